@@ -35,6 +35,21 @@ func TestCommandsExist(t *testing.T) {
 			args:     []string{"compare", "--help"},
 			expected: "Compare objects between two MinIO buckets",
 		},
+		{
+			name:     "abort-uploads command exists",
+			args:     []string{"abort-uploads", "--help"},
+			expected: "Abort incomplete multipart uploads older than a threshold",
+		},
+		{
+			name:     "trend command exists",
+			args:     []string{"trend", "--help"},
+			expected: "Show growth rates of objects, bytes, versions and delete markers",
+		},
+		{
+			name:     "inventory command exists",
+			args:     []string{"inventory", "--help"},
+			expected: "Export every object version of a bucket as an S3-Inventory-compatible report",
+		},
 	}
 
 	for _, tt := range tests {
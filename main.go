@@ -3,14 +3,22 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
 	"github.com/spf13/cobra"
 
 	"github.com/liamdn8/mc-tool/pkg/analyze"
 	"github.com/liamdn8/mc-tool/pkg/client"
 	"github.com/liamdn8/mc-tool/pkg/compare"
 	"github.com/liamdn8/mc-tool/pkg/config"
+	"github.com/liamdn8/mc-tool/pkg/history"
+	"github.com/liamdn8/mc-tool/pkg/inventory"
 	"github.com/liamdn8/mc-tool/pkg/validation"
 )
 
@@ -21,9 +29,51 @@ var (
 	BuildTime = "unknown"
 
 	// Runtime flags
-	versionsMode bool
-	verbose      bool
-	insecure     bool
+	versionsMode         bool
+	verbose              bool
+	insecure             bool
+	dryRun               bool
+	cleanupDeleteMarkers bool
+	olderThan            string
+	reconcile            bool
+	simulateLifecycle    bool
+	lifecycleFile        string
+	lifecycleDate        string
+	costProfile          string
+	findDuplicates       bool
+	hashMultipart        bool
+	storageClasses       bool
+	saveHistory          bool
+	historyDir           string
+	quota                string
+	against              string
+	dumpLimit            int
+	dumpStartAfter       string
+	dumpState            string
+	dumpCSV              string
+	inventoryDir         string
+	inventoryFormat      string
+	inventoryChunkMB     int
+	inventoryEncryption  bool
+	inventoryReplication bool
+	inventoryTags        bool
+	keyHygiene           bool
+	checklistOutput      string
+	checklistFailOn      string
+	checklistBaseline    string
+	checklistFix         bool
+	checklistBackupFile  string
+	checklistRollback    string
+	assumeYes            bool
+	scanRetention        bool
+	minRetention         string
+	sampleSize           int
+	sampleReplication    bool
+	testEvents           bool
+	sampleEncryption     bool
+
+	// Buckets processed in parallel when analyzing or checking a whole alias
+	concurrency int
 )
 
 func main() {
@@ -49,48 +99,187 @@ func main() {
 		Short: "Compare two MinIO buckets or paths",
 		Long: `Compare objects between two MinIO buckets or paths.
 		
+Either side may be the manifest.json of an inventory report instead of an
+alias/bucket/path, to compare without listing that side.
+
 Examples:
   mc-tool compare alias1/bucket1 alias2/bucket2
   mc-tool compare alias1/bucket1/folder alias2/bucket2/folder
   mc-tool compare --versions alias1/bucket1 alias2/bucket2
-  mc-tool compare --insecure alias1/bucket1 alias2/bucket2`,
+  mc-tool compare --insecure alias1/bucket1 alias2/bucket2
+  mc-tool compare --versions inventory/manifest.json alias2/bucket2`,
 		Args: cobra.ExactArgs(2),
 		Run:  runCompare,
 	}
 
 	analyzeCmd := &cobra.Command{
-		Use:   "analyze <alias/bucket/path>",
+		Use:   "analyze <alias[/bucket/path] | manifest.json>",
 		Short: "Analyze MinIO bucket for object distribution",
 		Long: `Analyze a MinIO bucket for object distribution, versions, and incomplete uploads.
 
+Given only an alias, every bucket is analyzed and a consolidated report ranks
+buckets by noncurrent bytes, delete markers and incomplete uploads. Only
+--concurrency and --save-history apply in that mode; the per-bucket reports and
+cleanups are rejected.
+
+Given an inventory manifest.json (CSV or Parquet), the report is computed offline from the
+inventory files instead of listing the bucket.
+
+With --limit, --start-after, --state or --csv and no other report, only the
+detailed object dump is written, streamed page by page as the bucket is listed.
+With --csv -, the CSV goes to stdout and everything else to stderr.
+
 Examples:
   mc-tool analyze alias/bucket
+  mc-tool analyze alias
+  mc-tool analyze --concurrency 8 alias
+  mc-tool analyze --key-hygiene inventory/manifest.json
   mc-tool analyze --verbose alias/bucket/path
-  mc-tool analyze alias/bucket/specific/path`,
+  mc-tool analyze alias/bucket/specific/path
+  mc-tool analyze --cleanup-delete-markers --dry-run alias/bucket
+  mc-tool analyze --reconcile alias/bucket
+  mc-tool analyze --simulate-lifecycle --lifecycle-date 2025-01-01 alias/bucket
+  mc-tool analyze --lifecycle-file candidate.xml alias/bucket
+  mc-tool analyze --cost-profile pricing.json --lifecycle-file candidate.xml alias/bucket
+  mc-tool analyze --duplicates --hash-multipart alias/bucket
+  mc-tool analyze --storage-classes alias/bucket
+  mc-tool analyze --key-hygiene alias/bucket
+  mc-tool analyze --save-history alias/bucket
+  mc-tool analyze --against alias2/bucket alias1/bucket
+  mc-tool analyze --verbose --limit 1000 --start-after logs/2024/ alias/bucket
+  mc-tool analyze --state delete-markers --csv markers.csv alias/bucket`,
 		Args: cobra.ExactArgs(1),
 		Run:  runAnalyze,
 	}
 
 	checklistCmd := &cobra.Command{
-		Use:   "checklist <alias/bucket>",
+		Use:   "checklist <alias/bucket | alias>",
 		Short: "Check bucket configuration including event settings and lifecycle",
 		Long: `Perform comprehensive validation of MinIO bucket configuration.
 
 Checks include:
 - Bucket existence
 - Versioning configuration
-- Event notifications (Lambda, Topic, Queue)
-- Object lifecycle policies
-- Server-side encryption
+- Event notifications (each target's ARN, events and filters, and overlapping
+  configurations that deliver an event twice)
+- Object lifecycle rules (disabled or conflicting rules, noncurrent version and
+  delete marker expiration, transition tiers, noncurrent expirations within object
+  lock retention)
+- Server-side encryption (default algorithm and KMS key, and whether the key is
+  usable when admin credentials are available)
 - Bucket policies and security settings
+- Object lock and default retention
+- Replication rules (destination and, with admin credentials, its remote target,
+  delete and existing-object replication, priorities)
+
+Each check reports pass, warn, fail or skip with a severity and a remediation
+hint. With --fail-on, the command exits with status 2 when a check warns or
+fails, so it can gate deployment pipelines. A missing bucket always exits with
+status 1.
+
+With --baseline, the bucket is also evaluated against a YAML or JSON baseline
+that lists required settings per bucket name pattern (versioning, encryption
+algorithm and KMS key, lifecycle limits, no public policy). Unmet requirements
+are reported as failures with the severity given in the baseline.
+
+With --fix, the remediation plan for fixable warnings and failures (versioning,
+default encryption, an AbortIncompleteMultipartUpload rule, anonymous policy
+statements granting writes, or all of them when the baseline forbids public
+access) is shown and applied only with --yes. The previous configuration is
+saved to a backup file first, which --rollback restores. The bucket is then
+checked again without probes or object scans.
+
+With --scan-retention, up to --sample-size current objects are checked for
+retention and legal holds. Objects with less than --min-retention left (or the
+baseline's min_retention_days) fail the check. With --sample-replication, the
+replication status (PENDING, FAILED, COMPLETED, REPLICA) of sampled objects is
+counted; failed replicas fail the check. With --sample-encryption, sampled
+objects are counted per encryption type and KMS key, since default encryption
+does not cover objects written before it was enabled.
+
+With --test-events, a probe object matching each notification configuration's
+filters is written and deleted while listening to the bucket's events, to
+confirm the events actually fire. This writes to the bucket, so it needs a single
+bucket and is skipped for buckets with default retention or replication, where the
+probes would be kept or copied.
+
+Given only an alias, every bucket is checked (--concurrency at a time) and a
+compliance matrix of buckets and checks is written as text, csv or html
+(--output). Each bucket and check gets a compliance score: the share of its
+evaluated (not skipped) checks that passed. With --fail-on, the command exits
+with status 2 when any bucket fails.
 
 Examples:
   mc-tool checklist alias/bucket
-  mc-tool checklist --verbose alias/bucket`,
+  mc-tool checklist --verbose alias/bucket
+  mc-tool checklist --output json alias/bucket
+  mc-tool checklist --output junit --fail-on fail alias/bucket > checklist.xml
+  mc-tool checklist --output sarif --fail-on warn alias/bucket > checklist.sarif
+  mc-tool checklist --baseline prod-baseline.yaml --fail-on fail alias/prod-data
+  mc-tool checklist --fix --dry-run alias/bucket
+  mc-tool checklist --fix --yes --backup-file bucket-backup.json alias/bucket
+  mc-tool checklist --rollback bucket-backup.json --yes alias/bucket
+  mc-tool checklist --scan-retention --min-retention 365d alias/compliance-bucket
+  mc-tool checklist --sample-replication --sample-size 500 alias/replicated-bucket
+  mc-tool checklist --test-events alias/bucket
+  mc-tool checklist --sample-encryption --sample-size 500 alias/bucket
+  mc-tool checklist alias
+  mc-tool checklist --baseline prod-baseline.yaml --output html alias > compliance.html`,
 		Args: cobra.ExactArgs(1),
 		Run:  runChecklist,
 	}
 
+	trendCmd := &cobra.Command{
+		Use:   "trend <alias/bucket/path>",
+		Short: "Show growth trends from saved analyze history",
+		Long: `Show growth rates of objects, bytes, versions and delete markers recorded by
+analyze --save-history, and forecast when a quota would be exceeded.
+
+Examples:
+  mc-tool trend alias/bucket
+  mc-tool trend --quota 500GiB alias/bucket
+  mc-tool trend --history-dir /var/lib/mc-tool alias/bucket/path`,
+		Args: cobra.ExactArgs(1),
+		Run:  runTrend,
+	}
+
+	inventoryCmd := &cobra.Command{
+		Use:   "inventory <alias/bucket/path>",
+		Short: "Export an S3-Inventory-compatible listing of a bucket",
+		Long: `Export every object version of a bucket as an S3-Inventory-compatible report:
+gzip-compressed CSV or Parquet data files chunked by size plus a manifest.json.
+
+Encryption status, replication status and tags are optional because they need
+one request per object version. Versions deleted during the export are left
+out; versions whose optional fields cannot be read are written without them and
+listed at the end. If the listing fails, the partial data files are removed.
+
+Examples:
+  mc-tool inventory --output-dir ./inventory alias/bucket
+  mc-tool inventory --output-dir ./inventory --encryption --replication alias/bucket/path
+  mc-tool inventory --output-dir ./inventory --tags --chunk-size-mb 64 alias/bucket
+  mc-tool inventory --output-dir ./inventory --format parquet alias/bucket
+
+Query the result in DuckDB:
+  SELECT * FROM read_csv('inventory/data/*.csv.gz', header = false);
+  SELECT * FROM read_parquet('inventory/data/*.parquet');`,
+		Args: cobra.ExactArgs(1),
+		Run:  runInventory,
+	}
+
+	abortUploadsCmd := &cobra.Command{
+		Use:   "abort-uploads <alias/bucket/path>",
+		Short: "Abort stale incomplete multipart uploads",
+		Long: `Abort incomplete multipart uploads older than a threshold and report the part bytes reclaimed.
+
+Examples:
+  mc-tool abort-uploads --dry-run alias/bucket
+  mc-tool abort-uploads --older-than 7d alias/bucket/path
+  mc-tool abort-uploads --older-than 36h alias/bucket`,
+		Args: cobra.ExactArgs(1),
+		Run:  runAbortUploads,
+	}
+
 	// Configure flags
 	compareCmd.Flags().BoolVar(&versionsMode, "versions", false, "Compare all object versions (default: compare current versions only)")
 	compareCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
@@ -98,14 +287,68 @@ Examples:
 
 	analyzeCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	analyzeCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (overrides config setting)")
+	analyzeCmd.Flags().BoolVar(&cleanupDeleteMarkers, "cleanup-delete-markers", false, "Remove expired and stacked delete markers")
+	analyzeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without removing anything")
+	analyzeCmd.Flags().BoolVar(&reconcile, "reconcile", false, "Reconcile listing totals against MinIO data usage (requires admin credentials)")
+	analyzeCmd.Flags().BoolVar(&simulateLifecycle, "simulate-lifecycle", false, "Simulate what the bucket's lifecycle rules would expire or transition")
+	analyzeCmd.Flags().StringVar(&lifecycleFile, "lifecycle-file", "", "Simulate a candidate lifecycle configuration (XML or JSON) instead of the bucket's")
+	analyzeCmd.Flags().StringVar(&lifecycleDate, "lifecycle-date", "", "Date to simulate lifecycle rules on (YYYY-MM-DD, default: today)")
+	analyzeCmd.Flags().StringVar(&costProfile, "cost-profile", "", "Estimate monthly storage cost using a pricing profile (JSON)")
+	analyzeCmd.Flags().BoolVar(&findDuplicates, "duplicates", false, "Report current objects with duplicate content")
+	analyzeCmd.Flags().BoolVar(&hashMultipart, "hash-multipart", false, "Hash the content of multipart objects for duplicate detection (downloads them)")
+	analyzeCmd.Flags().BoolVar(&storageClasses, "storage-classes", false, "Break down objects by storage class and check tiering against lifecycle rules")
+	analyzeCmd.Flags().IntVar(&concurrency, "concurrency", 4, "Number of buckets analyzed in parallel when analyzing a whole alias")
+	analyzeCmd.Flags().BoolVar(&keyHygiene, "key-hygiene", false, "Flag problematic key names (encoding, slashes, spaces, length, case collisions)")
+	analyzeCmd.Flags().IntVar(&dumpLimit, "limit", 0, "Maximum number of keys in the detailed object dump (0: no limit)")
+	analyzeCmd.Flags().StringVar(&dumpStartAfter, "start-after", "", "Start the detailed object dump after this key")
+	analyzeCmd.Flags().StringVar(&dumpState, "state", analyze.DumpAll, "Only dump versions in this state (all, current, noncurrent, delete-markers)")
+	analyzeCmd.Flags().StringVar(&dumpCSV, "csv", "", "Write the detailed object dump as CSV to this file ('-' for stdout)")
+	analyzeCmd.Flags().StringVar(&against, "against", "", "Explain the object-count and size difference against another alias/bucket/path")
+	analyzeCmd.Flags().BoolVar(&saveHistory, "save-history", false, "Save the analysis summary to the history store for trend reports")
+	analyzeCmd.Flags().StringVar(&historyDir, "history-dir", "", "History store directory (default: ~/.mc-tool/history)")
+
+	trendCmd.Flags().StringVar(&historyDir, "history-dir", "", "History store directory (default: ~/.mc-tool/history)")
+	trendCmd.Flags().StringVar(&quota, "quota", "", "Forecast when total bytes reach this quota (e.g. 500GiB, 2TB)")
+
+	abortUploadsCmd.Flags().StringVar(&olderThan, "older-than", "7d", "Only abort uploads initiated longer ago than this age (e.g. 7d, 2w, 36h)")
+	abortUploadsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be aborted without aborting anything")
+	abortUploadsCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	abortUploadsCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (overrides config setting)")
+
+	inventoryCmd.Flags().StringVar(&inventoryDir, "output-dir", "", "Directory to write the manifest and data files to (required)")
+	inventoryCmd.Flags().StringVar(&inventoryFormat, "format", "csv", "Output format (csv, parquet)")
+	inventoryCmd.Flags().IntVar(&inventoryChunkMB, "chunk-size-mb", inventory.DefaultChunkSize>>20, "Start a new data file after this many uncompressed MiB")
+	inventoryCmd.Flags().BoolVar(&inventoryEncryption, "encryption", false, "Include each version's encryption status")
+	inventoryCmd.Flags().BoolVar(&inventoryReplication, "replication", false, "Include each version's replication status")
+	inventoryCmd.Flags().BoolVar(&inventoryTags, "tags", false, "Include each version's tags")
+	inventoryCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (overrides config setting)")
+	inventoryCmd.MarkFlagRequired("output-dir")
 
 	checklistCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	checklistCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (overrides config setting)")
+	checklistCmd.Flags().StringVarP(&checklistOutput, "output", "o", validation.OutputText, "Output format (text, json, yaml, junit, sarif; text, csv or html for an alias)")
+	checklistCmd.Flags().StringVar(&checklistFailOn, "fail-on", "", "Exit with status 2 when a check reaches this status (warn, fail)")
+	checklistCmd.Flags().StringVar(&checklistBaseline, "baseline", "", "YAML or JSON baseline of required settings per bucket pattern")
+	checklistCmd.Flags().BoolVar(&checklistFix, "fix", false, "Plan and apply remediations for fixable warnings and failures")
+	checklistCmd.Flags().BoolVar(&assumeYes, "yes", false, "Apply --fix or --rollback changes without stopping at the plan")
+	checklistCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the --fix or --rollback plan without changing anything")
+	checklistCmd.Flags().StringVar(&checklistBackupFile, "backup-file", "", "Where --fix saves the previous configuration (default checklist-backup-<bucket>-<time>.json)")
+	checklistCmd.Flags().StringVar(&checklistRollback, "rollback", "", "Restore the configuration saved in a --fix backup file")
+	checklistCmd.Flags().BoolVar(&scanRetention, "scan-retention", false, "Scan objects for retention and legal holds")
+	checklistCmd.Flags().StringVar(&minRetention, "min-retention", "", "Retention every scanned object must have left (e.g. 30d, 52w)")
+	checklistCmd.Flags().IntVar(&concurrency, "concurrency", 4, "Number of buckets checked in parallel when checking a whole alias")
+	checklistCmd.Flags().BoolVar(&testEvents, "test-events", false, "Write and delete probe objects to confirm notification events fire")
+	checklistCmd.Flags().BoolVar(&sampleEncryption, "sample-encryption", false, "Sample how objects are encrypted")
+	checklistCmd.Flags().BoolVar(&sampleReplication, "sample-replication", false, "Sample objects' replication status")
+	checklistCmd.Flags().IntVar(&sampleSize, "sample-size", 1000, "Maximum number of objects inspected by object scans (0 for all)")
 
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(compareCmd)
 	rootCmd.AddCommand(analyzeCmd)
 	rootCmd.AddCommand(checklistCmd)
+	rootCmd.AddCommand(abortUploadsCmd)
+	rootCmd.AddCommand(trendCmd)
+	rootCmd.AddCommand(inventoryCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
@@ -116,47 +359,72 @@ func runCompare(cmd *cobra.Command, args []string) {
 	sourceURL := args[0]
 	targetURL := args[1]
 
-	// Parse source and target URLs
-	sourceAlias, sourceBucket, sourcePath, err := client.ParseURL(sourceURL)
+	ctx := context.Background()
+
+	// Get objects from each side, listed live or read from an inventory report
+	sourceObjects, err := loadObjects(ctx, sourceURL)
 	if err != nil {
-		log.Fatalf("Error parsing source URL: %v", err)
+		log.Fatalf("Error loading source objects: %v", err)
 	}
 
-	targetAlias, targetBucket, targetPath, err := client.ParseURL(targetURL)
+	targetObjects, err := loadObjects(ctx, targetURL)
 	if err != nil {
-		log.Fatalf("Error parsing target URL: %v", err)
+		log.Fatalf("Error loading target objects: %v", err)
 	}
 
-	// Load MC configuration
-	cfg, err := config.LoadMCConfig()
-	if err != nil {
-		log.Fatalf("Error loading MC configuration: %v", err)
+	// Perform comparison
+	results := compare.CompareObjectLists(sourceObjects, targetObjects, versionsMode)
+
+	// Display results
+	compare.DisplayResults(results, verbose)
+}
+
+// loadObjects returns every version under an alias/bucket/path, or listed by an inventory manifest
+func loadObjects(ctx context.Context, source string) ([]*compare.ObjectInfo, error) {
+	if inventory.IsManifestPath(source) {
+		objects, _, err := inventory.ReadObjects(source)
+		return objects, err
 	}
 
-	// Create MinIO clients
-	sourceClient, err := client.CreateMinIOClient(cfg, sourceAlias, insecure, verbose)
+	alias, bucket, path, err := client.ParseURL(source)
 	if err != nil {
-		log.Fatalf("Error creating source client: %v", err)
+		return nil, err
 	}
 
-	targetClient, err := client.CreateMinIOClient(cfg, targetAlias, insecure, verbose)
+	cfg, err := config.LoadMCConfig()
 	if err != nil {
-		log.Fatalf("Error creating target client: %v", err)
+		return nil, err
 	}
 
-	// Perform comparison
-	results, err := compare.CompareObjects(sourceClient, targetClient, sourceBucket, sourcePath, targetBucket, targetPath, versionsMode)
+	minioClient, err := client.CreateMinIOClient(cfg, alias, insecure, verbose)
 	if err != nil {
-		log.Fatalf("Error comparing objects: %v", err)
+		return nil, err
 	}
 
-	// Display results
-	compare.DisplayResults(results, verbose)
+	return compare.ListObjects(ctx, minioClient, bucket, path)
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) {
 	url := args[0]
 
+	// CSV written to stdout must not be mixed with the report, so the report goes to stderr
+	var report io.Writer = os.Stdout
+	if dumpCSV == "-" {
+		report = os.Stderr
+	}
+
+	// An inventory manifest is analyzed offline
+	if inventory.IsManifestPath(url) {
+		runAnalyzeInventory(cmd, report, url)
+		return
+	}
+
+	// An alias without a bucket analyzes every bucket
+	if !strings.Contains(strings.TrimSuffix(url, "/"), "/") {
+		runAnalyzeAlias(cmd, report, strings.TrimSuffix(url, "/"))
+		return
+	}
+
 	// Parse URL
 	alias, bucket, path, err := client.ParseURL(url)
 	if err != nil {
@@ -177,6 +445,21 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 
 	ctx := context.Background()
 
+	// The dump is streamed as the bucket is listed, without keeping a copy of every version
+	var lister *client.VersionLister
+	if dumpRequested(cmd) {
+		lister, err = client.CreateVersionLister(cfg, alias, insecure)
+		if err != nil {
+			log.Fatalf("Error creating MinIO client: %v", err)
+		}
+	}
+
+	// A dump on its own needs no listing for the report
+	if dumpOnly(cmd) {
+		runObjectDump(ctx, report, lister, bucket, path, nil)
+		return
+	}
+
 	// Get all objects (including all versions and delete markers)
 	objects, err := compare.ListObjects(ctx, minioClient, bucket, path)
 	if err != nil {
@@ -189,16 +472,609 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 		log.Fatalf("Error listing incomplete uploads: %v", err)
 	}
 
+	// Measure the parts already stored by each incomplete upload
+	uploadUsages, err := analyze.MeasureIncompleteUploads(ctx, minioClient, bucket, incompleteUploads)
+	if err != nil {
+		log.Fatalf("Error listing incomplete upload parts: %v", err)
+	}
+
 	// Analyze object distribution
 	stats := analyze.AnalyzeObjectDistribution(objects)
 
 	// Display analysis results
-	analyze.DisplayAnalysisResults(stats, incompleteUploads, objects, verbose)
+	analyze.DisplayAnalysisResults(report, stats, uploadUsages, path, verbose)
+
+	// Dump individual versions when asked for details
+	if lister != nil {
+		runObjectDump(ctx, report, lister, bucket, path, nil)
+	}
+
+	if saveHistory {
+		record := history.NewRecord(alias, path, analyze.SummarizeBucket(bucket, objects, uploadUsages), time.Now())
+		saveHistoryRecords(report, record)
+	}
+
+	if against != "" {
+		runAnalyzeAgainst(ctx, report, url, path, objects, uploadUsages)
+	}
+
+	// Report delete markers that no longer hide any data
+	markerReport := analyze.FindOrphanedDeleteMarkers(objects, path)
+	analyze.DisplayDeleteMarkerReport(report, markerReport, verbose)
+
+	if findDuplicates {
+		var hashes map[*compare.ObjectInfo]string
+		if hashMultipart {
+			hashes, err = analyze.HashMultipartObjects(ctx, minioClient, bucket, objects)
+			if err != nil {
+				log.Fatalf("Error hashing multipart objects: %v", err)
+			}
+		}
+
+		duplicates := analyze.FindDuplicates(objects, path, hashes)
+		analyze.DisplayDuplicateReport(report, duplicates, verbose)
+	}
+
+	if keyHygiene {
+		analyze.DisplayKeyHygieneReport(report, analyze.AnalyzeKeyHygiene(objects), verbose)
+	}
+
+	if storageClasses {
+		// Without a lifecycle configuration only the breakdown is reported
+		bucketLifecycle, err := minioClient.GetBucketLifecycle(ctx, bucket)
+		if err != nil {
+			bucketLifecycle = nil
+		}
+
+		classReport := analyze.AnalyzeStorageClasses(objects, path, bucketLifecycle, time.Now().UTC())
+		analyze.DisplayStorageClassReport(report, classReport, verbose)
+	}
+
+	var simulation *analyze.LifecycleSimulation
+	if simulateLifecycle || lifecycleFile != "" {
+		simulation = runLifecycleSimulation(ctx, report, minioClient, bucket, objects)
+	}
+
+	if costProfile != "" {
+		var versioning string
+		if simulation != nil {
+			config, err := minioClient.GetBucketVersioning(ctx, bucket)
+			if err != nil {
+				log.Fatalf("Error getting bucket versioning: %v", err)
+			}
+			versioning = config.Status
+		}
+		runCostEstimate(report, objects, uploadUsages, path, simulation, versioning)
+	}
+
+	if reconcile {
+		adminClient, err := client.CreateAdminClient(cfg, alias, insecure)
+		if err != nil {
+			log.Fatalf("Error creating admin client: %v", err)
+		}
+
+		usage, err := adminClient.DataUsageInfo(ctx)
+		if err != nil {
+			log.Fatalf("Error fetching data usage: %v", err)
+		}
+
+		bucketUsage, ok := usage.BucketsUsage[bucket]
+		if !ok {
+			fmt.Fprintf(report, "\n⚠ MinIO data usage has no entry for bucket %s yet (scanner may not have run)\n", bucket)
+		} else {
+			reconciliation := analyze.ReconcileUsage(objects, uploadUsages, bucketUsage, usage.LastUpdate)
+			analyze.DisplayUsageReconciliation(report, reconciliation, path)
+		}
+	}
+
+	if cleanupDeleteMarkers && markerReport.Total() > 0 {
+		fmt.Fprintln(report)
+		removed, err := analyze.RemoveDeleteMarkers(ctx, report, minioClient, bucket, markerReport.Markers(), dryRun)
+		if err != nil {
+			log.Fatalf("Error removing delete markers: %v", err)
+		}
+		if dryRun {
+			fmt.Fprintf(report, "\nDry run: %d delete markers would be removed\n", removed)
+		} else {
+			fmt.Fprintf(report, "\nRemoved %d delete markers\n", removed)
+		}
+	}
+}
+
+func runAnalyzeInventory(cmd *cobra.Command, report io.Writer, manifestPath string) {
+	// These act on or query the live bucket
+	for _, name := range []string{"cleanup-delete-markers", "reconcile", "hash-multipart", "save-history"} {
+		if cmd.Flags().Changed(name) {
+			log.Fatalf("Error: --%s needs a live bucket and cannot be used with an inventory manifest", name)
+		}
+	}
+	if simulateLifecycle && lifecycleFile == "" {
+		log.Fatalf("Error: --simulate-lifecycle needs --lifecycle-file when analyzing an inventory manifest")
+	}
+
+	objects, manifest, err := inventory.ReadObjects(manifestPath)
+	if err != nil {
+		log.Fatalf("Error reading inventory: %v", err)
+	}
+
+	ctx := context.Background()
+
+	fmt.Fprintf(report, "Inventory of bucket %s: %d versions in %d data files\n", manifest.SourceBucket, len(objects), len(manifest.Files))
+	fmt.Fprintln(report, "ℹ Inventory reports do not include incomplete multipart uploads")
+	fmt.Fprintln(report)
+
+	if dumpOnly(cmd) {
+		runObjectDump(ctx, report, nil, manifest.SourceBucket, "", objects)
+		return
+	}
+
+	stats := analyze.AnalyzeObjectDistribution(objects)
+	analyze.DisplayAnalysisResults(report, stats, nil, "", verbose)
+
+	if dumpRequested(cmd) {
+		runObjectDump(ctx, report, nil, manifest.SourceBucket, "", objects)
+	}
+
+	if against != "" {
+		runAnalyzeAgainst(ctx, report, manifestPath, "", objects, nil)
+	}
+
+	analyze.DisplayDeleteMarkerReport(report, analyze.FindOrphanedDeleteMarkers(objects, ""), verbose)
+
+	if findDuplicates {
+		analyze.DisplayDuplicateReport(report, analyze.FindDuplicates(objects, "", nil), verbose)
+	}
+
+	if keyHygiene {
+		analyze.DisplayKeyHygieneReport(report, analyze.AnalyzeKeyHygiene(objects), verbose)
+	}
+
+	if storageClasses {
+		// Tiering can only be checked against a lifecycle file offline
+		var lifecycleConfig *lifecycle.Configuration
+		if lifecycleFile != "" {
+			lifecycleConfig, err = analyze.LoadLifecycleConfig(lifecycleFile)
+			if err != nil {
+				log.Fatalf("Error loading lifecycle file: %v", err)
+			}
+		}
+
+		classReport := analyze.AnalyzeStorageClasses(objects, "", lifecycleConfig, time.Now().UTC())
+		analyze.DisplayStorageClassReport(report, classReport, verbose)
+	}
+
+	var simulation *analyze.LifecycleSimulation
+	if lifecycleFile != "" {
+		simulation = runLifecycleSimulation(ctx, report, nil, manifest.SourceBucket, objects)
+	}
+
+	if costProfile != "" {
+		runCostEstimate(report, objects, nil, "", simulation, inventoryVersioning(objects))
+	}
+}
+
+// analyzeReportFlags select the optional reports and actions of analyze on a bucket
+var analyzeReportFlags = []string{"cleanup-delete-markers", "dry-run", "reconcile", "simulate-lifecycle", "lifecycle-file",
+	"lifecycle-date", "cost-profile", "duplicates", "hash-multipart", "storage-classes", "key-hygiene", "against"}
+
+// analyzeDumpFlags select and format the detailed object dump
+var analyzeDumpFlags = []string{"limit", "start-after", "state", "csv"}
+
+// dumpRequested reports whether the detailed object dump was asked for
+func dumpRequested(cmd *cobra.Command) bool {
+	requested := verbose
+	for _, name := range analyzeDumpFlags {
+		requested = requested || cmd.Flags().Changed(name)
+	}
+	return requested
+}
+
+// dumpOnly reports whether a dump flag was given without any report, so that only the
+// dump is written
+func dumpOnly(cmd *cobra.Command) bool {
+	requested := false
+	for _, name := range analyzeDumpFlags {
+		requested = requested || cmd.Flags().Changed(name)
+	}
+	for _, name := range append([]string{"save-history"}, analyzeReportFlags...) {
+		if cmd.Flags().Changed(name) {
+			return false
+		}
+	}
+	return requested
+}
+
+func runCostEstimate(w io.Writer, objects []*compare.ObjectInfo, uploadUsages []analyze.UploadUsage, path string, simulation *analyze.LifecycleSimulation, versioning string) {
+	profile, err := analyze.LoadPricingProfile(costProfile)
+	if err != nil {
+		log.Fatalf("Error loading cost profile: %v", err)
+	}
+
+	estimate := analyze.EstimateStorageCost(profile, objects, uploadUsages, path)
+	var savings *analyze.LifecycleSavings
+	if simulation != nil {
+		savings = analyze.EstimateLifecycleSavings(profile, simulation, versioning)
+	}
+	analyze.DisplayCostEstimate(w, estimate, savings)
+}
+
+// inventoryVersioning guesses the versioning status of an inventoried bucket, which is
+// versioned when any listed version has a version ID
+func inventoryVersioning(objects []*compare.ObjectInfo) string {
+	for _, obj := range objects {
+		if obj.VersionID != "" && obj.VersionID != "null" {
+			return "Enabled"
+		}
+	}
+	return ""
+}
+
+// runObjectDump streams the listing of a live bucket, or dumps objects when lister is nil.
+// The text dump and its notes go to the report; --csv - writes to standard output.
+func runObjectDump(ctx context.Context, report io.Writer, lister *client.VersionLister, bucket, path string, objects []*compare.ObjectInfo) {
+	state, err := analyze.ParseDumpState(dumpState)
+	if err != nil {
+		log.Fatalf("Error parsing --state: %v", err)
+	}
+
+	opts := analyze.DumpOptions{
+		Limit:      dumpLimit,
+		StartAfter: dumpStartAfter,
+		State:      state,
+		CSV:        dumpCSV != "",
+	}
+
+	out := report
+	if dumpCSV == "-" {
+		out = os.Stdout
+	} else if dumpCSV != "" {
+		file, err := os.Create(dumpCSV)
+		if err != nil {
+			log.Fatalf("Error creating CSV file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if !opts.CSV {
+		fmt.Fprintln(report, "\nDetailed Object Analysis:")
+		fmt.Fprintln(report, "========================")
+	}
+
+	var summary *analyze.DumpSummary
+	if lister != nil {
+		summary, err = analyze.StreamObjectDump(ctx, lister, bucket, path, opts, out)
+	} else {
+		summary, err = analyze.DumpObjects(objects, opts, out)
+	}
+	if err != nil {
+		log.Fatalf("Error dumping objects: %v", err)
+	}
+
+	if dumpCSV != "" && dumpCSV != "-" {
+		fmt.Fprintf(report, "\nℹ Wrote %d versions of %d keys to %s\n", summary.Entries, summary.Keys, dumpCSV)
+	}
+	if summary.Truncated {
+		fmt.Fprintf(report, "\nℹ More keys follow; continue with --start-after %q\n", summary.LastKey)
+	}
+}
+
+func runAnalyzeAgainst(ctx context.Context, report io.Writer, url, path string, objects []*compare.ObjectInfo, uploadUsages []analyze.UploadUsage) {
+	if inventory.IsManifestPath(against) {
+		// Inventory reports carry no incomplete uploads
+		targetObjects, _, err := inventory.ReadObjects(against)
+		if err != nil {
+			log.Fatalf("Error reading inventory %s: %v", against, err)
+		}
+		displayAnalysisDiff(report, url, path, objects, uploadUsages, against, "", targetObjects, nil)
+		return
+	}
+
+	targetAlias, targetBucket, targetPath, err := client.ParseURL(against)
+	if err != nil {
+		log.Fatalf("Error parsing --against URL: %v", err)
+	}
+
+	cfg, err := config.LoadMCConfig()
+	if err != nil {
+		log.Fatalf("Error loading MC config: %v", err)
+	}
+
+	targetClient, err := client.CreateMinIOClient(cfg, targetAlias, insecure, verbose)
+	if err != nil {
+		log.Fatalf("Error creating MinIO client for %s: %v", targetAlias, err)
+	}
+
+	targetObjects, err := compare.ListObjects(ctx, targetClient, targetBucket, targetPath)
+	if err != nil {
+		log.Fatalf("Error listing objects of %s: %v", against, err)
+	}
+
+	targetUploads, err := analyze.ListIncompleteUploads(ctx, targetClient, targetBucket, targetPath)
+	if err != nil {
+		log.Fatalf("Error listing incomplete uploads of %s: %v", against, err)
+	}
+
+	targetUsages, err := analyze.MeasureIncompleteUploads(ctx, targetClient, targetBucket, targetUploads)
+	if err != nil {
+		log.Fatalf("Error listing incomplete upload parts of %s: %v", against, err)
+	}
+
+	displayAnalysisDiff(report, url, path, objects, uploadUsages, against, targetPath, targetObjects, targetUsages)
+}
+
+func displayAnalysisDiff(w io.Writer, source, sourcePath string, sourceObjects []*compare.ObjectInfo, sourceUsages []analyze.UploadUsage,
+	target, targetPath string, targetObjects []*compare.ObjectInfo, targetUsages []analyze.UploadUsage) {
+	diff := analyze.DiffAnalyses(sourceObjects, targetObjects, sourceUsages, targetUsages, sourcePath, targetPath)
+	diff.Source = source
+	diff.Target = target
+	analyze.DisplayAnalysisDiff(w, diff, verbose)
+}
+
+func runAnalyzeAlias(cmd *cobra.Command, report io.Writer, alias string) {
+	// These report on or act on a single bucket
+	for _, name := range append(append([]string{}, analyzeReportFlags...), analyzeDumpFlags...) {
+		if cmd.Flags().Changed(name) {
+			log.Fatalf("Error: --%s needs a bucket and cannot be used when analyzing a whole alias; use %s/<bucket>", name, alias)
+		}
+	}
+
+	// Load MinIO configuration
+	cfg, err := config.LoadMCConfig()
+	if err != nil {
+		log.Fatalf("Error loading MC config: %v", err)
+	}
+
+	// Create MinIO client
+	minioClient, err := client.CreateMinIOClient(cfg, alias, insecure, verbose)
+	if err != nil {
+		log.Fatalf("Error creating MinIO client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	buckets, err := minioClient.ListBuckets(ctx)
+	if err != nil {
+		log.Fatalf("Error listing buckets: %v", err)
+	}
+
+	names := make([]string, len(buckets))
+	for i, bucket := range buckets {
+		names[i] = bucket.Name
+	}
+
+	summaries := analyze.AnalyzeBuckets(ctx, minioClient, names, concurrency)
+	analyze.DisplayBucketReport(report, alias, summaries)
+
+	if saveHistory {
+		now := time.Now()
+		var records []history.Record
+		for _, summary := range summaries {
+			if summary.Err == nil {
+				records = append(records, history.NewRecord(alias, "", summary, now))
+			}
+		}
+		saveHistoryRecords(report, records...)
+	}
+}
+
+func saveHistoryRecords(w io.Writer, records ...history.Record) {
+	store, err := history.OpenStore(historyDir)
+	if err != nil {
+		log.Fatalf("Error opening history store: %v", err)
+	}
+
+	for _, record := range records {
+		if err := store.Append(record); err != nil {
+			log.Fatalf("Error saving history: %v", err)
+		}
+	}
+
+	fmt.Fprintf(w, "\nℹ Saved %d history records to %s\n", len(records), store.Dir())
+}
+
+func runTrend(cmd *cobra.Command, args []string) {
+	url := args[0]
+
+	// Parse URL
+	alias, bucket, path, err := client.ParseURL(url)
+	if err != nil {
+		log.Fatalf("Error parsing URL: %v", err)
+	}
+
+	var quotaBytes int64
+	if quota != "" {
+		quotaBytes, err = history.ParseSize(quota)
+		if err != nil {
+			log.Fatalf("Error parsing --quota: %v", err)
+		}
+	}
+
+	store, err := history.OpenStore(historyDir)
+	if err != nil {
+		log.Fatalf("Error opening history store: %v", err)
+	}
+
+	records, err := store.Load(alias, bucket, path)
+	if err != nil {
+		log.Fatalf("Error loading history: %v", err)
+	}
+
+	history.DisplayTrend(os.Stdout, url, history.ComputeTrend(records), quotaBytes)
+}
+
+func runLifecycleSimulation(ctx context.Context, report io.Writer, minioClient *minio.Client, bucket string, objects []*compare.ObjectInfo) *analyze.LifecycleSimulation {
+	at := time.Now().UTC()
+	if lifecycleDate != "" {
+		parsed, err := time.Parse("2006-01-02", lifecycleDate)
+		if err != nil {
+			log.Fatalf("Error parsing --lifecycle-date: %v", err)
+		}
+		at = parsed
+	}
+
+	var lifecycleConfig *lifecycle.Configuration
+	if lifecycleFile != "" {
+		candidate, err := analyze.LoadLifecycleConfig(lifecycleFile)
+		if err != nil {
+			log.Fatalf("Error loading lifecycle file: %v", err)
+		}
+		fmt.Fprintf(report, "\nSimulating candidate lifecycle configuration from %s\n", lifecycleFile)
+		lifecycleConfig = candidate
+	} else {
+		current, err := minioClient.GetBucketLifecycle(ctx, bucket)
+		if err != nil {
+			fmt.Fprintln(report, "\n➖ Lifecycle Simulation: No lifecycle configuration on bucket")
+			return nil
+		}
+		lifecycleConfig = current
+	}
+
+	simulation := analyze.SimulateLifecycle(lifecycleConfig, objects, at)
+	analyze.DisplayLifecycleSimulation(report, simulation)
+
+	return simulation
+}
+
+func runAbortUploads(cmd *cobra.Command, args []string) {
+	url := args[0]
+
+	age, err := analyze.ParseAge(olderThan)
+	if err != nil {
+		log.Fatalf("Error parsing --older-than: %v", err)
+	}
+
+	// Parse URL
+	alias, bucket, path, err := client.ParseURL(url)
+	if err != nil {
+		log.Fatalf("Error parsing URL: %v", err)
+	}
+
+	// Load MinIO configuration
+	cfg, err := config.LoadMCConfig()
+	if err != nil {
+		log.Fatalf("Error loading MC config: %v", err)
+	}
+
+	// Create MinIO client
+	minioClient, err := client.CreateMinIOClient(cfg, alias, insecure, verbose)
+	if err != nil {
+		log.Fatalf("Error creating MinIO client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	uploads, err := analyze.ListIncompleteUploads(ctx, minioClient, bucket, path)
+	if err != nil {
+		log.Fatalf("Error listing incomplete uploads: %v", err)
+	}
+
+	stale := analyze.FilterUploadsOlderThan(uploads, time.Now().Add(-age))
+	fmt.Printf("Incomplete uploads: %d total, %d older than %s\n", len(uploads), len(stale), olderThan)
+	if len(stale) == 0 {
+		return
+	}
+
+	// Measure part usage before aborting so reclaimed bytes can be reported
+	usages, err := analyze.MeasureIncompleteUploads(ctx, minioClient, bucket, stale)
+	if err != nil {
+		log.Fatalf("Error listing upload parts: %v", err)
+	}
+
+	fmt.Println()
+	aborted, reclaimed, err := analyze.AbortIncompleteUploads(ctx, minioClient, bucket, usages, dryRun)
+	if err != nil {
+		log.Fatalf("Error aborting uploads: %v", err)
+	}
+
+	if dryRun {
+		fmt.Printf("\nDry run: %d uploads would be aborted, reclaiming %d bytes\n", aborted, reclaimed)
+	} else {
+		fmt.Printf("\nAborted %d uploads, reclaimed %d bytes\n", aborted, reclaimed)
+	}
+}
+
+func runInventory(cmd *cobra.Command, args []string) {
+	url := args[0]
+
+	format, err := inventory.ParseFormat(inventoryFormat)
+	if err != nil {
+		log.Fatalf("Error parsing --format: %v", err)
+	}
+
+	// Parse URL
+	alias, bucket, path, err := client.ParseURL(url)
+	if err != nil {
+		log.Fatalf("Error parsing URL: %v", err)
+	}
+
+	// Load MinIO configuration
+	cfg, err := config.LoadMCConfig()
+	if err != nil {
+		log.Fatalf("Error loading MC config: %v", err)
+	}
+
+	// Create MinIO client
+	minioClient, err := client.CreateMinIOClient(cfg, alias, insecure, verbose)
+	if err != nil {
+		log.Fatalf("Error creating MinIO client: %v", err)
+	}
+
+	opts := inventory.Options{
+		Prefix:      path,
+		Format:      format,
+		ChunkSize:   int64(inventoryChunkMB) << 20,
+		Encryption:  inventoryEncryption,
+		Replication: inventoryReplication,
+		Tags:        inventoryTags,
+	}
+
+	summary, err := inventory.Export(context.Background(), minioClient, bucket, inventoryDir, opts)
+	if err != nil {
+		log.Fatalf("Error exporting inventory: %v", err)
+	}
+
+	fmt.Printf("✅ Wrote %d versions to %d data files in %s\n", summary.Records, len(summary.Manifest.Files), inventoryDir)
+	fmt.Printf("Fields: %s\n", strings.Join(opts.Fields(), ", "))
+	if summary.Vanished > 0 {
+		fmt.Printf("ℹ %d versions were deleted during the export and are not included\n", summary.Vanished)
+	}
+	if len(summary.Incomplete) > 0 {
+		fmt.Printf("⚠ %d versions were written without some optional fields:\n", len(summary.Incomplete))
+		for _, reason := range summary.Incomplete {
+			fmt.Printf("  %s\n", reason)
+		}
+	}
 }
 
 func runChecklist(cmd *cobra.Command, args []string) {
 	url := args[0]
 
+	failOn, err := validation.ParseFailOn(checklistFailOn)
+	if err != nil {
+		log.Fatalf("Error parsing --fail-on: %v", err)
+	}
+
+	// An alias without a bucket checks every bucket
+	if !strings.Contains(strings.TrimSuffix(url, "/"), "/") {
+		runChecklistAlias(strings.TrimSuffix(url, "/"), failOn)
+		return
+	}
+
+	format, err := validation.ParseOutputFormat(checklistOutput)
+	if err != nil {
+		log.Fatalf("Error parsing --output: %v", err)
+	}
+
+	if checklistFix && checklistRollback != "" {
+		log.Fatalf("Error: --fix and --rollback cannot be combined")
+	}
+	if (checklistFix || checklistRollback != "") && format != validation.OutputText {
+		log.Fatalf("Error: --fix and --rollback require text output")
+	}
+
+	opts := checklistOptions()
+
 	// Parse URL (only need alias and bucket for checklist)
 	alias, bucket, _, err := client.ParseURL(url)
 	if err != nil {
@@ -219,10 +1095,220 @@ func runChecklist(cmd *cobra.Command, args []string) {
 
 	ctx := context.Background()
 
+	if checklistRollback != "" {
+		runChecklistRollback(ctx, minioClient, bucket)
+		return
+	}
+
+	addAdminChecks(ctx, cfg, alias, &opts)
+
 	// Perform bucket configuration validation
-	fmt.Printf("=== Bucket Configuration Checklist ===\n")
-	err = validation.CheckBucketConfiguration(ctx, minioClient, bucket)
+	report, err := validation.CheckBucketConfiguration(ctx, minioClient, bucket, opts)
 	if err != nil {
 		log.Fatalf("Error checking bucket configuration: %v", err)
 	}
+
+	if format == validation.OutputText {
+		fmt.Printf("=== Bucket Configuration Checklist ===\n")
+	}
+	if err := validation.WriteReport(os.Stdout, report, format); err != nil {
+		log.Fatalf("Error writing report: %v", err)
+	}
+
+	// A missing bucket is an error whatever --fail-on says
+	if result, ok := report.Result(validation.CheckBucketExists); ok && result.Status != validation.StatusPass {
+		log.Fatalf("Error: bucket %s does not exist", bucket)
+	}
+
+	if checklistFix {
+		report = runChecklistFix(ctx, minioClient, report, opts)
+	}
+
+	if report.Failed(failOn) {
+		os.Exit(2)
+	}
+}
+
+// checklistOptions builds the check options from the checklist flags
+func checklistOptions() validation.CheckOptions {
+	opts := validation.CheckOptions{
+		ScanRetention:     scanRetention,
+		TestEvents:        testEvents,
+		SampleEncryption:  sampleEncryption,
+		SampleReplication: sampleReplication,
+		SampleSize:        sampleSize,
+	}
+
+	var err error
+	if checklistBaseline != "" {
+		opts.Baseline, err = validation.LoadBaseline(checklistBaseline)
+		if err != nil {
+			log.Fatalf("Error loading baseline: %v", err)
+		}
+	}
+	if minRetention != "" {
+		if !scanRetention {
+			log.Fatalf("Error: --min-retention requires --scan-retention")
+		}
+		opts.MinRetention, err = analyze.ParseAge(minRetention)
+		if err != nil {
+			log.Fatalf("Error parsing --min-retention: %v", err)
+		}
+	}
+
+	return opts
+}
+
+// addAdminChecks enables the checks that need the admin API: lifecycle transitions are
+// checked against the remote tiers, replication rules against the bucket's remote targets
+// and the default encryption key against the KMS. Without admin credentials those checks
+// are skipped.
+func addAdminChecks(ctx context.Context, cfg *config.MCConfig, alias string, opts *validation.CheckOptions) {
+	adminClient, err := client.CreateAdminClient(cfg, alias, insecure)
+	if err != nil {
+		return
+	}
+
+	opts.KMSKeyStatus = adminClient.KMSKeyStatus
+	opts.RemoteTargets = adminClient.ListRemoteTargets
+	opts.Tiers, err = adminClient.ListTiers(ctx)
+	if err != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// runChecklistAlias checks every bucket of an alias and writes the compliance matrix
+func runChecklistAlias(alias string, failOn validation.Status) {
+	format, err := validation.ParseMatrixFormat(checklistOutput)
+	if err != nil {
+		log.Fatalf("Error parsing --output: %v", err)
+	}
+	if checklistFix || checklistRollback != "" {
+		log.Fatalf("Error: --fix and --rollback require a single bucket")
+	}
+	if testEvents {
+		log.Fatalf("Error: --test-events writes to the bucket and requires a single bucket")
+	}
+
+	opts := checklistOptions()
+
+	// Load MinIO configuration
+	cfg, err := config.LoadMCConfig()
+	if err != nil {
+		log.Fatalf("Error loading MC config: %v", err)
+	}
+
+	// Create MinIO client
+	minioClient, err := client.CreateMinIOClient(cfg, alias, insecure, verbose)
+	if err != nil {
+		log.Fatalf("Error creating MinIO client: %v", err)
+	}
+
+	ctx := context.Background()
+	addAdminChecks(ctx, cfg, alias, &opts)
+
+	buckets, err := minioClient.ListBuckets(ctx)
+	if err != nil {
+		log.Fatalf("Error listing buckets: %v", err)
+	}
+
+	names := make([]string, len(buckets))
+	for i, bucket := range buckets {
+		names[i] = bucket.Name
+	}
+
+	reports := validation.CheckBuckets(ctx, minioClient, names, opts, concurrency)
+	matrix := validation.NewMatrix(alias, reports, time.Now())
+	if err := validation.WriteMatrix(os.Stdout, matrix, format); err != nil {
+		log.Fatalf("Error writing compliance matrix: %v", err)
+	}
+
+	if failOn == "" {
+		return
+	}
+	for _, bucketReport := range reports {
+		if bucketReport.Err != nil || bucketReport.Report.Failed(failOn) {
+			os.Exit(2)
+		}
+	}
+}
+
+// runChecklistFix shows the remediation plan and applies it with --yes, returning the
+// report to judge --fail-on by (re-checked after fixes were applied)
+func runChecklistFix(ctx context.Context, minioClient *minio.Client, report *validation.Report, opts validation.CheckOptions) *validation.Report {
+	plan := validation.PlanFixes(report, opts.Baseline)
+	validation.DisplayFixPlan(plan)
+
+	if len(plan.Fixes) == 0 {
+		return report
+	}
+	if dryRun {
+		fmt.Printf("\nDry run: %d fixes would be applied\n", len(plan.Fixes))
+		return report
+	}
+	if !assumeYes {
+		fmt.Printf("\nRe-run with --yes to apply these fixes, or with --dry-run to only show them\n")
+		return report
+	}
+
+	backupPath := checklistBackupFile
+	if backupPath == "" {
+		backupPath = validation.DefaultBackupPath(report.Bucket, time.Now())
+	}
+
+	fmt.Println()
+	if err := validation.ApplyFixes(ctx, minioClient, plan, backupPath); err != nil {
+		log.Fatalf("Error applying fixes: %v", err)
+	}
+
+	// The fixes only change bucket configuration, so probes and object scans are not repeated
+	opts.TestEvents = false
+	opts.ScanRetention = false
+	opts.SampleEncryption = false
+	opts.SampleReplication = false
+
+	rechecked, err := validation.CheckBucketConfiguration(ctx, minioClient, report.Bucket, opts)
+	if err != nil {
+		log.Fatalf("Error re-checking bucket configuration: %v", err)
+	}
+	fmt.Printf("\nAfter fixes: %d passed, %d warnings, %d failed, %d skipped\n",
+		rechecked.Count(validation.StatusPass), rechecked.Count(validation.StatusWarn),
+		rechecked.Count(validation.StatusFail), rechecked.Count(validation.StatusSkip))
+	fmt.Printf("Roll back with: mc-tool checklist --rollback %s --yes <alias>/%s\n", backupPath, report.Bucket)
+	return rechecked
+}
+
+func runChecklistRollback(ctx context.Context, minioClient *minio.Client, bucket string) {
+	backup, err := validation.LoadBackup(checklistRollback)
+	if err != nil {
+		log.Fatalf("Error loading backup: %v", err)
+	}
+	if backup.Bucket != bucket {
+		log.Fatalf("Error: backup %s is for bucket %s, not %s", checklistRollback, backup.Bucket, bucket)
+	}
+
+	fmt.Printf("Rollback plan for %s (backup from %s):\n", bucket, backup.CreatedAt.Format(time.RFC3339))
+	fmt.Printf("===================\n")
+	steps := backup.RestoreSteps()
+	if len(steps) == 0 {
+		fmt.Printf("Nothing to roll back\n")
+		return
+	}
+	for i, step := range steps {
+		fmt.Printf("%d. %s\n", i+1, step)
+	}
+
+	if dryRun {
+		fmt.Printf("\nDry run: %d changes would be rolled back\n", len(steps))
+		return
+	}
+	if !assumeYes {
+		fmt.Printf("\nRe-run with --yes to roll back, or with --dry-run to only show the plan\n")
+		return
+	}
+
+	fmt.Println()
+	if err := validation.Rollback(ctx, minioClient, backup); err != nil {
+		log.Fatalf("Error rolling back: %v", err)
+	}
 }
\ No newline at end of file
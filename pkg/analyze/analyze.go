@@ -3,12 +3,58 @@ package analyze
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 
 	"github.com/minio/minio-go/v7"
 
 	"github.com/liamdn8/mc-tool/pkg/compare"
 )
 
+// rootPrefix is the label used for keys that sit directly at the bucket root
+const rootPrefix = "(root)"
+
+// topLevelPrefix returns the first path segment of key below base (including
+// the trailing slash) so that per-prefix totals can be reported
+func topLevelPrefix(key, base string) string {
+	rel := strings.TrimPrefix(key, base)
+	rel = strings.TrimPrefix(rel, "/")
+
+	idx := strings.Index(rel, "/")
+	if idx < 0 {
+		if base == "" {
+			return rootPrefix
+		}
+		return base
+	}
+
+	return key[:len(key)-len(rel)+idx+1]
+}
+
+// groupVersionsByKey groups objects by key, returning the keys in sorted order
+// and each key's versions ordered from newest to oldest
+func groupVersionsByKey(objects []*compare.ObjectInfo) ([]string, map[string][]*compare.ObjectInfo) {
+	versionsByKey := make(map[string][]*compare.ObjectInfo)
+	for _, obj := range objects {
+		versionsByKey[obj.Key] = append(versionsByKey[obj.Key], obj)
+	}
+
+	keys := make([]string, 0, len(versionsByKey))
+	for key, versions := range versionsByKey {
+		keys = append(keys, key)
+		sort.SliceStable(versions, func(i, j int) bool {
+			if versions[i].IsLatest != versions[j].IsLatest {
+				return versions[i].IsLatest
+			}
+			return versions[i].LastModified.After(versions[j].LastModified)
+		})
+	}
+	sort.Strings(keys)
+
+	return keys, versionsByKey
+}
+
 // ListIncompleteUploads detects incomplete multipart uploads that might affect object counts
 func ListIncompleteUploads(ctx context.Context, client *minio.Client, bucket, prefix string) ([]minio.ObjectMultipartInfo, error) {
 	var incompleteUploads []minio.ObjectMultipartInfo
@@ -23,6 +69,30 @@ func ListIncompleteUploads(ctx context.Context, client *minio.Client, bucket, pr
 	return incompleteUploads, nil
 }
 
+// UploadPrefixUsage totals the storage held by incomplete uploads under a prefix
+type UploadPrefixUsage struct {
+	Uploads int
+	Parts   int
+	Size    int64
+}
+
+// SummarizeUploadUsage totals incomplete upload parts and bytes per top-level prefix
+func SummarizeUploadUsage(usages []UploadUsage, prefix string) map[string]*UploadPrefixUsage {
+	summary := make(map[string]*UploadPrefixUsage)
+
+	for _, usage := range usages {
+		p := topLevelPrefix(usage.Key, prefix)
+		if summary[p] == nil {
+			summary[p] = &UploadPrefixUsage{}
+		}
+		summary[p].Uploads++
+		summary[p].Parts += usage.Parts
+		summary[p].Size += usage.PartsSize
+	}
+
+	return summary
+}
+
 // AnalyzeObjectDistribution provides detailed statistics about object versions and states
 func AnalyzeObjectDistribution(objects []*compare.ObjectInfo) map[string]interface{} {
 	stats := make(map[string]interface{})
@@ -64,91 +134,77 @@ func AnalyzeObjectDistribution(objects []*compare.ObjectInfo) map[string]interfa
 }
 
 // DisplayAnalysisResults displays the analysis results in a formatted way
-func DisplayAnalysisResults(stats map[string]interface{}, incompleteUploads []minio.ObjectMultipartInfo, objects []*compare.ObjectInfo, verbose bool) {
-	fmt.Println("Object Distribution Analysis:")
-	fmt.Println("============================")
-
-	fmt.Printf("Total Objects (all versions): %d\n", stats["total_objects"])
-	fmt.Printf("Current Versions: %d\n", stats["current_versions"])
-	fmt.Printf("Old Versions: %d\n", stats["old_versions"])
-	fmt.Printf("Delete Markers: %d\n", stats["delete_markers"])
-	fmt.Printf("Unique Object Keys: %d\n", stats["unique_keys"])
-	fmt.Printf("Total Size (all versions): %d bytes\n", stats["total_size"])
-	fmt.Printf("Current Version Size: %d bytes\n", stats["current_size"])
+func DisplayAnalysisResults(w io.Writer, stats map[string]interface{}, incompleteUploads []UploadUsage, prefix string, verbose bool) {
+	fmt.Fprintln(w, "Object Distribution Analysis:")
+	fmt.Fprintln(w, "============================")
+
+	fmt.Fprintf(w, "Total Objects (all versions): %d\n", stats["total_objects"])
+	fmt.Fprintf(w, "Current Versions: %d\n", stats["current_versions"])
+	fmt.Fprintf(w, "Old Versions: %d\n", stats["old_versions"])
+	fmt.Fprintf(w, "Delete Markers: %d\n", stats["delete_markers"])
+	fmt.Fprintf(w, "Unique Object Keys: %d\n", stats["unique_keys"])
+	fmt.Fprintf(w, "Total Size (all versions): %d bytes\n", stats["total_size"])
+	fmt.Fprintf(w, "Current Version Size: %d bytes\n", stats["current_size"])
+
+	var incompleteUploadSize int64
+	for _, upload := range incompleteUploads {
+		incompleteUploadSize += upload.PartsSize
+	}
 
 	if len(incompleteUploads) > 0 {
-		fmt.Printf("\nIncomplete Multipart Uploads: %d\n", len(incompleteUploads))
+		fmt.Fprintf(w, "\nIncomplete Multipart Uploads: %d\n", len(incompleteUploads))
+		fmt.Fprintf(w, "Incomplete Upload Size (uploaded parts): %d bytes\n", incompleteUploadSize)
 		if verbose {
-			fmt.Println("\nIncomplete Upload Details:")
+			fmt.Fprintln(w, "\nIncomplete Upload Details:")
 			for _, upload := range incompleteUploads {
-				fmt.Printf("  - %s (ID: %s, Initiated: %s)\n",
-					upload.Key, upload.UploadID, upload.Initiated.Format("2006-01-02 15:04:05"))
+				fmt.Fprintf(w, "  - %s (ID: %s, Initiated: %s, Parts: %d, Size: %d bytes)\n",
+					upload.Key, upload.UploadID, upload.Initiated.Format("2006-01-02 15:04:05"), upload.Parts, upload.PartsSize)
 			}
 		}
 	} else {
-		fmt.Println("\nIncomplete Multipart Uploads: 0")
-	}
-
-	if verbose && len(objects) > 0 {
-		fmt.Println("\nDetailed Object Analysis:")
-		fmt.Println("========================")
-
-		// Group objects by key
-		objectsByKey := make(map[string][]*compare.ObjectInfo)
-		for _, obj := range objects {
-			objectsByKey[obj.Key] = append(objectsByKey[obj.Key], obj)
-		}
-
-		for key, versions := range objectsByKey {
-			fmt.Printf("\nObject: %s\n", key)
-			fmt.Printf("  Total versions: %d\n", len(versions))
-
-			for i, version := range versions {
-				status := ""
-				if version.IsLatest {
-					status += "[CURRENT]"
-				}
-				if version.IsDeleteMarker {
-					status += "[DELETE_MARKER]"
-				}
-				if status == "" {
-					status = "[OLD_VERSION]"
-				}
-
-				fmt.Printf("  %d. %s Size: %d, ETag: %s, VersionID: %s, Modified: %s\n",
-					i+1, status, version.Size, version.ETag, version.VersionID,
-					version.LastModified.Format("2006-01-02 15:04:05"))
-			}
-		}
+		fmt.Fprintln(w, "\nIncomplete Multipart Uploads: 0")
 	}
 
 	// Analysis summary
-	fmt.Println("\nPotential Discrepancy Sources:")
-	fmt.Println("==============================")
+	fmt.Fprintln(w, "\nPotential Discrepancy Sources:")
+	fmt.Fprintln(w, "==============================")
 
 	if stats["delete_markers"].(int) > 0 {
-		fmt.Printf("⚠ Found %d delete markers that might not be counted in some metrics\n", stats["delete_markers"])
+		fmt.Fprintf(w, "⚠ Found %d delete markers that might not be counted in some metrics\n", stats["delete_markers"])
 	}
 
 	if len(incompleteUploads) > 0 {
-		fmt.Printf("⚠ Found %d incomplete multipart uploads that might affect object counts\n", len(incompleteUploads))
+		fmt.Fprintf(w, "⚠ Found %d incomplete multipart uploads consuming %d bytes that might affect object counts and size\n",
+			len(incompleteUploads), incompleteUploadSize)
+
+		usageByPrefix := SummarizeUploadUsage(incompleteUploads, prefix)
+		prefixes := make([]string, 0, len(usageByPrefix))
+		for p := range usageByPrefix {
+			prefixes = append(prefixes, p)
+		}
+		sort.Strings(prefixes)
+
+		for _, p := range prefixes {
+			usage := usageByPrefix[p]
+			fmt.Fprintf(w, "  - %s: %d uploads, %d parts, %d bytes\n", p, usage.Uploads, usage.Parts, usage.Size)
+		}
 	}
 
 	if stats["old_versions"].(int) > 0 {
-		fmt.Printf("ℹ Found %d old versions (these should not affect current object counts)\n", stats["old_versions"])
+		fmt.Fprintf(w, "ℹ Found %d old versions (these should not affect current object counts)\n", stats["old_versions"])
 	}
 
 	currentObjects := stats["current_versions"].(int)
 	totalVersions := stats["total_objects"].(int)
 
-	fmt.Printf("\nMetrics Comparison:\n")
-	fmt.Printf("- Current objects (should match bucket metrics): %d\n", currentObjects)
-	fmt.Printf("- Total storage entries (all versions): %d\n", totalVersions)
-	fmt.Printf("- Objects with delete markers as current version: %d\n", stats["delete_markers"])
+	fmt.Fprintf(w, "\nMetrics Comparison:\n")
+	fmt.Fprintf(w, "- Current objects (should match bucket metrics): %d\n", currentObjects)
+	fmt.Fprintf(w, "- Total storage entries (all versions): %d\n", totalVersions)
+	fmt.Fprintf(w, "- Objects with delete markers as current version: %d\n", stats["delete_markers"])
 
 	if stats["delete_markers"].(int) > 0 || len(incompleteUploads) > 0 {
-		fmt.Println("\n🔍 Recommendation: These hidden objects might explain metric discrepancies")
+		fmt.Fprintln(w, "\n🔍 Recommendation: These hidden objects might explain metric discrepancies")
 	} else {
-		fmt.Println("\n✅ No hidden objects detected - metric discrepancy might be due to other factors")
+		fmt.Fprintln(w, "\n✅ No hidden objects detected - metric discrepancy might be due to other factors")
 	}
 }
\ No newline at end of file
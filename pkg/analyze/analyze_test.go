@@ -3,6 +3,7 @@ package analyze
 import (
 	"testing"
 
+	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/liamdn8/mc-tool/pkg/compare"
@@ -144,4 +145,18 @@ func TestAnalyzeObjectDistributionVersioned(t *testing.T) {
 
 	versionDist := stats["version_distribution"].(map[string]int)
 	assert.Equal(t, 3, versionDist["versioned.txt"])
-}
\ No newline at end of file
+}
+
+func TestSummarizeUploadUsage(t *testing.T) {
+	usages := []UploadUsage{
+		{ObjectMultipartInfo: minio.ObjectMultipartInfo{Key: "backups/db.tar", UploadID: "u1"}, Parts: 3, PartsSize: 300},
+		{ObjectMultipartInfo: minio.ObjectMultipartInfo{Key: "backups/logs.tar", UploadID: "u2"}, Parts: 1, PartsSize: 50},
+		{ObjectMultipartInfo: minio.ObjectMultipartInfo{Key: "video.mp4", UploadID: "u3"}, Parts: 2, PartsSize: 200},
+	}
+
+	summary := SummarizeUploadUsage(usages, "")
+
+	assert.Len(t, summary, 2)
+	assert.Equal(t, &UploadPrefixUsage{Uploads: 2, Parts: 4, Size: 350}, summary["backups/"])
+	assert.Equal(t, &UploadPrefixUsage{Uploads: 1, Parts: 2, Size: 200}, summary[rootPrefix])
+}
@@ -0,0 +1,194 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// BucketSummary holds the headline analysis figures of a single bucket
+type BucketSummary struct {
+	Bucket string
+	Err    error
+
+	TotalVersions        int
+	CurrentObjects       int
+	CurrentSize          int64
+	NoncurrentVersions   int
+	NoncurrentSize       int64
+	DeleteMarkers        int
+	IncompleteUploads    int
+	IncompleteUploadSize int64
+}
+
+// SummarizeBucket reduces a bucket's listing and incomplete uploads to a summary
+func SummarizeBucket(bucket string, objects []*compare.ObjectInfo, uploads []UploadUsage) BucketSummary {
+	summary := BucketSummary{Bucket: bucket}
+
+	for _, obj := range objects {
+		summary.TotalVersions++
+		switch {
+		case obj.IsDeleteMarker:
+			summary.DeleteMarkers++
+		case obj.IsLatest:
+			summary.CurrentObjects++
+			summary.CurrentSize += obj.Size
+		default:
+			summary.NoncurrentVersions++
+			summary.NoncurrentSize += obj.Size
+		}
+	}
+
+	for _, upload := range uploads {
+		summary.IncompleteUploads++
+		summary.IncompleteUploadSize += upload.PartsSize
+	}
+
+	return summary
+}
+
+// AnalyzeBucket lists a bucket's versions and incomplete uploads and summarizes them
+func AnalyzeBucket(ctx context.Context, client *minio.Client, bucket string) BucketSummary {
+	objects, err := compare.ListObjects(ctx, client, bucket, "")
+	if err != nil {
+		return BucketSummary{Bucket: bucket, Err: fmt.Errorf("failed to list objects: %w", err)}
+	}
+
+	uploads, err := ListIncompleteUploads(ctx, client, bucket, "")
+	if err != nil {
+		return BucketSummary{Bucket: bucket, Err: fmt.Errorf("failed to list incomplete uploads: %w", err)}
+	}
+
+	usages, err := MeasureIncompleteUploads(ctx, client, bucket, uploads)
+	if err != nil {
+		return BucketSummary{Bucket: bucket, Err: err}
+	}
+
+	return SummarizeBucket(bucket, objects, usages)
+}
+
+// AnalyzeBuckets analyzes the given buckets with at most concurrency buckets in flight.
+// Summaries are returned in the order of the buckets.
+func AnalyzeBuckets(ctx context.Context, client *minio.Client, buckets []string, concurrency int) []BucketSummary {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	summaries := make([]BucketSummary, len(buckets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, bucket := range buckets {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, bucket string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			summaries[i] = AnalyzeBucket(ctx, client, bucket)
+		}(i, bucket)
+	}
+
+	wg.Wait()
+	return summaries
+}
+
+// rankBuckets returns the successfully analyzed buckets with a non-zero value, highest first
+func rankBuckets(summaries []BucketSummary, value func(BucketSummary) int64) []BucketSummary {
+	var ranked []BucketSummary
+	for _, summary := range summaries {
+		if summary.Err == nil && value(summary) > 0 {
+			ranked = append(ranked, summary)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return value(ranked[i]) > value(ranked[j])
+	})
+
+	return ranked
+}
+
+// DisplayBucketReport displays a consolidated report ranking buckets by noncurrent
+// bytes, delete markers and incomplete uploads
+func DisplayBucketReport(w io.Writer, alias string, summaries []BucketSummary) {
+	fmt.Fprintf(w, "Consolidated Analysis: %s (%d buckets)\n", alias, len(summaries))
+	fmt.Fprintln(w, "=================================")
+
+	var total BucketSummary
+	var failed []BucketSummary
+	for _, summary := range summaries {
+		if summary.Err != nil {
+			failed = append(failed, summary)
+			continue
+		}
+		total.TotalVersions += summary.TotalVersions
+		total.CurrentObjects += summary.CurrentObjects
+		total.CurrentSize += summary.CurrentSize
+		total.NoncurrentVersions += summary.NoncurrentVersions
+		total.NoncurrentSize += summary.NoncurrentSize
+		total.DeleteMarkers += summary.DeleteMarkers
+		total.IncompleteUploads += summary.IncompleteUploads
+		total.IncompleteUploadSize += summary.IncompleteUploadSize
+	}
+
+	fmt.Fprintf(w, "Total Objects (all versions): %d\n", total.TotalVersions)
+	fmt.Fprintf(w, "Current Objects: %d (%d bytes)\n", total.CurrentObjects, total.CurrentSize)
+	fmt.Fprintf(w, "Noncurrent Versions: %d (%d bytes)\n", total.NoncurrentVersions, total.NoncurrentSize)
+	fmt.Fprintf(w, "Delete Markers: %d\n", total.DeleteMarkers)
+	fmt.Fprintf(w, "Incomplete Multipart Uploads: %d (%d bytes)\n", total.IncompleteUploads, total.IncompleteUploadSize)
+
+	fmt.Fprintln(w, "\nAll Buckets:")
+	fmt.Fprintf(w, "  %-32s %12s %16s %12s %16s %10s %16s\n",
+		"Bucket", "Current", "Current Bytes", "Noncurrent", "Noncurrent Bytes", "Markers", "Upload Bytes")
+	for _, summary := range summaries {
+		if summary.Err != nil {
+			continue
+		}
+		fmt.Fprintf(w, "  %-32s %12d %16d %12d %16d %10d %16d\n", summary.Bucket,
+			summary.CurrentObjects, summary.CurrentSize, summary.NoncurrentVersions, summary.NoncurrentSize,
+			summary.DeleteMarkers, summary.IncompleteUploadSize)
+	}
+
+	fmt.Fprintln(w, "\nTop Buckets by Noncurrent Bytes:")
+	printRanking(w, rankBuckets(summaries, func(s BucketSummary) int64 { return s.NoncurrentSize }), func(s BucketSummary) string {
+		return fmt.Sprintf("%d bytes in %d versions", s.NoncurrentSize, s.NoncurrentVersions)
+	})
+
+	fmt.Fprintln(w, "\nTop Buckets by Delete Markers:")
+	printRanking(w, rankBuckets(summaries, func(s BucketSummary) int64 { return int64(s.DeleteMarkers) }), func(s BucketSummary) string {
+		return fmt.Sprintf("%d delete markers", s.DeleteMarkers)
+	})
+
+	fmt.Fprintln(w, "\nTop Buckets by Incomplete Uploads:")
+	printRanking(w, rankBuckets(summaries, func(s BucketSummary) int64 { return s.IncompleteUploadSize }), func(s BucketSummary) string {
+		return fmt.Sprintf("%d bytes in %d uploads", s.IncompleteUploadSize, s.IncompleteUploads)
+	})
+
+	if len(failed) > 0 {
+		fmt.Fprintf(w, "\n❌ Failed to analyze %d buckets:\n", len(failed))
+		for _, summary := range failed {
+			fmt.Fprintf(w, "  - %s: %v\n", summary.Bucket, summary.Err)
+		}
+	}
+}
+
+func printRanking(w io.Writer, ranked []BucketSummary, describe func(BucketSummary) string) {
+	if len(ranked) == 0 {
+		fmt.Fprintln(w, "  (none)")
+		return
+	}
+
+	for i, summary := range ranked {
+		if i >= 10 {
+			break
+		}
+		fmt.Fprintf(w, "  %d. %s: %s\n", i+1, summary.Bucket, describe(summary))
+	}
+}
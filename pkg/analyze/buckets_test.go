@@ -0,0 +1,55 @@
+package analyze
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func TestSummarizeBucket(t *testing.T) {
+	objects := []*compare.ObjectInfo{
+		{Key: "a", IsLatest: true, Size: 100},
+		{Key: "a", Size: 80},
+		{Key: "b", IsLatest: true, IsDeleteMarker: true},
+		{Key: "b", Size: 40},
+	}
+	uploads := []UploadUsage{
+		{ObjectMultipartInfo: minio.ObjectMultipartInfo{Key: "c"}, PartsSize: 500},
+	}
+
+	summary := SummarizeBucket("data", objects, uploads)
+
+	assert.Equal(t, BucketSummary{
+		Bucket:               "data",
+		TotalVersions:        4,
+		CurrentObjects:       1,
+		CurrentSize:          100,
+		NoncurrentVersions:   2,
+		NoncurrentSize:       120,
+		DeleteMarkers:        1,
+		IncompleteUploads:    1,
+		IncompleteUploadSize: 500,
+	}, summary)
+}
+
+func TestRankBuckets(t *testing.T) {
+	summaries := []BucketSummary{
+		{Bucket: "small", NoncurrentSize: 10},
+		{Bucket: "none"},
+		{Bucket: "large", NoncurrentSize: 1000},
+		{Bucket: "broken", NoncurrentSize: 5000, Err: errors.New("access denied")},
+		{Bucket: "medium", NoncurrentSize: 100},
+	}
+
+	ranked := rankBuckets(summaries, func(s BucketSummary) int64 { return s.NoncurrentSize })
+
+	var names []string
+	for _, summary := range ranked {
+		names = append(names, summary.Bucket)
+	}
+	assert.Equal(t, []string{"large", "medium", "small"}, names)
+}
@@ -0,0 +1,276 @@
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// bytesPerGB is the gigabyte used for storage billing (2^30 bytes)
+const bytesPerGB = 1 << 30
+
+// StorageClassPricing holds the prices of a storage class
+type StorageClassPricing struct {
+	PerGBMonth        float64 `json:"per_gb_month"`
+	TransitionPer1000 float64 `json:"transition_per_1000"`
+}
+
+// RequestPricing holds the price and expected monthly volume of a request type
+type RequestPricing struct {
+	Per1000      float64 `json:"per_1000"`
+	MonthlyCount int64   `json:"monthly_count"`
+}
+
+// PricingProfile describes storage and request prices used for cost estimation
+type PricingProfile struct {
+	Currency            string                         `json:"currency"`
+	DefaultStorageClass string                         `json:"default_storage_class"`
+	StorageClasses      map[string]StorageClassPricing `json:"storage_classes"`
+	Requests            map[string]RequestPricing      `json:"requests"`
+}
+
+// LoadPricingProfile reads a pricing profile from a JSON file
+func LoadPricingProfile(path string) (*PricingProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing profile: %v", err)
+	}
+
+	var profile PricingProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing profile: %v", err)
+	}
+
+	if profile.DefaultStorageClass == "" {
+		profile.DefaultStorageClass = "STANDARD"
+	}
+	if _, ok := profile.StorageClasses[profile.DefaultStorageClass]; !ok {
+		return nil, fmt.Errorf("pricing profile has no price for default storage class %s", profile.DefaultStorageClass)
+	}
+
+	return &profile, nil
+}
+
+// storageClass resolves an object's storage class, falling back to the default class
+// when the class is empty or not priced in the profile
+func (p *PricingProfile) storageClass(class string) string {
+	if _, ok := p.StorageClasses[class]; ok {
+		return class
+	}
+	return p.DefaultStorageClass
+}
+
+// monthlyStorageCost returns the monthly cost of storing size bytes in a storage class
+func (p *PricingProfile) monthlyStorageCost(class string, size int64) float64 {
+	return float64(size) / bytesPerGB * p.StorageClasses[p.storageClass(class)].PerGBMonth
+}
+
+// CostBreakdown splits storage bytes and monthly cost by object state
+type CostBreakdown struct {
+	CurrentBytes    int64
+	NoncurrentBytes int64
+	UploadBytes     int64
+
+	CurrentCost    float64
+	NoncurrentCost float64
+	UploadCost     float64
+}
+
+// TotalCost returns the monthly storage cost of the breakdown
+func (c *CostBreakdown) TotalCost() float64 {
+	return c.CurrentCost + c.NoncurrentCost + c.UploadCost
+}
+
+// CostEstimate holds the estimated monthly cost of a bucket or prefix
+type CostEstimate struct {
+	Currency    string
+	Total       CostBreakdown
+	ByPrefix    map[string]*CostBreakdown
+	RequestCost float64
+
+	// UnpricedClasses lists storage classes missing from the profile, priced as the default class
+	UnpricedClasses []string
+}
+
+// EstimateStorageCost computes the monthly storage cost of current versions, noncurrent
+// versions and incomplete upload parts, in total and per top-level prefix
+func EstimateStorageCost(profile *PricingProfile, objects []*compare.ObjectInfo, uploads []UploadUsage, prefix string) *CostEstimate {
+	estimate := &CostEstimate{
+		Currency: profile.Currency,
+		ByPrefix: make(map[string]*CostBreakdown),
+	}
+
+	unpriced := make(map[string]bool)
+	breakdownFor := func(key string) *CostBreakdown {
+		p := topLevelPrefix(key, prefix)
+		if estimate.ByPrefix[p] == nil {
+			estimate.ByPrefix[p] = &CostBreakdown{}
+		}
+		return estimate.ByPrefix[p]
+	}
+
+	for _, obj := range objects {
+		if obj.IsDeleteMarker {
+			continue
+		}
+		if _, ok := profile.StorageClasses[obj.StorageClass]; !ok && obj.StorageClass != "" {
+			unpriced[obj.StorageClass] = true
+		}
+
+		cost := profile.monthlyStorageCost(obj.StorageClass, obj.Size)
+		breakdown := breakdownFor(obj.Key)
+
+		if obj.IsLatest {
+			breakdown.CurrentBytes += obj.Size
+			breakdown.CurrentCost += cost
+			estimate.Total.CurrentBytes += obj.Size
+			estimate.Total.CurrentCost += cost
+		} else {
+			breakdown.NoncurrentBytes += obj.Size
+			breakdown.NoncurrentCost += cost
+			estimate.Total.NoncurrentBytes += obj.Size
+			estimate.Total.NoncurrentCost += cost
+		}
+	}
+
+	for _, upload := range uploads {
+		cost := profile.monthlyStorageCost(upload.StorageClass, upload.PartsSize)
+		breakdown := breakdownFor(upload.Key)
+
+		breakdown.UploadBytes += upload.PartsSize
+		breakdown.UploadCost += cost
+		estimate.Total.UploadBytes += upload.PartsSize
+		estimate.Total.UploadCost += cost
+	}
+
+	for _, request := range profile.Requests {
+		estimate.RequestCost += float64(request.MonthlyCount) / 1000 * request.Per1000
+	}
+
+	for class := range unpriced {
+		estimate.UnpricedClasses = append(estimate.UnpricedClasses, class)
+	}
+	sort.Strings(estimate.UnpricedClasses)
+
+	return estimate
+}
+
+// LifecycleSavings holds the estimated effect of a lifecycle configuration on cost
+type LifecycleSavings struct {
+	RemovedBytes      int64
+	RemovalSavings    float64
+	TransitionedBytes int64
+	TransitionSavings float64
+
+	// TransitionRequestCost is the one-off cost of the transition requests
+	TransitionRequestCost float64
+
+	// UnpricedClasses lists transition target classes missing from the pricing profile
+	UnpricedClasses []string
+}
+
+// MonthlySavings returns the recurring monthly savings
+func (s *LifecycleSavings) MonthlySavings() float64 {
+	return s.RemovalSavings + s.TransitionSavings
+}
+
+// EstimateLifecycleSavings prices the versions a lifecycle simulation would remove or
+// transition. versioning is the bucket's versioning status: expiring a current version of
+// a versioned bucket only adds a delete marker, so it frees storage only when the bucket
+// is unversioned or the version is the null version of a suspended bucket.
+func EstimateLifecycleSavings(profile *PricingProfile, sim *LifecycleSimulation, versioning string) *LifecycleSavings {
+	savings := &LifecycleSavings{}
+
+	removed := sim.Removed
+	for _, obj := range sim.ExpiredCurrent {
+		if expirationFreesStorage(obj, versioning) {
+			removed = append(removed, obj)
+		}
+	}
+
+	for _, obj := range removed {
+		savings.RemovedBytes += obj.Size
+		savings.RemovalSavings += profile.monthlyStorageCost(obj.StorageClass, obj.Size)
+	}
+
+	unpriced := make(map[string]bool)
+	for obj, class := range sim.TransitionedTo {
+		if _, ok := profile.StorageClasses[class]; !ok {
+			unpriced[class] = true
+		}
+
+		savings.TransitionedBytes += obj.Size
+		savings.TransitionSavings += profile.monthlyStorageCost(obj.StorageClass, obj.Size) -
+			profile.monthlyStorageCost(class, obj.Size)
+		savings.TransitionRequestCost += profile.StorageClasses[profile.storageClass(class)].TransitionPer1000 / 1000
+	}
+
+	for class := range unpriced {
+		savings.UnpricedClasses = append(savings.UnpricedClasses, class)
+	}
+	sort.Strings(savings.UnpricedClasses)
+
+	return savings
+}
+
+// expirationFreesStorage reports whether expiring a current version deletes its data
+func expirationFreesStorage(obj *compare.ObjectInfo, versioning string) bool {
+	switch versioning {
+	case "Enabled":
+		return false
+	case "Suspended":
+		return obj.VersionID == "" || obj.VersionID == "null"
+	}
+	return true
+}
+
+// DisplayCostEstimate displays the monthly cost estimate, per-prefix chargeback and
+// optional lifecycle savings
+func DisplayCostEstimate(w io.Writer, estimate *CostEstimate, savings *LifecycleSavings) {
+	currency := estimate.Currency
+
+	fmt.Fprintln(w, "\nEstimated Monthly Storage Cost:")
+	fmt.Fprintln(w, "===============================")
+	fmt.Fprintf(w, "Current versions: %d bytes = %.2f %s\n", estimate.Total.CurrentBytes, estimate.Total.CurrentCost, currency)
+	fmt.Fprintf(w, "Noncurrent versions: %d bytes = %.2f %s\n", estimate.Total.NoncurrentBytes, estimate.Total.NoncurrentCost, currency)
+	fmt.Fprintf(w, "Incomplete upload parts: %d bytes = %.2f %s\n", estimate.Total.UploadBytes, estimate.Total.UploadCost, currency)
+	if estimate.RequestCost > 0 {
+		fmt.Fprintf(w, "Requests: %.2f %s\n", estimate.RequestCost, currency)
+	}
+	fmt.Fprintf(w, "Total: %.2f %s\n", estimate.Total.TotalCost()+estimate.RequestCost, currency)
+
+	for _, class := range estimate.UnpricedClasses {
+		fmt.Fprintf(w, "⚠ Storage class %s is not in the pricing profile, priced as the default class\n", class)
+	}
+
+	if len(estimate.ByPrefix) > 0 {
+		prefixes := make([]string, 0, len(estimate.ByPrefix))
+		for p := range estimate.ByPrefix {
+			prefixes = append(prefixes, p)
+		}
+		sort.Strings(prefixes)
+
+		fmt.Fprintln(w, "\nChargeback by prefix (storage only):")
+		fmt.Fprintf(w, "  %-32s %12s %12s %12s %12s\n", "Prefix", "Current", "Noncurrent", "Uploads", "Total")
+		for _, p := range prefixes {
+			breakdown := estimate.ByPrefix[p]
+			fmt.Fprintf(w, "  %-32s %12.2f %12.2f %12.2f %12.2f\n", p,
+				breakdown.CurrentCost, breakdown.NoncurrentCost, breakdown.UploadCost, breakdown.TotalCost())
+		}
+	}
+
+	if savings != nil {
+		fmt.Fprintln(w, "\nLifecycle Savings:")
+		fmt.Fprintf(w, "  Removed versions: %d bytes, saving %.2f %s/month\n", savings.RemovedBytes, savings.RemovalSavings, currency)
+		fmt.Fprintf(w, "  Transitioned versions: %d bytes, saving %.2f %s/month\n", savings.TransitionedBytes, savings.TransitionSavings, currency)
+		fmt.Fprintf(w, "  One-off transition request cost: %.2f %s\n", savings.TransitionRequestCost, currency)
+		fmt.Fprintf(w, "  Net monthly savings: %.2f %s\n", savings.MonthlySavings(), currency)
+		for _, class := range savings.UnpricedClasses {
+			fmt.Fprintf(w, "  ⚠ Transition target class %s is not in the pricing profile, priced as the default class\n", class)
+		}
+	}
+}
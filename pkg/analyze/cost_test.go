@@ -0,0 +1,107 @@
+package analyze
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func testPricingProfile() *PricingProfile {
+	return &PricingProfile{
+		Currency:            "USD",
+		DefaultStorageClass: "STANDARD",
+		StorageClasses: map[string]StorageClassPricing{
+			"STANDARD": {PerGBMonth: 0.02},
+			"WARM":     {PerGBMonth: 0.01, TransitionPer1000: 10},
+		},
+		Requests: map[string]RequestPricing{
+			"PUT": {Per1000: 0.005, MonthlyCount: 200000},
+		},
+	}
+}
+
+func TestLoadPricingProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "pricing.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"currency": "EUR",
+		"storage_classes": {"STANDARD": {"per_gb_month": 0.02}}
+	}`), 0644))
+
+	profile, err := LoadPricingProfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", profile.Currency)
+	assert.Equal(t, "STANDARD", profile.DefaultStorageClass)
+
+	invalidPath := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidPath, []byte(`{"storage_classes": {"WARM": {"per_gb_month": 0.01}}}`), 0644))
+
+	_, err = LoadPricingProfile(invalidPath)
+	assert.Error(t, err)
+}
+
+func TestEstimateStorageCost(t *testing.T) {
+	profile := testPricingProfile()
+
+	objects := []*compare.ObjectInfo{
+		{Key: "team-a/data.bin", IsLatest: true, Size: 2 * bytesPerGB},
+		{Key: "team-a/data.bin", Size: bytesPerGB},
+		{Key: "team-b/cold.bin", IsLatest: true, Size: bytesPerGB, StorageClass: "WARM"},
+		{Key: "team-b/other.bin", IsLatest: true, Size: bytesPerGB, StorageClass: "GLACIER"},
+		{Key: "team-b/gone.bin", IsLatest: true, IsDeleteMarker: true},
+	}
+	uploads := []UploadUsage{
+		{ObjectMultipartInfo: minio.ObjectMultipartInfo{Key: "team-a/big.bin"}, PartsSize: bytesPerGB},
+	}
+
+	estimate := EstimateStorageCost(profile, objects, uploads, "")
+
+	assert.InDelta(t, 0.07, estimate.Total.CurrentCost, 1e-9)
+	assert.InDelta(t, 0.02, estimate.Total.NoncurrentCost, 1e-9)
+	assert.InDelta(t, 0.02, estimate.Total.UploadCost, 1e-9)
+	assert.InDelta(t, 1.0, estimate.RequestCost, 1e-9)
+	assert.Equal(t, []string{"GLACIER"}, estimate.UnpricedClasses)
+
+	require.Contains(t, estimate.ByPrefix, "team-a/")
+	assert.InDelta(t, 0.08, estimate.ByPrefix["team-a/"].TotalCost(), 1e-9)
+	assert.InDelta(t, 0.03, estimate.ByPrefix["team-b/"].TotalCost(), 1e-9)
+}
+
+func TestEstimateLifecycleSavings(t *testing.T) {
+	profile := testPricingProfile()
+
+	removed := &compare.ObjectInfo{Key: "old.bin", VersionID: "v1", Size: bytesPerGB}
+	expired := &compare.ObjectInfo{Key: "new.bin", VersionID: "v2", IsLatest: true, Size: 2 * bytesPerGB}
+	moved := &compare.ObjectInfo{Key: "cold.bin", Size: 4 * bytesPerGB}
+
+	sim := &LifecycleSimulation{
+		Removed:        []*compare.ObjectInfo{removed},
+		ExpiredCurrent: []*compare.ObjectInfo{expired},
+		TransitionedTo: map[*compare.ObjectInfo]string{moved: "WARM"},
+	}
+
+	// Expiring a current version of a versioned bucket only adds a delete marker
+	savings := EstimateLifecycleSavings(profile, sim, "Enabled")
+
+	assert.Equal(t, int64(bytesPerGB), savings.RemovedBytes)
+	assert.InDelta(t, 0.02, savings.RemovalSavings, 1e-9)
+	assert.InDelta(t, 0.04, savings.TransitionSavings, 1e-9)
+	assert.InDelta(t, 0.01, savings.TransitionRequestCost, 1e-9)
+	assert.InDelta(t, 0.06, savings.MonthlySavings(), 1e-9)
+	assert.Empty(t, savings.UnpricedClasses)
+
+	savings = EstimateLifecycleSavings(profile, sim, "")
+	assert.Equal(t, int64(3*bytesPerGB), savings.RemovedBytes)
+	assert.InDelta(t, 0.06, savings.RemovalSavings, 1e-9)
+
+	sim.TransitionedTo[moved] = "GLACIER"
+	savings = EstimateLifecycleSavings(profile, sim, "Enabled")
+	assert.Equal(t, []string{"GLACIER"}, savings.UnpricedClasses)
+}
@@ -0,0 +1,157 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// DeleteMarkerCounts holds the number of redundant delete markers under a prefix
+type DeleteMarkerCounts struct {
+	Expired int
+	Stacked int
+}
+
+// DeleteMarkerReport describes delete markers that no longer hide any object data
+type DeleteMarkerReport struct {
+	// ExpiredMarkers are delete markers that are the only remaining version of a key
+	ExpiredMarkers []*compare.ObjectInfo
+	// StackedMarkers are delete markers placed directly on top of another delete marker
+	StackedMarkers []*compare.ObjectInfo
+	// ByPrefix totals the markers per top-level prefix
+	ByPrefix map[string]*DeleteMarkerCounts
+}
+
+// Total returns the number of redundant delete markers found
+func (r *DeleteMarkerReport) Total() int {
+	return len(r.ExpiredMarkers) + len(r.StackedMarkers)
+}
+
+// Markers returns all redundant delete markers, expired markers first
+func (r *DeleteMarkerReport) Markers() []*compare.ObjectInfo {
+	markers := make([]*compare.ObjectInfo, 0, r.Total())
+	markers = append(markers, r.ExpiredMarkers...)
+	markers = append(markers, r.StackedMarkers...)
+	return markers
+}
+
+// FindOrphanedDeleteMarkers identifies expired object delete markers and delete
+// markers stacked on other delete markers. Both inflate object counts in metrics
+// without hiding any data.
+func FindOrphanedDeleteMarkers(objects []*compare.ObjectInfo, prefix string) *DeleteMarkerReport {
+	report := &DeleteMarkerReport{
+		ByPrefix: make(map[string]*DeleteMarkerCounts),
+	}
+
+	keys, versionsByKey := groupVersionsByKey(objects)
+
+	for _, key := range keys {
+		versions := versionsByKey[key]
+
+		onlyMarkers := true
+		for _, version := range versions {
+			if !version.IsDeleteMarker {
+				onlyMarkers = false
+				break
+			}
+		}
+
+		counts := report.ByPrefix[topLevelPrefix(key, prefix)]
+		if counts == nil {
+			counts = &DeleteMarkerCounts{}
+		}
+
+		if onlyMarkers {
+			// Nothing is hidden: the newest marker is expired, the rest are redundant
+			report.ExpiredMarkers = append(report.ExpiredMarkers, versions[0])
+			counts.Expired++
+			for _, version := range versions[1:] {
+				report.StackedMarkers = append(report.StackedMarkers, version)
+				counts.Stacked++
+			}
+		} else {
+			for i := 0; i < len(versions)-1; i++ {
+				if versions[i].IsDeleteMarker && versions[i+1].IsDeleteMarker {
+					report.StackedMarkers = append(report.StackedMarkers, versions[i])
+					counts.Stacked++
+				}
+			}
+		}
+
+		if counts.Expired > 0 || counts.Stacked > 0 {
+			report.ByPrefix[topLevelPrefix(key, prefix)] = counts
+		}
+	}
+
+	return report
+}
+
+// DisplayDeleteMarkerReport displays redundant delete markers totalled per prefix
+func DisplayDeleteMarkerReport(w io.Writer, report *DeleteMarkerReport, verbose bool) {
+	fmt.Fprintln(w, "\nRedundant Delete Markers:")
+	fmt.Fprintln(w, "=========================")
+
+	fmt.Fprintf(w, "Expired object delete markers (only remaining version): %d\n", len(report.ExpiredMarkers))
+	fmt.Fprintf(w, "Delete markers stacked on other delete markers: %d\n", len(report.StackedMarkers))
+
+	if report.Total() == 0 {
+		return
+	}
+
+	prefixes := make([]string, 0, len(report.ByPrefix))
+	for prefix := range report.ByPrefix {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	fmt.Fprintln(w, "\nBy prefix:")
+	for _, prefix := range prefixes {
+		counts := report.ByPrefix[prefix]
+		fmt.Fprintf(w, "  %s: %d expired, %d stacked\n", prefix, counts.Expired, counts.Stacked)
+	}
+
+	if verbose {
+		fmt.Fprintln(w, "\nRedundant Delete Marker Details:")
+		for _, marker := range report.ExpiredMarkers {
+			fmt.Fprintf(w, "  - [EXPIRED] %s (VersionID: %s, Modified: %s)\n",
+				marker.Key, marker.VersionID, marker.LastModified.Format("2006-01-02 15:04:05"))
+		}
+		for _, marker := range report.StackedMarkers {
+			fmt.Fprintf(w, "  - [STACKED] %s (VersionID: %s, Modified: %s)\n",
+				marker.Key, marker.VersionID, marker.LastModified.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	fmt.Fprintln(w, "\n💡 Run with --cleanup-delete-markers (optionally --dry-run) to remove them")
+}
+
+// RemoveDeleteMarkers permanently removes the given delete markers by version ID.
+// In dry-run mode the markers are only listed.
+func RemoveDeleteMarkers(ctx context.Context, w io.Writer, client *minio.Client, bucket string, markers []*compare.ObjectInfo, dryRun bool) (int, error) {
+	removed := 0
+
+	for _, marker := range markers {
+		if dryRun {
+			fmt.Fprintf(w, "Would remove delete marker: %s (VersionID: %s)\n", marker.Key, marker.VersionID)
+			removed++
+			continue
+		}
+
+		err := client.RemoveObject(ctx, bucket, marker.Key, minio.RemoveObjectOptions{
+			VersionID: marker.VersionID,
+		})
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove delete marker %s (version %s): %w", marker.Key, marker.VersionID, err)
+		}
+
+		fmt.Fprintf(w, "Removed delete marker: %s (VersionID: %s)\n", marker.Key, marker.VersionID)
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,73 @@
+package analyze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func TestTopLevelPrefix(t *testing.T) {
+	assert.Equal(t, "logs/", topLevelPrefix("logs/2024/app.log", ""))
+	assert.Equal(t, rootPrefix, topLevelPrefix("file.txt", ""))
+	assert.Equal(t, "data/a/", topLevelPrefix("data/a/b/c.txt", "data/"))
+	assert.Equal(t, "data/a/", topLevelPrefix("data/a/b/c.txt", "data"))
+	assert.Equal(t, "data/", topLevelPrefix("data/file.txt", "data/"))
+}
+
+func TestFindOrphanedDeleteMarkers(t *testing.T) {
+	now := time.Now()
+
+	objects := []*compare.ObjectInfo{
+		// Expired delete marker: the only remaining version
+		{Key: "logs/expired.txt", VersionID: "e1", IsLatest: true, IsDeleteMarker: true, LastModified: now},
+		// Stacked delete markers hiding an older version
+		{Key: "logs/stacked.txt", VersionID: "s1", IsLatest: true, IsDeleteMarker: true, LastModified: now},
+		{Key: "logs/stacked.txt", VersionID: "s2", IsDeleteMarker: true, LastModified: now.Add(-time.Hour)},
+		{Key: "logs/stacked.txt", VersionID: "s3", Size: 10, LastModified: now.Add(-2 * time.Hour)},
+		// Regular delete marker hiding data is not redundant
+		{Key: "data/hidden.txt", VersionID: "h1", IsLatest: true, IsDeleteMarker: true, LastModified: now},
+		{Key: "data/hidden.txt", VersionID: "h2", Size: 20, LastModified: now.Add(-time.Hour)},
+		// Only delete markers: newest is expired, the rest are stacked
+		{Key: "data/gone.txt", VersionID: "g2", IsDeleteMarker: true, LastModified: now.Add(-time.Hour)},
+		{Key: "data/gone.txt", VersionID: "g1", IsLatest: true, IsDeleteMarker: true, LastModified: now},
+		// Current object is untouched
+		{Key: "root.txt", VersionID: "r1", IsLatest: true, Size: 5, LastModified: now},
+	}
+
+	report := FindOrphanedDeleteMarkers(objects, "")
+
+	assert.Len(t, report.ExpiredMarkers, 2)
+	assert.Len(t, report.StackedMarkers, 2)
+	assert.Equal(t, 4, report.Total())
+
+	var expired, stacked []string
+	for _, marker := range report.ExpiredMarkers {
+		expired = append(expired, marker.VersionID)
+	}
+	for _, marker := range report.StackedMarkers {
+		stacked = append(stacked, marker.VersionID)
+	}
+	assert.ElementsMatch(t, []string{"e1", "g1"}, expired)
+	assert.ElementsMatch(t, []string{"s1", "g2"}, stacked)
+
+	assert.Equal(t, &DeleteMarkerCounts{Expired: 1, Stacked: 1}, report.ByPrefix["logs/"])
+	assert.Equal(t, &DeleteMarkerCounts{Expired: 1, Stacked: 1}, report.ByPrefix["data/"])
+	assert.NotContains(t, report.ByPrefix, rootPrefix)
+
+	assert.Len(t, report.Markers(), 4)
+}
+
+func TestFindOrphanedDeleteMarkersNone(t *testing.T) {
+	objects := []*compare.ObjectInfo{
+		{Key: "file.txt", VersionID: "v2", IsLatest: true, Size: 10},
+		{Key: "file.txt", VersionID: "v1", Size: 8},
+	}
+
+	report := FindOrphanedDeleteMarkers(objects, "")
+
+	assert.Equal(t, 0, report.Total())
+	assert.Empty(t, report.ByPrefix)
+}
@@ -0,0 +1,241 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// Differential analysis categories
+const (
+	DiffCurrentObjects     = "Current objects"
+	DiffNoncurrentVersions = "Noncurrent versions"
+	DiffDeleteMarkers      = "Delete markers"
+	DiffIncompleteUploads  = "Incomplete uploads"
+)
+
+// diffCategoryOrder is the order categories are reported in
+var diffCategoryOrder = []string{
+	DiffCurrentObjects,
+	DiffNoncurrentVersions,
+	DiffDeleteMarkers,
+	DiffIncompleteUploads,
+}
+
+// KeyDiff is a key whose entries in a category differ between source and target
+type KeyDiff struct {
+	Key         string
+	SourceCount int
+	TargetCount int
+	SourceSize  int64
+	TargetSize  int64
+}
+
+// CategoryDiff compares one category of entries between source and target
+type CategoryDiff struct {
+	Category    string
+	SourceCount int
+	TargetCount int
+	SourceSize  int64
+	TargetSize  int64
+	// Keys lists the keys responsible for the difference, largest size delta first
+	Keys []KeyDiff
+}
+
+// CountDelta returns the source count minus the target count
+func (c *CategoryDiff) CountDelta() int {
+	return c.SourceCount - c.TargetCount
+}
+
+// SizeDelta returns the source size minus the target size
+func (c *CategoryDiff) SizeDelta() int64 {
+	return c.SourceSize - c.TargetSize
+}
+
+// AnalysisDiff explains the object-count and size difference between two analyses
+type AnalysisDiff struct {
+	Source     string
+	Target     string
+	Categories []*CategoryDiff
+}
+
+// CountDelta returns the total difference in entries across all categories
+func (d *AnalysisDiff) CountDelta() int {
+	total := 0
+	for _, category := range d.Categories {
+		total += category.CountDelta()
+	}
+	return total
+}
+
+// SizeDelta returns the total difference in bytes across all categories
+func (d *AnalysisDiff) SizeDelta() int64 {
+	var total int64
+	for _, category := range d.Categories {
+		total += category.SizeDelta()
+	}
+	return total
+}
+
+// objectDiffCategory returns the differential category of a listed version
+func objectDiffCategory(obj *compare.ObjectInfo) string {
+	switch {
+	case obj.IsDeleteMarker:
+		return DiffDeleteMarkers
+	case obj.IsLatest:
+		return DiffCurrentObjects
+	default:
+		return DiffNoncurrentVersions
+	}
+}
+
+// keyTally accumulates per-key counts and sizes of one side of a category
+type keyTally struct {
+	count int
+	size  int64
+}
+
+// DiffAnalyses compares the listings and incomplete uploads of a source and a target,
+// attributing the difference to categories and keys. Keys are compared relative to
+// each side's prefix.
+func DiffAnalyses(source, target []*compare.ObjectInfo, sourceUploads, targetUploads []UploadUsage, sourcePrefix, targetPrefix string) *AnalysisDiff {
+	sourceTallies := make(map[string]map[string]*keyTally)
+	targetTallies := make(map[string]map[string]*keyTally)
+	for _, category := range diffCategoryOrder {
+		sourceTallies[category] = make(map[string]*keyTally)
+		targetTallies[category] = make(map[string]*keyTally)
+	}
+
+	tally := func(tallies map[string]map[string]*keyTally, category, key string, size int64) {
+		t, ok := tallies[category][key]
+		if !ok {
+			t = &keyTally{}
+			tallies[category][key] = t
+		}
+		t.count++
+		t.size += size
+	}
+
+	for _, obj := range source {
+		tally(sourceTallies, objectDiffCategory(obj), strings.TrimPrefix(obj.Key, sourcePrefix), obj.Size)
+	}
+	for _, obj := range target {
+		tally(targetTallies, objectDiffCategory(obj), strings.TrimPrefix(obj.Key, targetPrefix), obj.Size)
+	}
+	for _, upload := range sourceUploads {
+		tally(sourceTallies, DiffIncompleteUploads, strings.TrimPrefix(upload.Key, sourcePrefix), upload.PartsSize)
+	}
+	for _, upload := range targetUploads {
+		tally(targetTallies, DiffIncompleteUploads, strings.TrimPrefix(upload.Key, targetPrefix), upload.PartsSize)
+	}
+
+	diff := &AnalysisDiff{}
+	for _, category := range diffCategoryOrder {
+		categoryDiff := &CategoryDiff{Category: category}
+
+		keys := make(map[string]bool)
+		for key, t := range sourceTallies[category] {
+			keys[key] = true
+			categoryDiff.SourceCount += t.count
+			categoryDiff.SourceSize += t.size
+		}
+		for key, t := range targetTallies[category] {
+			keys[key] = true
+			categoryDiff.TargetCount += t.count
+			categoryDiff.TargetSize += t.size
+		}
+
+		for key := range keys {
+			keyDiff := KeyDiff{Key: key}
+			if t, ok := sourceTallies[category][key]; ok {
+				keyDiff.SourceCount, keyDiff.SourceSize = t.count, t.size
+			}
+			if t, ok := targetTallies[category][key]; ok {
+				keyDiff.TargetCount, keyDiff.TargetSize = t.count, t.size
+			}
+			if keyDiff.SourceCount != keyDiff.TargetCount || keyDiff.SourceSize != keyDiff.TargetSize {
+				categoryDiff.Keys = append(categoryDiff.Keys, keyDiff)
+			}
+		}
+
+		sort.Slice(categoryDiff.Keys, func(i, j int) bool {
+			a, b := categoryDiff.Keys[i], categoryDiff.Keys[j]
+			da, db := absInt64(a.SourceSize-a.TargetSize), absInt64(b.SourceSize-b.TargetSize)
+			if da != db {
+				return da > db
+			}
+			return a.Key < b.Key
+		})
+
+		diff.Categories = append(diff.Categories, categoryDiff)
+	}
+
+	return diff
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// DisplayAnalysisDiff displays the per-category difference and the keys responsible
+func DisplayAnalysisDiff(w io.Writer, diff *AnalysisDiff, verbose bool) {
+	fmt.Fprintf(w, "\nDifferential Analysis: %s vs %s\n", diff.Source, diff.Target)
+	fmt.Fprintln(w, "=================================")
+
+	fmt.Fprintf(w, "  %-20s %10s %10s %10s %16s %16s %16s\n",
+		"Category", "Source", "Target", "Delta", "Source Bytes", "Target Bytes", "Delta Bytes")
+	for _, c := range diff.Categories {
+		fmt.Fprintf(w, "  %-20s %10d %10d %+10d %16d %16d %+16d\n",
+			c.Category, c.SourceCount, c.TargetCount, c.CountDelta(), c.SourceSize, c.TargetSize, c.SizeDelta())
+	}
+	fmt.Fprintf(w, "  %-20s %10s %10s %+10d %16s %16s %+16d\n", "Total", "", "", diff.CountDelta(), "", "", diff.SizeDelta())
+
+	var countCauses, sizeCauses []string
+	for _, c := range diff.Categories {
+		if c.CountDelta() != 0 {
+			countCauses = append(countCauses, fmt.Sprintf("%s %+d", strings.ToLower(c.Category), c.CountDelta()))
+		}
+		if c.SizeDelta() != 0 {
+			sizeCauses = append(sizeCauses, fmt.Sprintf("%s %+d bytes", strings.ToLower(c.Category), c.SizeDelta()))
+		}
+	}
+
+	fmt.Fprintln(w)
+	if len(countCauses) == 0 && len(sizeCauses) == 0 {
+		fmt.Fprintln(w, "✅ No differences found")
+		return
+	}
+	if len(countCauses) > 0 {
+		fmt.Fprintf(w, "🔍 Object-count difference (%+d) comes from: %s\n", diff.CountDelta(), strings.Join(countCauses, ", "))
+	}
+	if len(sizeCauses) > 0 {
+		fmt.Fprintf(w, "🔍 Size difference (%+d bytes) comes from: %s\n", diff.SizeDelta(), strings.Join(sizeCauses, ", "))
+	}
+
+	limit := 10
+	if verbose {
+		limit = -1
+	}
+
+	for _, c := range diff.Categories {
+		if len(c.Keys) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "\n%s: %d keys differ\n", c.Category, len(c.Keys))
+		for i, k := range c.Keys {
+			if limit >= 0 && i >= limit {
+				fmt.Fprintf(w, "    ... %d more (use --verbose for all)\n", len(c.Keys)-limit)
+				break
+			}
+			fmt.Fprintf(w, "    %s: source %d (%d bytes), target %d (%d bytes)\n",
+				k.Key, k.SourceCount, k.SourceSize, k.TargetCount, k.TargetSize)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package analyze
+
+import (
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func TestDiffAnalyses(t *testing.T) {
+	source := []*compare.ObjectInfo{
+		{Key: "m1/a.txt", IsLatest: true, Size: 10},
+		{Key: "m1/a.txt", Size: 8},
+		{Key: "m1/b.txt", IsLatest: true, IsDeleteMarker: true},
+		{Key: "m1/b.txt", Size: 5},
+		{Key: "m1/c.txt", IsLatest: true, Size: 20},
+	}
+	target := []*compare.ObjectInfo{
+		{Key: "a.txt", IsLatest: true, Size: 10},
+		{Key: "c.txt", IsLatest: true, Size: 25},
+	}
+	sourceUploads := []UploadUsage{
+		{ObjectMultipartInfo: minio.ObjectMultipartInfo{Key: "m1/big.bin"}, PartsSize: 100},
+	}
+
+	diff := DiffAnalyses(source, target, sourceUploads, nil, "m1/", "")
+
+	require.Len(t, diff.Categories, 4)
+	byCategory := make(map[string]*CategoryDiff)
+	for _, c := range diff.Categories {
+		byCategory[c.Category] = c
+	}
+
+	current := byCategory[DiffCurrentObjects]
+	assert.Equal(t, 0, current.CountDelta())
+	assert.Equal(t, int64(-5), current.SizeDelta())
+	assert.Equal(t, []KeyDiff{{Key: "c.txt", SourceCount: 1, TargetCount: 1, SourceSize: 20, TargetSize: 25}}, current.Keys)
+
+	noncurrent := byCategory[DiffNoncurrentVersions]
+	assert.Equal(t, 2, noncurrent.CountDelta())
+	assert.Equal(t, int64(13), noncurrent.SizeDelta())
+	require.Len(t, noncurrent.Keys, 2)
+	assert.Equal(t, "a.txt", noncurrent.Keys[0].Key)
+
+	markers := byCategory[DiffDeleteMarkers]
+	assert.Equal(t, 1, markers.CountDelta())
+	assert.Equal(t, []KeyDiff{{Key: "b.txt", SourceCount: 1}}, markers.Keys)
+
+	uploads := byCategory[DiffIncompleteUploads]
+	assert.Equal(t, 1, uploads.CountDelta())
+	assert.Equal(t, "big.bin", uploads.Keys[0].Key)
+
+	assert.Equal(t, 4, diff.CountDelta())
+	assert.Equal(t, int64(108), diff.SizeDelta())
+}
+
+func TestDiffAnalysesIdentical(t *testing.T) {
+	objects := []*compare.ObjectInfo{
+		{Key: "a.txt", IsLatest: true, Size: 10},
+	}
+
+	diff := DiffAnalyses(objects, objects, nil, nil, "", "")
+
+	assert.Equal(t, 0, diff.CountDelta())
+	assert.Equal(t, int64(0), diff.SizeDelta())
+	for _, c := range diff.Categories {
+		assert.Empty(t, c.Keys)
+	}
+}
@@ -0,0 +1,224 @@
+package analyze
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/liamdn8/mc-tool/pkg/client"
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// Object dump states
+const (
+	DumpAll           = "all"
+	DumpCurrent       = "current"
+	DumpNoncurrent    = "noncurrent"
+	DumpDeleteMarkers = "delete-markers"
+)
+
+// DumpOptions controls which listed versions are dumped and how
+type DumpOptions struct {
+	// Limit is the maximum number of keys dumped; 0 means no limit
+	Limit int
+	// StartAfter skips keys up to and including this key
+	StartAfter string
+	// State restricts the dump to versions in this state
+	State string
+	// CSV writes the dump as CSV instead of text
+	CSV bool
+}
+
+// DumpSummary describes what a dump wrote
+type DumpSummary struct {
+	Keys    int
+	Entries int
+	// LastKey is the last key dumped, to continue from with StartAfter
+	LastKey string
+	// Truncated is true when more matching keys follow LastKey
+	Truncated bool
+}
+
+// ParseDumpState validates a dump state
+func ParseDumpState(state string) (string, error) {
+	switch state {
+	case "", DumpAll:
+		return DumpAll, nil
+	case DumpCurrent, DumpNoncurrent, DumpDeleteMarkers:
+		return state, nil
+	default:
+		return "", fmt.Errorf("invalid state: %s (expected %s, %s, %s or %s)",
+			state, DumpAll, DumpCurrent, DumpNoncurrent, DumpDeleteMarkers)
+	}
+}
+
+// versionState returns the dump state of a listed version
+func versionState(obj *compare.ObjectInfo) string {
+	switch {
+	case obj.IsDeleteMarker:
+		return DumpDeleteMarkers
+	case obj.IsLatest:
+		return DumpCurrent
+	default:
+		return DumpNoncurrent
+	}
+}
+
+// versionStatus returns the label of a listed version in the text dump
+func versionStatus(obj *compare.ObjectInfo) string {
+	status := ""
+	if obj.IsLatest {
+		status += "[CURRENT]"
+	}
+	if obj.IsDeleteMarker {
+		status += "[DELETE_MARKER]"
+	}
+	if status == "" {
+		status = "[OLD_VERSION]"
+	}
+	return status
+}
+
+var dumpCSVHeader = []string{
+	"Key", "VersionId", "State", "IsLatest", "IsDeleteMarker", "Size", "ETag", "LastModifiedDate", "StorageClass",
+}
+
+// objectDumper writes versions in listing order, buffering only the versions of the current key
+type objectDumper struct {
+	w       io.Writer
+	csv     *csv.Writer
+	opts    DumpOptions
+	key     string
+	pending []*compare.ObjectInfo
+	summary DumpSummary
+	err     error
+}
+
+func newObjectDumper(w io.Writer, opts DumpOptions) *objectDumper {
+	if opts.State == "" {
+		opts.State = DumpAll
+	}
+
+	d := &objectDumper{w: w, opts: opts}
+	if opts.CSV {
+		d.csv = csv.NewWriter(w)
+		d.err = d.csv.Write(dumpCSVHeader)
+	}
+	return d
+}
+
+// add dumps a version and returns false once no more versions are wanted
+func (d *objectDumper) add(obj *compare.ObjectInfo) bool {
+	if d.err != nil {
+		return false
+	}
+
+	if d.opts.StartAfter != "" && obj.Key <= d.opts.StartAfter {
+		return true
+	}
+
+	if d.opts.State != DumpAll && versionState(obj) != d.opts.State {
+		return true
+	}
+
+	if obj.Key != d.key {
+		d.flush()
+		if d.opts.Limit > 0 && d.summary.Keys >= d.opts.Limit {
+			d.summary.Truncated = true
+			return false
+		}
+		d.key = obj.Key
+	}
+
+	d.pending = append(d.pending, obj)
+	return d.err == nil
+}
+
+// flush writes the buffered versions of the current key
+func (d *objectDumper) flush() {
+	if len(d.pending) == 0 || d.err != nil {
+		return
+	}
+
+	if d.csv != nil {
+		for _, obj := range d.pending {
+			d.err = d.csv.Write([]string{
+				obj.Key,
+				obj.VersionID,
+				versionState(obj),
+				strconv.FormatBool(obj.IsLatest),
+				strconv.FormatBool(obj.IsDeleteMarker),
+				strconv.FormatInt(obj.Size, 10),
+				obj.ETag,
+				obj.LastModified.UTC().Format(time.RFC3339),
+				obj.StorageClass,
+			})
+			if d.err != nil {
+				return
+			}
+		}
+	} else {
+		_, d.err = fmt.Fprintf(d.w, "\nObject: %s\n  Total versions: %d\n", d.key, len(d.pending))
+		for i, obj := range d.pending {
+			if d.err != nil {
+				return
+			}
+			_, d.err = fmt.Fprintf(d.w, "  %d. %s Size: %d, ETag: %s, VersionID: %s, Modified: %s\n",
+				i+1, versionStatus(obj), obj.Size, obj.ETag, obj.VersionID,
+				obj.LastModified.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	d.summary.Keys++
+	d.summary.Entries += len(d.pending)
+	d.summary.LastKey = d.key
+	d.pending = d.pending[:0]
+}
+
+// close flushes the last key and returns the dump summary
+func (d *objectDumper) close() (*DumpSummary, error) {
+	d.flush()
+	if d.csv != nil && d.err == nil {
+		d.csv.Flush()
+		d.err = d.csv.Error()
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("failed to write object dump: %w", d.err)
+	}
+	return &d.summary, nil
+}
+
+// DumpObjects writes already loaded versions, e.g. from an inventory report. Versions
+// must be grouped by key in ascending key order.
+func DumpObjects(objects []*compare.ObjectInfo, opts DumpOptions, w io.Writer) (*DumpSummary, error) {
+	dumper := newObjectDumper(w, opts)
+	for _, obj := range objects {
+		if !dumper.add(obj) {
+			break
+		}
+	}
+
+	return dumper.close()
+}
+
+// StreamObjectDump lists a bucket's versions after opts.StartAfter and writes them in key
+// order as they are listed, so memory use does not grow with the number of keys
+func StreamObjectDump(ctx context.Context, lister *client.VersionLister, bucket, prefix string, opts DumpOptions, w io.Writer) (*DumpSummary, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	dumper := newObjectDumper(w, opts)
+	for objInfo := range lister.ListVersions(ctx, bucket, prefix, opts.StartAfter) {
+		if objInfo.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", objInfo.Err)
+		}
+		if !dumper.add(compare.NewObjectInfo(objInfo)) {
+			break
+		}
+	}
+
+	return dumper.close()
+}
@@ -0,0 +1,92 @@
+package analyze
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// dumpListing is in the order a versioned listing returns entries: keys ascending, newest version first
+func dumpListing() []*compare.ObjectInfo {
+	modified := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	return []*compare.ObjectInfo{
+		{Key: "a.txt", VersionID: "a2", IsLatest: true, Size: 10, LastModified: modified},
+		{Key: "a.txt", VersionID: "a1", Size: 8, LastModified: modified},
+		{Key: "b.txt", VersionID: "b2", IsLatest: true, IsDeleteMarker: true, LastModified: modified},
+		{Key: "b.txt", VersionID: "b1", Size: 5, LastModified: modified},
+		{Key: "c.txt", VersionID: "c1", IsLatest: true, Size: 20, LastModified: modified},
+		{Key: "d.txt", VersionID: "d2", IsLatest: true, IsDeleteMarker: true, LastModified: modified},
+	}
+}
+
+func runDumper(t *testing.T, opts DumpOptions) (*DumpSummary, string) {
+	var buf bytes.Buffer
+	dumper := newObjectDumper(&buf, opts)
+	for _, obj := range dumpListing() {
+		if !dumper.add(obj) {
+			break
+		}
+	}
+	summary, err := dumper.close()
+	require.NoError(t, err)
+	return summary, buf.String()
+}
+
+func TestParseDumpState(t *testing.T) {
+	state, err := ParseDumpState("")
+	require.NoError(t, err)
+	assert.Equal(t, DumpAll, state)
+
+	state, err = ParseDumpState(DumpDeleteMarkers)
+	require.NoError(t, err)
+	assert.Equal(t, DumpDeleteMarkers, state)
+
+	_, err = ParseDumpState("hidden")
+	assert.Error(t, err)
+}
+
+func TestObjectDumperText(t *testing.T) {
+	summary, out := runDumper(t, DumpOptions{})
+
+	assert.Equal(t, &DumpSummary{Keys: 4, Entries: 6, LastKey: "d.txt"}, summary)
+	assert.Less(t, strings.Index(out, "Object: a.txt"), strings.Index(out, "Object: b.txt"))
+	assert.Contains(t, out, "Object: b.txt\n  Total versions: 2\n  1. [CURRENT][DELETE_MARKER]")
+	assert.Contains(t, out, "2. [OLD_VERSION] Size: 5")
+}
+
+func TestObjectDumperPagination(t *testing.T) {
+	summary, out := runDumper(t, DumpOptions{Limit: 2})
+	assert.Equal(t, &DumpSummary{Keys: 2, Entries: 4, LastKey: "b.txt", Truncated: true}, summary)
+	assert.NotContains(t, out, "c.txt")
+
+	summary, out = runDumper(t, DumpOptions{Limit: 2, StartAfter: summary.LastKey})
+	assert.Equal(t, &DumpSummary{Keys: 2, Entries: 2, LastKey: "d.txt"}, summary)
+	assert.NotContains(t, out, "b.txt")
+}
+
+func TestObjectDumperStateFilter(t *testing.T) {
+	summary, out := runDumper(t, DumpOptions{State: DumpDeleteMarkers})
+	assert.Equal(t, &DumpSummary{Keys: 2, Entries: 2, LastKey: "d.txt"}, summary)
+	assert.NotContains(t, out, "a.txt")
+
+	summary, _ = runDumper(t, DumpOptions{State: DumpNoncurrent, Limit: 1})
+	assert.Equal(t, &DumpSummary{Keys: 1, Entries: 1, LastKey: "a.txt", Truncated: true}, summary)
+}
+
+func TestObjectDumperCSV(t *testing.T) {
+	_, out := runDumper(t, DumpOptions{CSV: true, State: DumpCurrent})
+
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, dumpCSVHeader, records[0])
+	assert.Equal(t, []string{"a.txt", "a2", DumpCurrent, "true", "false", "10", "", "2024-03-01T10:00:00Z", ""}, records[1])
+	assert.Equal(t, "c.txt", records[2][0])
+}
@@ -0,0 +1,159 @@
+package analyze
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// DuplicateGroup is a set of current objects sharing the same content
+type DuplicateGroup struct {
+	ETag     string
+	Size     int64
+	Hashed   bool // content identity was established by hashing multipart objects
+	Objects  []*compare.ObjectInfo
+	Prefixes []string
+}
+
+// WastedBytes returns the bytes stored beyond a single copy of the content
+func (g *DuplicateGroup) WastedBytes() int64 {
+	return int64(len(g.Objects)-1) * g.Size
+}
+
+// DuplicateReport summarises duplicate content across keys
+type DuplicateReport struct {
+	Groups           []*DuplicateGroup
+	DuplicateObjects int
+	WastedBytes      int64
+}
+
+// isMultipartETag reports whether an ETag was produced by a multipart upload,
+// in which case it is not the MD5 of the content
+func isMultipartETag(etag string) bool {
+	return strings.Contains(strings.Trim(etag, `"`), "-")
+}
+
+// HashMultipartObjects downloads current objects with multipart ETags and computes
+// the MD5 of their content, so they can be matched against single-part uploads
+func HashMultipartObjects(ctx context.Context, client *minio.Client, bucket string, objects []*compare.ObjectInfo) (map[*compare.ObjectInfo]string, error) {
+	hashes := make(map[*compare.ObjectInfo]string)
+
+	for _, obj := range objects {
+		if !obj.IsLatest || obj.IsDeleteMarker || obj.Size == 0 || !isMultipartETag(obj.ETag) {
+			continue
+		}
+
+		reader, err := client.GetObject(ctx, bucket, obj.Key, minio.GetObjectOptions{VersionID: obj.VersionID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", obj.Key, err)
+		}
+
+		hash := md5.New()
+		_, err = io.Copy(hash, reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", obj.Key, err)
+		}
+
+		hashes[obj] = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	return hashes, nil
+}
+
+// FindDuplicates groups current objects by ETag and size. Objects with an entry in
+// hashes are grouped by their content MD5 instead, which matches single-part ETags.
+func FindDuplicates(objects []*compare.ObjectInfo, prefix string, hashes map[*compare.ObjectInfo]string) *DuplicateReport {
+	groups := make(map[string]*DuplicateGroup)
+
+	for _, obj := range objects {
+		if !obj.IsLatest || obj.IsDeleteMarker || obj.Size == 0 {
+			continue
+		}
+
+		etag := strings.Trim(obj.ETag, `"`)
+		hashed := false
+		if hash, ok := hashes[obj]; ok {
+			etag = hash
+			hashed = true
+		}
+
+		groupKey := fmt.Sprintf("%s:%d", etag, obj.Size)
+		group := groups[groupKey]
+		if group == nil {
+			group = &DuplicateGroup{ETag: etag, Size: obj.Size}
+			groups[groupKey] = group
+		}
+		group.Objects = append(group.Objects, obj)
+		group.Hashed = group.Hashed || hashed
+	}
+
+	report := &DuplicateReport{}
+	for _, group := range groups {
+		if len(group.Objects) < 2 {
+			continue
+		}
+
+		prefixes := make(map[string]bool)
+		for _, obj := range group.Objects {
+			prefixes[topLevelPrefix(obj.Key, prefix)] = true
+		}
+		for p := range prefixes {
+			group.Prefixes = append(group.Prefixes, p)
+		}
+		sort.Strings(group.Prefixes)
+		sort.Slice(group.Objects, func(i, j int) bool {
+			return group.Objects[i].Key < group.Objects[j].Key
+		})
+
+		report.Groups = append(report.Groups, group)
+		report.DuplicateObjects += len(group.Objects) - 1
+		report.WastedBytes += group.WastedBytes()
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].WastedBytes() != report.Groups[j].WastedBytes() {
+			return report.Groups[i].WastedBytes() > report.Groups[j].WastedBytes()
+		}
+		return report.Groups[i].Objects[0].Key < report.Groups[j].Objects[0].Key
+	})
+
+	return report
+}
+
+// DisplayDuplicateReport displays duplicate content groups, largest waste first
+func DisplayDuplicateReport(w io.Writer, report *DuplicateReport, verbose bool) {
+	fmt.Fprintln(w, "\nDuplicate Content:")
+	fmt.Fprintln(w, "==================")
+	fmt.Fprintf(w, "Duplicate groups: %d\n", len(report.Groups))
+	fmt.Fprintf(w, "Redundant copies: %d\n", report.DuplicateObjects)
+	fmt.Fprintf(w, "Wasted bytes: %d\n", report.WastedBytes)
+
+	groups := report.Groups
+	if !verbose && len(groups) > 10 {
+		groups = groups[:10]
+		fmt.Fprintln(w, "\nTop 10 groups by wasted bytes (use --verbose for all):")
+	} else if len(groups) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	for _, group := range groups {
+		hashed := ""
+		if group.Hashed {
+			hashed = ", content hashed"
+		}
+		fmt.Fprintf(w, "- %d copies of %d bytes (ETag: %s%s), wasted %d bytes, prefixes: %s\n",
+			len(group.Objects), group.Size, group.ETag, hashed, group.WastedBytes(), strings.Join(group.Prefixes, ", "))
+		for _, obj := range group.Objects {
+			fmt.Fprintf(w, "    %s\n", obj.Key)
+		}
+	}
+}
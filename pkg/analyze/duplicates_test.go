@@ -0,0 +1,63 @@
+package analyze
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func TestIsMultipartETag(t *testing.T) {
+	assert.True(t, isMultipartETag(`"d41d8cd98f00b204e9800998ecf8427e-3"`))
+	assert.False(t, isMultipartETag(`"d41d8cd98f00b204e9800998ecf8427e"`))
+	assert.False(t, isMultipartETag(""))
+}
+
+func TestFindDuplicates(t *testing.T) {
+	objects := []*compare.ObjectInfo{
+		{Key: "raw/a.csv", ETag: `"aaa"`, Size: 100, IsLatest: true},
+		{Key: "copy/a.csv", ETag: `"aaa"`, Size: 100, IsLatest: true},
+		{Key: "backup/a.csv", ETag: "aaa", Size: 100, IsLatest: true},
+		// Same ETag but different size is not a duplicate
+		{Key: "raw/b.csv", ETag: `"aaa"`, Size: 200, IsLatest: true},
+		// Old versions and delete markers are ignored
+		{Key: "raw/c.csv", ETag: `"aaa"`, Size: 100},
+		{Key: "raw/d.csv", IsLatest: true, IsDeleteMarker: true},
+		// Zero-byte objects are ignored
+		{Key: "raw/empty1", ETag: `"e"`, IsLatest: true},
+		{Key: "raw/empty2", ETag: `"e"`, IsLatest: true},
+		// Smaller duplicate group
+		{Key: "raw/x.bin", ETag: `"xxx"`, Size: 10, IsLatest: true},
+		{Key: "raw/y.bin", ETag: `"xxx"`, Size: 10, IsLatest: true},
+	}
+
+	report := FindDuplicates(objects, "", nil)
+
+	require.Len(t, report.Groups, 2)
+	assert.Equal(t, 3, report.DuplicateObjects)
+	assert.Equal(t, int64(210), report.WastedBytes)
+
+	largest := report.Groups[0]
+	assert.Equal(t, int64(200), largest.WastedBytes())
+	assert.Equal(t, []string{"backup/", "copy/", "raw/"}, largest.Prefixes)
+	assert.Equal(t, "backup/a.csv", largest.Objects[0].Key)
+
+	assert.Equal(t, []string{"raw/"}, report.Groups[1].Prefixes)
+}
+
+func TestFindDuplicatesWithContentHashes(t *testing.T) {
+	single := &compare.ObjectInfo{Key: "a/file.bin", ETag: `"5d41402abc4b2a76b9719d911017c592"`, Size: 5, IsLatest: true}
+	multipart := &compare.ObjectInfo{Key: "b/file.bin", ETag: `"0123456789abcdef0123456789abcdef-2"`, Size: 5, IsLatest: true}
+
+	report := FindDuplicates([]*compare.ObjectInfo{single, multipart}, "", nil)
+	assert.Empty(t, report.Groups)
+
+	hashes := map[*compare.ObjectInfo]string{multipart: "5d41402abc4b2a76b9719d911017c592"}
+	report = FindDuplicates([]*compare.ObjectInfo{single, multipart}, "", hashes)
+
+	require.Len(t, report.Groups, 1)
+	assert.True(t, report.Groups[0].Hashed)
+	assert.Equal(t, int64(5), report.WastedBytes)
+}
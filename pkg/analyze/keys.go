@@ -0,0 +1,227 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// maxKeyLength is the S3 limit on object key length in bytes
+const maxKeyLength = 1024
+
+// longKeyThreshold flags keys approaching maxKeyLength
+const longKeyThreshold = 900
+
+// Key hygiene issues
+const (
+	KeyIssueInvalidUTF8     = "invalid UTF-8"
+	KeyIssueNotNFC          = "not NFC-normalized unicode"
+	KeyIssueLeadingSlash    = "leading slash"
+	KeyIssueTrailingSlash   = "trailing slash on non-empty object"
+	KeyIssueSegmentSpaces   = "leading/trailing spaces in a path segment"
+	KeyIssueDoubleSlash     = "'//' sequence"
+	KeyIssueLongKey         = "key length near the 1024-byte limit"
+	KeyIssueDirectoryObject = "directory object (zero-byte key ending with '/')"
+)
+
+// keyIssueOrder is the order issues are displayed in
+var keyIssueOrder = []string{
+	KeyIssueInvalidUTF8,
+	KeyIssueNotNFC,
+	KeyIssueLeadingSlash,
+	KeyIssueTrailingSlash,
+	KeyIssueSegmentSpaces,
+	KeyIssueDoubleSlash,
+	KeyIssueLongKey,
+	KeyIssueDirectoryObject,
+}
+
+// KeyHygieneReport lists current keys that are problematic for downstream tools
+type KeyHygieneReport struct {
+	CheckedKeys int
+	// Issues maps each issue to the keys exhibiting it
+	Issues map[string][]string
+	// CaseCollisions groups keys, or directory prefixes ending with '/', that are
+	// identical when compared case-insensitively
+	CaseCollisions [][]string
+}
+
+// TotalIssues returns the number of flagged keys and collision groups
+func (r *KeyHygieneReport) TotalIssues() int {
+	total := len(r.CaseCollisions)
+	for _, keys := range r.Issues {
+		total += len(keys)
+	}
+	return total
+}
+
+// checkKey returns the hygiene issues of a single key
+func checkKey(obj *compare.ObjectInfo) []string {
+	key := obj.Key
+	var issues []string
+
+	if !utf8.ValidString(key) {
+		issues = append(issues, KeyIssueInvalidUTF8)
+	} else if !norm.NFC.IsNormalString(key) {
+		issues = append(issues, KeyIssueNotNFC)
+	}
+
+	if strings.HasPrefix(key, "/") {
+		issues = append(issues, KeyIssueLeadingSlash)
+	}
+
+	if strings.HasSuffix(key, "/") {
+		if obj.Size == 0 {
+			issues = append(issues, KeyIssueDirectoryObject)
+		} else {
+			issues = append(issues, KeyIssueTrailingSlash)
+		}
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment != strings.TrimSpace(segment) {
+			issues = append(issues, KeyIssueSegmentSpaces)
+			break
+		}
+	}
+
+	if strings.Contains(key, "//") {
+		issues = append(issues, KeyIssueDoubleSlash)
+	}
+
+	if len(key) >= longKeyThreshold {
+		issues = append(issues, KeyIssueLongKey)
+	}
+
+	return issues
+}
+
+// AnalyzeKeyHygiene flags current keys with encoding problems, unusual slashes or
+// spaces, excessive length, case-insensitive collisions and directory objects
+func AnalyzeKeyHygiene(objects []*compare.ObjectInfo) *KeyHygieneReport {
+	report := &KeyHygieneReport{
+		Issues: make(map[string][]string),
+	}
+
+	// pathsByFold maps each folded key and directory prefix to the distinct paths folding to it
+	pathsByFold := make(map[string]map[string]bool)
+	addPath := func(path string) {
+		folded := strings.ToLower(norm.NFC.String(path))
+		if pathsByFold[folded] == nil {
+			pathsByFold[folded] = make(map[string]bool)
+		}
+		pathsByFold[folded][path] = true
+	}
+
+	for _, obj := range objects {
+		if !obj.IsLatest || obj.IsDeleteMarker {
+			continue
+		}
+		report.CheckedKeys++
+
+		for _, issue := range checkKey(obj) {
+			report.Issues[issue] = append(report.Issues[issue], obj.Key)
+		}
+
+		// Keys under directories differing only in case, such as Reports/a.csv and
+		// reports/b.csv, collide on case-insensitive filesystems too
+		for i, c := range obj.Key {
+			if c == '/' {
+				addPath(obj.Key[:i+1])
+			}
+		}
+		addPath(obj.Key)
+	}
+
+	for _, paths := range pathsByFold {
+		if len(paths) < 2 {
+			continue
+		}
+		group := make([]string, 0, len(paths))
+		for path := range paths {
+			group = append(group, path)
+		}
+		sort.Strings(group)
+		// Paths differing only in a parent directory are reported by that directory's group
+		if !sameLastSegment(group) {
+			report.CaseCollisions = append(report.CaseCollisions, group)
+		}
+	}
+	sort.Slice(report.CaseCollisions, func(i, j int) bool {
+		return report.CaseCollisions[i][0] < report.CaseCollisions[j][0]
+	})
+
+	for _, keys := range report.Issues {
+		sort.Strings(keys)
+	}
+
+	return report
+}
+
+// sameLastSegment reports whether the paths share their final path segment exactly
+func sameLastSegment(paths []string) bool {
+	last := func(path string) string {
+		path = strings.TrimSuffix(path, "/")
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	for _, path := range paths[1:] {
+		if last(path) != last(paths[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// DisplayKeyHygieneReport displays the flagged keys grouped by issue
+func DisplayKeyHygieneReport(w io.Writer, report *KeyHygieneReport, verbose bool) {
+	fmt.Fprintln(w, "\nKey Naming Hygiene:")
+	fmt.Fprintln(w, "===================")
+	fmt.Fprintf(w, "Current keys checked: %d\n", report.CheckedKeys)
+
+	if report.TotalIssues() == 0 {
+		fmt.Fprintln(w, "✅ No problematic keys found")
+		return
+	}
+
+	limit := 5
+	if verbose {
+		limit = -1
+	}
+
+	for _, issue := range keyIssueOrder {
+		keys := report.Issues[issue]
+		if len(keys) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "\n⚠ %s: %d\n", issue, len(keys))
+		for i, key := range keys {
+			if limit >= 0 && i >= limit {
+				fmt.Fprintf(w, "    ... %d more (use --verbose for all)\n", len(keys)-limit)
+				break
+			}
+			fmt.Fprintf(w, "    %q\n", key)
+		}
+	}
+
+	if len(report.CaseCollisions) > 0 {
+		fmt.Fprintf(w, "\n⚠ case-insensitive collisions: %d groups\n", len(report.CaseCollisions))
+		for i, keys := range report.CaseCollisions {
+			if limit >= 0 && i >= limit {
+				fmt.Fprintf(w, "    ... %d more (use --verbose for all)\n", len(report.CaseCollisions)-limit)
+				break
+			}
+			quoted := make([]string, len(keys))
+			for k, key := range keys {
+				quoted[k] = fmt.Sprintf("%q", key)
+			}
+			fmt.Fprintf(w, "    %s\n", strings.Join(quoted, " <-> "))
+		}
+	}
+}
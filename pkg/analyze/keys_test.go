@@ -0,0 +1,65 @@
+package analyze
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func TestCheckKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		size     int64
+		expected []string
+	}{
+		{name: "clean key", key: "data/2024/file.csv", size: 1},
+		{name: "invalid utf-8", key: "data/\xff.csv", size: 1, expected: []string{KeyIssueInvalidUTF8}},
+		{name: "decomposed unicode", key: "cafe\u0301.txt", size: 1, expected: []string{KeyIssueNotNFC}},
+		{name: "composed unicode", key: "caf\u00e9.txt", size: 1},
+		{name: "leading slash", key: "/data/file", size: 1, expected: []string{KeyIssueLeadingSlash}},
+		{name: "trailing slash", key: "data/file/", size: 10, expected: []string{KeyIssueTrailingSlash}},
+		{name: "directory object", key: "data/dir/", size: 0, expected: []string{KeyIssueDirectoryObject}},
+		{name: "segment spaces", key: "data /file.txt ", size: 1, expected: []string{KeyIssueSegmentSpaces}},
+		{name: "double slash", key: "data//file", size: 1, expected: []string{KeyIssueDoubleSlash}},
+		{name: "long key", key: strings.Repeat("a", longKeyThreshold), size: 1, expected: []string{KeyIssueLongKey}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := checkKey(&compare.ObjectInfo{Key: tt.key, Size: tt.size})
+			assert.Equal(t, tt.expected, issues)
+		})
+	}
+}
+
+func TestAnalyzeKeyHygiene(t *testing.T) {
+	objects := []*compare.ObjectInfo{
+		{Key: "Reports/Q1.csv", IsLatest: true, Size: 1},
+		{Key: "reports/q1.csv", IsLatest: true, Size: 1},
+		{Key: "reports/Q2.csv", IsLatest: true, Size: 1},
+		{Key: "reports/dir/", IsLatest: true},
+		// Different files, but Logs/ and logs/ are one directory on case-insensitive filesystems
+		{Key: "Logs/a.csv", IsLatest: true, Size: 1},
+		{Key: "logs/2024/b.csv", IsLatest: true, Size: 1},
+		{Key: "logs/2024/c.csv", IsLatest: true, Size: 1},
+		// Old versions and deleted keys are not checked
+		{Key: "REPORTS/Q2.csv", Size: 1},
+		{Key: "/deleted", IsLatest: true, IsDeleteMarker: true},
+	}
+
+	report := AnalyzeKeyHygiene(objects)
+
+	assert.Equal(t, 7, report.CheckedKeys)
+	assert.Equal(t, [][]string{
+		{"Logs/", "logs/"},
+		{"Reports/", "reports/"},
+		{"Reports/Q1.csv", "reports/q1.csv"},
+	}, report.CaseCollisions)
+	assert.Equal(t, []string{"reports/dir/"}, report.Issues[KeyIssueDirectoryObject])
+	assert.NotContains(t, report.Issues, KeyIssueLeadingSlash)
+	assert.Equal(t, 4, report.TotalIssues())
+}
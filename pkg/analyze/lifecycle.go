@@ -0,0 +1,291 @@
+package analyze
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// LifecycleActionStats counts the objects and bytes affected by a lifecycle action
+type LifecycleActionStats struct {
+	Objects int
+	Bytes   int64
+}
+
+func (s *LifecycleActionStats) add(obj *compare.ObjectInfo) {
+	s.Objects++
+	s.Bytes += obj.Size
+}
+
+// LifecycleRuleResult holds the simulated effect of a single lifecycle rule
+type LifecycleRuleResult struct {
+	ID      string
+	Status  string
+	Prefix  string
+	Skipped string // reason the rule was not evaluated, if any
+
+	Expired                LifecycleActionStats
+	Transitioned           LifecycleActionStats
+	NoncurrentExpired      LifecycleActionStats
+	NoncurrentTransitioned LifecycleActionStats
+	DeleteMarkersRemoved   LifecycleActionStats
+}
+
+// LifecycleSimulation holds the simulated effect of a lifecycle configuration at a point in time.
+// Totals count each object version once even when several rules apply to it.
+type LifecycleSimulation struct {
+	At    time.Time
+	Rules []LifecycleRuleResult
+
+	Expired                LifecycleActionStats
+	Transitioned           LifecycleActionStats
+	NoncurrentExpired      LifecycleActionStats
+	NoncurrentTransitioned LifecycleActionStats
+	DeleteMarkersRemoved   LifecycleActionStats
+
+	// TransitionsByClass totals transitioned bytes per target storage class
+	TransitionsByClass map[string]*LifecycleActionStats
+
+	// Removed records the noncurrent versions and delete markers that would be deleted,
+	// ExpiredCurrent the expired current versions and TransitionedTo the transitioned ones
+	Removed        []*compare.ObjectInfo
+	ExpiredCurrent []*compare.ObjectInfo
+	TransitionedTo map[*compare.ObjectInfo]string
+}
+
+// LoadLifecycleConfig reads a candidate lifecycle configuration from an XML or JSON file
+func LoadLifecycleConfig(path string) (*lifecycle.Configuration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lifecycle file: %v", err)
+	}
+
+	config := lifecycle.NewConfiguration()
+	trimmed := strings.TrimSpace(string(data))
+
+	if strings.HasPrefix(trimmed, "<") {
+		if err := xml.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse lifecycle XML: %v", err)
+		}
+	} else {
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse lifecycle JSON: %v", err)
+		}
+	}
+
+	return config, nil
+}
+
+// lifecycleRulePrefix returns the key prefix a rule applies to
+func lifecycleRulePrefix(rule lifecycle.Rule) string {
+	if rule.RuleFilter.And.Prefix != "" {
+		return rule.RuleFilter.And.Prefix
+	}
+	if rule.RuleFilter.Prefix != "" {
+		return rule.RuleFilter.Prefix
+	}
+	return rule.Prefix
+}
+
+// lifecycleRuleHasTagFilter reports whether a rule filters on object tags
+func lifecycleRuleHasTagFilter(rule lifecycle.Rule) bool {
+	return !rule.RuleFilter.Tag.IsEmpty() || len(rule.RuleFilter.And.Tags) > 0
+}
+
+// lifecycleDue returns when an action configured in days becomes due, rounded up
+// to the next midnight UTC as done by S3 and MinIO
+func lifecycleDue(from time.Time, days int) time.Time {
+	return from.UTC().Add(time.Duration(days+1) * 24 * time.Hour).Truncate(24 * time.Hour)
+}
+
+func expirationDue(expiration lifecycle.Expiration, obj *compare.ObjectInfo, at time.Time) bool {
+	if !expiration.IsDateNull() {
+		return !at.Before(expiration.Date.Time)
+	}
+	if !expiration.IsDaysNull() {
+		return !at.Before(lifecycleDue(obj.LastModified, int(expiration.Days)))
+	}
+	return false
+}
+
+func transitionDue(transition lifecycle.Transition, obj *compare.ObjectInfo, at time.Time) bool {
+	if transition.IsNull() || strings.EqualFold(obj.StorageClass, transition.StorageClass) {
+		return false
+	}
+	if !transition.IsDateNull() {
+		return !at.Before(transition.Date.Time)
+	}
+	return !at.Before(lifecycleDue(obj.LastModified, int(transition.Days)))
+}
+
+// SimulateLifecycle evaluates each enabled lifecycle rule against the listed object
+// versions and reports what would be expired or transitioned at the given time
+func SimulateLifecycle(config *lifecycle.Configuration, objects []*compare.ObjectInfo, at time.Time) *LifecycleSimulation {
+	sim := &LifecycleSimulation{
+		At:                 at,
+		TransitionsByClass: make(map[string]*LifecycleActionStats),
+		TransitionedTo:     make(map[*compare.ObjectInfo]string),
+	}
+
+	for _, rule := range config.Rules {
+		result := LifecycleRuleResult{
+			ID:     rule.ID,
+			Status: rule.Status,
+			Prefix: lifecycleRulePrefix(rule),
+		}
+		if rule.Status != "Enabled" {
+			result.Skipped = "rule is disabled"
+		} else if lifecycleRuleHasTagFilter(rule) {
+			result.Skipped = "tag filters cannot be evaluated from a listing"
+		}
+		sim.Rules = append(sim.Rules, result)
+	}
+
+	keys, versionsByKey := groupVersionsByKey(objects)
+
+	for _, key := range keys {
+		versions := versionsByKey[key]
+
+		for i, version := range versions {
+			expired, transitioned, removed := false, "", false
+
+			for r, rule := range config.Rules {
+				result := &sim.Rules[r]
+				if result.Skipped != "" || !strings.HasPrefix(key, result.Prefix) {
+					continue
+				}
+
+				switch {
+				case i == 0 && version.IsDeleteMarker:
+					// Expired object delete marker: the only remaining version of the key
+					if len(versions) == 1 && rule.Expiration.IsDeleteMarkerExpirationEnabled() {
+						result.DeleteMarkersRemoved.add(version)
+						removed = true
+					}
+				case i == 0:
+					if expirationDue(rule.Expiration, version, at) {
+						result.Expired.add(version)
+						expired = true
+					} else if transitionDue(rule.Transition, version, at) {
+						result.Transitioned.add(version)
+						if transitioned == "" {
+							transitioned = rule.Transition.StorageClass
+						}
+					}
+				default:
+					// A version becomes noncurrent when its successor is written
+					noncurrentSince := versions[i-1].LastModified
+					newerNoncurrent := i - 1
+
+					nve := rule.NoncurrentVersionExpiration
+					if !nve.IsDaysNull() && newerNoncurrent >= nve.NewerNoncurrentVersions &&
+						!at.Before(lifecycleDue(noncurrentSince, int(nve.NoncurrentDays))) {
+						result.NoncurrentExpired.add(version)
+						removed = true
+						continue
+					}
+
+					nvt := rule.NoncurrentVersionTransition
+					if !version.IsDeleteMarker && !nvt.IsStorageClassEmpty() &&
+						!strings.EqualFold(version.StorageClass, nvt.StorageClass) &&
+						newerNoncurrent >= nvt.NewerNoncurrentVersions &&
+						!at.Before(lifecycleDue(noncurrentSince, int(nvt.NoncurrentDays))) {
+						result.NoncurrentTransitioned.add(version)
+						if transitioned == "" {
+							transitioned = nvt.StorageClass
+						}
+					}
+				}
+			}
+
+			switch {
+			case expired:
+				sim.Expired.add(version)
+				sim.ExpiredCurrent = append(sim.ExpiredCurrent, version)
+			case removed && i == 0:
+				sim.DeleteMarkersRemoved.add(version)
+				sim.Removed = append(sim.Removed, version)
+			case removed:
+				sim.NoncurrentExpired.add(version)
+				sim.Removed = append(sim.Removed, version)
+			case transitioned != "":
+				sim.TransitionedTo[version] = transitioned
+				if i == 0 {
+					sim.Transitioned.add(version)
+				} else {
+					sim.NoncurrentTransitioned.add(version)
+				}
+				if sim.TransitionsByClass[transitioned] == nil {
+					sim.TransitionsByClass[transitioned] = &LifecycleActionStats{}
+				}
+				sim.TransitionsByClass[transitioned].add(version)
+			}
+		}
+	}
+
+	return sim
+}
+
+// DisplayLifecycleSimulation displays the simulated effect of a lifecycle configuration
+func DisplayLifecycleSimulation(w io.Writer, sim *LifecycleSimulation) {
+	fmt.Fprintf(w, "\nLifecycle Simulation (as of %s):\n", sim.At.Format("2006-01-02"))
+	fmt.Fprintln(w, "=====================================")
+
+	if len(sim.Rules) == 0 {
+		fmt.Fprintln(w, "No lifecycle rules to evaluate")
+		return
+	}
+
+	for _, rule := range sim.Rules {
+		prefix := rule.Prefix
+		if prefix == "" {
+			prefix = "*"
+		}
+		fmt.Fprintf(w, "Rule '%s' (%s, prefix: %s)\n", rule.ID, rule.Status, prefix)
+		if rule.Skipped != "" {
+			fmt.Fprintf(w, "  ➖ Skipped: %s\n", rule.Skipped)
+			continue
+		}
+		printLifecycleAction(w, "Expire current versions", rule.Expired)
+		printLifecycleAction(w, "Transition current versions", rule.Transitioned)
+		printLifecycleAction(w, "Remove noncurrent versions", rule.NoncurrentExpired)
+		printLifecycleAction(w, "Transition noncurrent versions", rule.NoncurrentTransitioned)
+		printLifecycleAction(w, "Remove expired delete markers", rule.DeleteMarkersRemoved)
+	}
+
+	fmt.Fprintln(w, "\nTotals (each version counted once):")
+	fmt.Fprintf(w, "  Current versions expired: %d (%d bytes)\n", sim.Expired.Objects, sim.Expired.Bytes)
+	fmt.Fprintf(w, "  Current versions transitioned: %d (%d bytes)\n", sim.Transitioned.Objects, sim.Transitioned.Bytes)
+	fmt.Fprintf(w, "  Noncurrent versions removed: %d (%d bytes)\n", sim.NoncurrentExpired.Objects, sim.NoncurrentExpired.Bytes)
+	fmt.Fprintf(w, "  Noncurrent versions transitioned: %d (%d bytes)\n", sim.NoncurrentTransitioned.Objects, sim.NoncurrentTransitioned.Bytes)
+	fmt.Fprintf(w, "  Expired delete markers removed: %d\n", sim.DeleteMarkersRemoved.Objects)
+
+	if len(sim.TransitionsByClass) > 0 {
+		classes := make([]string, 0, len(sim.TransitionsByClass))
+		for class := range sim.TransitionsByClass {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+
+		fmt.Fprintln(w, "\nTransitions by storage class:")
+		for _, class := range classes {
+			stats := sim.TransitionsByClass[class]
+			fmt.Fprintf(w, "  %s: %d versions (%d bytes)\n", class, stats.Objects, stats.Bytes)
+		}
+	}
+}
+
+func printLifecycleAction(w io.Writer, label string, stats LifecycleActionStats) {
+	if stats.Objects > 0 {
+		fmt.Fprintf(w, "  - %s: %d (%d bytes)\n", label, stats.Objects, stats.Bytes)
+	}
+}
@@ -0,0 +1,154 @@
+package analyze
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func TestLifecycleDue(t *testing.T) {
+	modified := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), lifecycleDue(modified, 0))
+	assert.Equal(t, time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC), lifecycleDue(modified, 7))
+}
+
+func TestSimulateLifecycle(t *testing.T) {
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := at.Add(-100 * 24 * time.Hour)
+	recent := at.Add(-5 * 24 * time.Hour)
+
+	config := &lifecycle.Configuration{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "expire-logs",
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{Prefix: "logs/"},
+				Expiration: lifecycle.Expiration{Days: 30},
+			},
+			{
+				ID:                          "tier-and-cleanup",
+				Status:                      "Enabled",
+				Transition:                  lifecycle.Transition{Days: 60, StorageClass: "WARM"},
+				NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 30},
+				Expiration:                  lifecycle.Expiration{DeleteMarker: true},
+			},
+			{
+				ID:         "disabled",
+				Status:     "Disabled",
+				Expiration: lifecycle.Expiration{Days: 1},
+			},
+			{
+				ID:         "tagged",
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{Tag: lifecycle.Tag{Key: "tier", Value: "cold"}},
+				Expiration: lifecycle.Expiration{Days: 1},
+			},
+		},
+	}
+
+	objects := []*compare.ObjectInfo{
+		// Old log expires (rule 1) and would also be transitioned by rule 2
+		{Key: "logs/app.log", VersionID: "l1", IsLatest: true, Size: 100, LastModified: old},
+		// Recent log is untouched
+		{Key: "logs/new.log", VersionID: "l2", IsLatest: true, Size: 10, LastModified: recent},
+		// Old data transitions, its noncurrent version is removed
+		{Key: "data/file.bin", VersionID: "d2", IsLatest: true, Size: 1000, LastModified: old},
+		{Key: "data/file.bin", VersionID: "d1", Size: 900, LastModified: old.Add(-time.Hour)},
+		// Already in target class is not transitioned
+		{Key: "data/warm.bin", VersionID: "w1", IsLatest: true, Size: 500, LastModified: old, StorageClass: "WARM"},
+		// Expired object delete marker
+		{Key: "data/gone.bin", VersionID: "g1", IsLatest: true, IsDeleteMarker: true, LastModified: recent},
+	}
+
+	sim := SimulateLifecycle(config, objects, at)
+
+	require.Len(t, sim.Rules, 4)
+	assert.Equal(t, LifecycleActionStats{Objects: 1, Bytes: 100}, sim.Rules[0].Expired)
+	assert.Equal(t, LifecycleActionStats{Objects: 2, Bytes: 1100}, sim.Rules[1].Transitioned)
+	assert.Equal(t, LifecycleActionStats{Objects: 1, Bytes: 900}, sim.Rules[1].NoncurrentExpired)
+	assert.Equal(t, 1, sim.Rules[1].DeleteMarkersRemoved.Objects)
+	assert.Equal(t, "rule is disabled", sim.Rules[2].Skipped)
+	assert.NotEmpty(t, sim.Rules[3].Skipped)
+
+	// Totals count each version once, expiration wins over transition
+	assert.Equal(t, LifecycleActionStats{Objects: 1, Bytes: 100}, sim.Expired)
+	assert.Equal(t, LifecycleActionStats{Objects: 1, Bytes: 1000}, sim.Transitioned)
+	assert.Equal(t, LifecycleActionStats{Objects: 1, Bytes: 900}, sim.NoncurrentExpired)
+	assert.Equal(t, 1, sim.DeleteMarkersRemoved.Objects)
+	assert.Equal(t, &LifecycleActionStats{Objects: 1, Bytes: 1000}, sim.TransitionsByClass["WARM"])
+
+	assert.Len(t, sim.Removed, 2)
+	assert.Equal(t, []*compare.ObjectInfo{objects[0]}, sim.ExpiredCurrent)
+	assert.Len(t, sim.TransitionedTo, 1)
+	assert.Equal(t, "WARM", sim.TransitionedTo[objects[2]])
+}
+
+func TestSimulateLifecycleNewerNoncurrentVersions(t *testing.T) {
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := at.Add(-100 * 24 * time.Hour)
+
+	config := &lifecycle.Configuration{
+		Rules: []lifecycle.Rule{
+			{
+				ID:     "keep-two",
+				Status: "Enabled",
+				NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{
+					NoncurrentDays:          1,
+					NewerNoncurrentVersions: 2,
+				},
+			},
+		},
+	}
+
+	objects := []*compare.ObjectInfo{
+		{Key: "k", VersionID: "v4", IsLatest: true, Size: 4, LastModified: old.Add(4 * time.Hour)},
+		{Key: "k", VersionID: "v3", Size: 3, LastModified: old.Add(3 * time.Hour)},
+		{Key: "k", VersionID: "v2", Size: 2, LastModified: old.Add(2 * time.Hour)},
+		{Key: "k", VersionID: "v1", Size: 1, LastModified: old.Add(1 * time.Hour)},
+	}
+
+	sim := SimulateLifecycle(config, objects, at)
+
+	assert.Equal(t, LifecycleActionStats{Objects: 1, Bytes: 1}, sim.NoncurrentExpired)
+}
+
+func TestLoadLifecycleConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	xmlPath := filepath.Join(dir, "lifecycle.xml")
+	require.NoError(t, os.WriteFile(xmlPath, []byte(`<LifecycleConfiguration>
+  <Rule>
+    <ID>expire</ID>
+    <Status>Enabled</Status>
+    <Filter><Prefix>tmp/</Prefix></Filter>
+    <Expiration><Days>7</Days></Expiration>
+  </Rule>
+</LifecycleConfiguration>`), 0644))
+
+	config, err := LoadLifecycleConfig(xmlPath)
+	require.NoError(t, err)
+	require.Len(t, config.Rules, 1)
+	assert.Equal(t, "tmp/", lifecycleRulePrefix(config.Rules[0]))
+	assert.Equal(t, lifecycle.ExpirationDays(7), config.Rules[0].Expiration.Days)
+
+	jsonPath := filepath.Join(dir, "lifecycle.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"Rules": [
+		{"ID": "tier", "Status": "Enabled", "Transition": {"Days": 30, "StorageClass": "WARM"}}
+	]}`), 0644))
+
+	config, err = LoadLifecycleConfig(jsonPath)
+	require.NoError(t, err)
+	require.Len(t, config.Rules, 1)
+	assert.Equal(t, "WARM", config.Rules[0].Transition.StorageClass)
+
+	_, err = LoadLifecycleConfig(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
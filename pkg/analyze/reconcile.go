@@ -0,0 +1,147 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/liamdn8/mc-tool/pkg/client"
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// UsageReconciliation compares listing results against the data usage reported by MinIO
+type UsageReconciliation struct {
+	LastUpdate time.Time
+
+	ListedObjects       int
+	ListedVersions      int
+	ListedDeleteMarkers int
+	ListedCurrentSize   int64
+	ListedTotalSize     int64
+
+	ReportedObjects       uint64
+	ReportedVersions      uint64
+	ReportedDeleteMarkers uint64
+	ReportedSize          uint64
+
+	// Sources that explain why the reported usage exceeds the current objects
+	HiddenKeys         int
+	NoncurrentVersions int
+	NoncurrentSize     int64
+
+	// Incomplete multipart uploads take up space but are not part of MinIO's data usage
+	IncompleteUploads    int
+	IncompleteUploadSize int64
+}
+
+// ObjectDelta returns the reported object count minus the listed current objects
+func (r *UsageReconciliation) ObjectDelta() int64 {
+	return int64(r.ReportedObjects) - int64(r.ListedObjects)
+}
+
+// SizeDelta returns the reported size minus the listed current version size
+func (r *UsageReconciliation) SizeDelta() int64 {
+	return int64(r.ReportedSize) - r.ListedCurrentSize
+}
+
+// UnexplainedObjects returns the part of the object delta not covered by
+// keys hidden behind delete markers
+func (r *UsageReconciliation) UnexplainedObjects() int64 {
+	return r.ObjectDelta() - int64(r.HiddenKeys)
+}
+
+// UnexplainedSize returns the part of the size delta not covered by
+// noncurrent versions
+func (r *UsageReconciliation) UnexplainedSize() int64 {
+	return r.SizeDelta() - r.NoncurrentSize
+}
+
+// ReconcileUsage builds a reconciliation of the listed objects and uploads against
+// the bucket usage reported by the MinIO scanner
+func ReconcileUsage(objects []*compare.ObjectInfo, uploads []UploadUsage, usage client.BucketUsageInfo, lastUpdate time.Time) *UsageReconciliation {
+	r := &UsageReconciliation{
+		LastUpdate:            lastUpdate,
+		ReportedObjects:       usage.ObjectsCount,
+		ReportedVersions:      usage.VersionsCount,
+		ReportedDeleteMarkers: usage.DeleteMarkersCount,
+		ReportedSize:          usage.Size,
+	}
+
+	keys, versionsByKey := groupVersionsByKey(objects)
+	for _, key := range keys {
+		versions := versionsByKey[key]
+
+		latestIsMarker := versions[0].IsDeleteMarker
+		hidesData := false
+
+		for _, version := range versions {
+			r.ListedTotalSize += version.Size
+
+			if version.IsDeleteMarker {
+				r.ListedDeleteMarkers++
+				continue
+			}
+
+			r.ListedVersions++
+			if version.IsLatest {
+				r.ListedObjects++
+				r.ListedCurrentSize += version.Size
+			} else {
+				r.NoncurrentVersions++
+				r.NoncurrentSize += version.Size
+				hidesData = true
+			}
+		}
+
+		if latestIsMarker && hidesData {
+			r.HiddenKeys++
+		}
+	}
+
+	for _, upload := range uploads {
+		r.IncompleteUploads++
+		r.IncompleteUploadSize += upload.PartsSize
+	}
+
+	return r
+}
+
+// DisplayUsageReconciliation displays listing results side by side with MinIO's data usage
+func DisplayUsageReconciliation(w io.Writer, r *UsageReconciliation, prefix string) {
+	fmt.Fprintln(w, "\nUsage Reconciliation (listing vs MinIO data usage):")
+	fmt.Fprintln(w, "===================================================")
+
+	if !r.LastUpdate.IsZero() {
+		fmt.Fprintf(w, "MinIO data usage last updated: %s\n", r.LastUpdate.Format("2006-01-02 15:04:05"))
+	}
+	if prefix != "" {
+		fmt.Fprintf(w, "⚠ Listing is limited to prefix '%s' while MinIO reports usage for the whole bucket\n", prefix)
+	}
+
+	fmt.Fprintf(w, "\n%-28s %18s %18s %18s\n", "Metric", "Listing", "MinIO usage", "Delta")
+	fmt.Fprintf(w, "%-28s %18d %18d %18d\n", "Current objects", r.ListedObjects, r.ReportedObjects, r.ObjectDelta())
+	fmt.Fprintf(w, "%-28s %18d %18d %18d\n", "Versions", r.ListedVersions, r.ReportedVersions,
+		int64(r.ReportedVersions)-int64(r.ListedVersions))
+	fmt.Fprintf(w, "%-28s %18d %18d %18d\n", "Delete markers", r.ListedDeleteMarkers, r.ReportedDeleteMarkers,
+		int64(r.ReportedDeleteMarkers)-int64(r.ListedDeleteMarkers))
+	fmt.Fprintf(w, "%-28s %18d %18d %18d\n", "Size, current (bytes)", r.ListedCurrentSize, r.ReportedSize, r.SizeDelta())
+	fmt.Fprintf(w, "%-28s %18d %18d %18d\n", "Size, all versions (bytes)", r.ListedTotalSize, r.ReportedSize,
+		int64(r.ReportedSize)-r.ListedTotalSize)
+
+	fmt.Fprintf(w, "\nObject count delta (%d) explained by:\n", r.ObjectDelta())
+	fmt.Fprintf(w, "  - Keys hidden behind delete markers: %d\n", r.HiddenKeys)
+	fmt.Fprintf(w, "  - Unexplained: %d\n", r.UnexplainedObjects())
+
+	fmt.Fprintf(w, "\nSize delta (%d bytes) explained by:\n", r.SizeDelta())
+	fmt.Fprintf(w, "  - Noncurrent versions (%d): %d bytes\n", r.NoncurrentVersions, r.NoncurrentSize)
+	fmt.Fprintf(w, "  - Unexplained: %d bytes\n", r.UnexplainedSize())
+
+	fmt.Fprintf(w, "\nNot counted in MinIO data usage:\n")
+	fmt.Fprintf(w, "  - Incomplete multipart uploads (%d): %d bytes of parts\n", r.IncompleteUploads, r.IncompleteUploadSize)
+
+	if r.UnexplainedObjects() != 0 || r.UnexplainedSize() != 0 {
+		fmt.Fprintln(w, "\n💡 Remaining differences may come from changes made after the last scanner cycle")
+	} else {
+		fmt.Fprintln(w, "\n✅ Listing fully reconciles with MinIO data usage")
+	}
+}
@@ -0,0 +1,59 @@
+package analyze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/liamdn8/mc-tool/pkg/client"
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func TestReconcileUsage(t *testing.T) {
+	now := time.Now()
+
+	objects := []*compare.ObjectInfo{
+		{Key: "a.txt", VersionID: "a2", IsLatest: true, Size: 100, LastModified: now},
+		{Key: "a.txt", VersionID: "a1", Size: 80, LastModified: now.Add(-time.Hour)},
+		{Key: "b.txt", VersionID: "b2", IsLatest: true, IsDeleteMarker: true, LastModified: now},
+		{Key: "b.txt", VersionID: "b1", Size: 50, LastModified: now.Add(-time.Hour)},
+		{Key: "c.txt", VersionID: "c1", IsLatest: true, Size: 20, LastModified: now},
+	}
+	uploads := []UploadUsage{
+		{ObjectMultipartInfo: minio.ObjectMultipartInfo{Key: "big.bin", UploadID: "u1"}, Parts: 2, PartsSize: 500},
+	}
+	// MinIO's data usage does not include incomplete upload parts
+	usage := client.BucketUsageInfo{Size: 250, ObjectsCount: 3, VersionsCount: 4, DeleteMarkersCount: 1}
+
+	r := ReconcileUsage(objects, uploads, usage, now)
+
+	assert.Equal(t, 2, r.ListedObjects)
+	assert.Equal(t, 4, r.ListedVersions)
+	assert.Equal(t, 1, r.ListedDeleteMarkers)
+	assert.Equal(t, int64(120), r.ListedCurrentSize)
+	assert.Equal(t, int64(250), r.ListedTotalSize)
+	assert.Equal(t, 1, r.HiddenKeys)
+	assert.Equal(t, 2, r.NoncurrentVersions)
+	assert.Equal(t, int64(130), r.NoncurrentSize)
+	assert.Equal(t, 1, r.IncompleteUploads)
+	assert.Equal(t, int64(500), r.IncompleteUploadSize)
+
+	assert.Equal(t, int64(1), r.ObjectDelta())
+	assert.Equal(t, int64(0), r.UnexplainedObjects())
+	assert.Equal(t, int64(130), r.SizeDelta())
+	assert.Equal(t, int64(0), r.UnexplainedSize())
+}
+
+func TestReconcileUsageUnexplained(t *testing.T) {
+	objects := []*compare.ObjectInfo{
+		{Key: "a.txt", VersionID: "a1", IsLatest: true, Size: 100},
+	}
+	usage := client.BucketUsageInfo{Size: 300, ObjectsCount: 3, VersionsCount: 3}
+
+	r := ReconcileUsage(objects, nil, usage, time.Time{})
+
+	assert.Equal(t, int64(2), r.UnexplainedObjects())
+	assert.Equal(t, int64(200), r.UnexplainedSize())
+}
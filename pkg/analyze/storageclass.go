@@ -0,0 +1,232 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// defaultStorageClass is reported for objects listed without a storage class
+const defaultStorageClass = "STANDARD"
+
+// StorageClassStats counts the versions and bytes stored in a storage class
+type StorageClassStats struct {
+	Versions int
+	Bytes    int64
+}
+
+// TierViolation describes an object whose storage class contradicts the lifecycle rules
+type TierViolation struct {
+	Object *compare.ObjectInfo
+	RuleID string
+	Reason string
+}
+
+// StorageClassReport breaks down stored versions by storage class
+type StorageClassReport struct {
+	ByClass    map[string]*StorageClassStats
+	ByPrefix   map[string]map[string]*StorageClassStats
+	Violations []TierViolation
+}
+
+// objectStorageClass returns the storage class of an object, defaulting to STANDARD
+func objectStorageClass(obj *compare.ObjectInfo) string {
+	if obj.StorageClass == "" {
+		return defaultStorageClass
+	}
+	return obj.StorageClass
+}
+
+// isLocalStorageClass reports whether a storage class is served by the MinIO cluster
+// itself rather than by a remote tier
+func isLocalStorageClass(class string) bool {
+	return class == defaultStorageClass || class == "REDUCED_REDUNDANCY"
+}
+
+// AnalyzeStorageClasses totals versions and bytes per storage class and prefix, and
+// flags objects whose tier contradicts the transition rules of the lifecycle configuration
+func AnalyzeStorageClasses(objects []*compare.ObjectInfo, prefix string, config *lifecycle.Configuration, at time.Time) *StorageClassReport {
+	report := &StorageClassReport{
+		ByClass:  make(map[string]*StorageClassStats),
+		ByPrefix: make(map[string]map[string]*StorageClassStats),
+	}
+
+	for _, obj := range objects {
+		if obj.IsDeleteMarker {
+			continue
+		}
+
+		class := objectStorageClass(obj)
+		if report.ByClass[class] == nil {
+			report.ByClass[class] = &StorageClassStats{}
+		}
+		report.ByClass[class].Versions++
+		report.ByClass[class].Bytes += obj.Size
+
+		p := topLevelPrefix(obj.Key, prefix)
+		if report.ByPrefix[p] == nil {
+			report.ByPrefix[p] = make(map[string]*StorageClassStats)
+		}
+		if report.ByPrefix[p][class] == nil {
+			report.ByPrefix[p][class] = &StorageClassStats{}
+		}
+		report.ByPrefix[p][class].Versions++
+		report.ByPrefix[p][class].Bytes += obj.Size
+	}
+
+	if config != nil {
+		report.Violations = findTierViolations(objects, config, at)
+	}
+
+	return report
+}
+
+func findTierViolations(objects []*compare.ObjectInfo, config *lifecycle.Configuration, at time.Time) []TierViolation {
+	var violations []TierViolation
+
+	var rules []lifecycle.Rule
+	for _, rule := range config.Rules {
+		if rule.Status == "Enabled" && !lifecycleRuleHasTagFilter(rule) {
+			rules = append(rules, rule)
+		}
+	}
+
+	keys, versionsByKey := groupVersionsByKey(objects)
+	for _, key := range keys {
+		versions := versionsByKey[key]
+
+		for i, version := range versions {
+			if version.IsDeleteMarker {
+				continue
+			}
+			class := objectStorageClass(version)
+			targeted := false
+
+			for _, rule := range rules {
+				if !strings.HasPrefix(key, lifecycleRulePrefix(rule)) {
+					continue
+				}
+
+				if i == 0 && !rule.Transition.IsNull() {
+					target := rule.Transition.StorageClass
+					if strings.EqualFold(class, target) {
+						targeted = true
+						due := rule.Transition.Date.Time
+						if rule.Transition.IsDateNull() {
+							due = lifecycleDue(version.LastModified, int(rule.Transition.Days))
+						}
+						if at.Before(due) {
+							violations = append(violations, TierViolation{
+								Object: version,
+								RuleID: rule.ID,
+								Reason: fmt.Sprintf("in %s before the rule's transition date %s", class, due.Format("2006-01-02")),
+							})
+						}
+					} else if transitionDue(rule.Transition, version, at) && !expirationDue(rule.Expiration, version, at) {
+						violations = append(violations, TierViolation{
+							Object: version,
+							RuleID: rule.ID,
+							Reason: fmt.Sprintf("still in %s, overdue for transition to %s", class, target),
+						})
+					}
+				}
+
+				// A noncurrent version may have been transitioned while it was still current
+				if i > 0 && !rule.Transition.IsNull() && strings.EqualFold(class, rule.Transition.StorageClass) {
+					targeted = true
+				}
+
+				nvt := rule.NoncurrentVersionTransition
+				if i > 0 && !nvt.IsStorageClassEmpty() {
+					if strings.EqualFold(class, nvt.StorageClass) {
+						targeted = true
+					} else if i-1 >= nvt.NewerNoncurrentVersions &&
+						!at.Before(lifecycleDue(versions[i-1].LastModified, int(nvt.NoncurrentDays))) {
+						violations = append(violations, TierViolation{
+							Object: version,
+							RuleID: rule.ID,
+							Reason: fmt.Sprintf("noncurrent version still in %s, overdue for transition to %s", class, nvt.StorageClass),
+						})
+					}
+				}
+			}
+
+			if !isLocalStorageClass(class) && !targeted {
+				violations = append(violations, TierViolation{
+					Object: version,
+					Reason: fmt.Sprintf("in remote tier %s but no lifecycle rule transitions it there", class),
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// DisplayStorageClassReport displays the storage class breakdown and tiering contradictions
+func DisplayStorageClassReport(w io.Writer, report *StorageClassReport, verbose bool) {
+	fmt.Fprintln(w, "\nStorage Class Breakdown:")
+	fmt.Fprintln(w, "========================")
+
+	classes := make([]string, 0, len(report.ByClass))
+	for class := range report.ByClass {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	for _, class := range classes {
+		stats := report.ByClass[class]
+		tier := "local"
+		if !isLocalStorageClass(class) {
+			tier = "remote tier"
+		}
+		fmt.Fprintf(w, "%s (%s): %d versions, %d bytes\n", class, tier, stats.Versions, stats.Bytes)
+	}
+
+	prefixes := make([]string, 0, len(report.ByPrefix))
+	for p := range report.ByPrefix {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+
+	if len(prefixes) > 0 {
+		fmt.Fprintln(w, "\nBy prefix:")
+	}
+	for _, p := range prefixes {
+		var parts []string
+		for _, class := range classes {
+			if stats, ok := report.ByPrefix[p][class]; ok {
+				parts = append(parts, fmt.Sprintf("%s %d (%d bytes)", class, stats.Versions, stats.Bytes))
+			}
+		}
+		fmt.Fprintf(w, "  %s: %s\n", p, strings.Join(parts, ", "))
+	}
+
+	if len(report.Violations) == 0 {
+		fmt.Fprintln(w, "\n✅ No objects contradict the lifecycle transition rules")
+		return
+	}
+
+	fmt.Fprintf(w, "\n⚠ Objects contradicting lifecycle transition rules: %d\n", len(report.Violations))
+
+	violations := report.Violations
+	if !verbose && len(violations) > 20 {
+		violations = violations[:20]
+	}
+	for _, violation := range violations {
+		rule := ""
+		if violation.RuleID != "" {
+			rule = fmt.Sprintf(" [rule '%s']", violation.RuleID)
+		}
+		fmt.Fprintf(w, "  - %s (VersionID: %s): %s%s\n", violation.Object.Key, violation.Object.VersionID, violation.Reason, rule)
+	}
+	if len(violations) < len(report.Violations) {
+		fmt.Fprintf(w, "  ... %d more (use --verbose for all)\n", len(report.Violations)-len(violations))
+	}
+}
@@ -0,0 +1,113 @@
+package analyze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+func TestAnalyzeStorageClassesBreakdown(t *testing.T) {
+	objects := []*compare.ObjectInfo{
+		{Key: "logs/a.log", IsLatest: true, Size: 10},
+		{Key: "logs/a.log", Size: 5, StorageClass: "STANDARD"},
+		{Key: "logs/b.log", IsLatest: true, Size: 20, StorageClass: "WARM"},
+		{Key: "media/c.mp4", IsLatest: true, Size: 100, StorageClass: "REDUCED_REDUNDANCY"},
+		{Key: "media/d.mp4", IsLatest: true, IsDeleteMarker: true},
+	}
+
+	report := AnalyzeStorageClasses(objects, "", nil, time.Now())
+
+	assert.Equal(t, &StorageClassStats{Versions: 2, Bytes: 15}, report.ByClass["STANDARD"])
+	assert.Equal(t, &StorageClassStats{Versions: 1, Bytes: 20}, report.ByClass["WARM"])
+	assert.Equal(t, &StorageClassStats{Versions: 1, Bytes: 100}, report.ByClass["REDUCED_REDUNDANCY"])
+	assert.Equal(t, &StorageClassStats{Versions: 1, Bytes: 20}, report.ByPrefix["logs/"]["WARM"])
+	assert.Empty(t, report.Violations)
+}
+
+func TestAnalyzeStorageClassesViolations(t *testing.T) {
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := at.Add(-100 * 24 * time.Hour)
+	recent := at.Add(-2 * 24 * time.Hour)
+
+	config := &lifecycle.Configuration{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "tier-logs",
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{Prefix: "logs/"},
+				Transition: lifecycle.Transition{Days: 30, StorageClass: "WARM"},
+				NoncurrentVersionTransition: lifecycle.NoncurrentVersionTransition{
+					NoncurrentDays: 7,
+					StorageClass:   "WARM",
+				},
+			},
+		},
+	}
+
+	objects := []*compare.ObjectInfo{
+		// Overdue: old object still in STANDARD
+		{Key: "logs/old.log", VersionID: "o2", IsLatest: true, Size: 10, LastModified: old},
+		// Overdue noncurrent version
+		{Key: "logs/old.log", VersionID: "o1", Size: 10, LastModified: old.Add(-time.Hour)},
+		// Correctly transitioned
+		{Key: "logs/tiered.log", VersionID: "t1", IsLatest: true, Size: 10, LastModified: old, StorageClass: "WARM"},
+		// Early: in WARM before it is due
+		{Key: "logs/early.log", VersionID: "e1", IsLatest: true, Size: 10, LastModified: recent, StorageClass: "WARM"},
+		// Recent object in STANDARD is fine
+		{Key: "logs/new.log", VersionID: "n1", IsLatest: true, Size: 10, LastModified: recent},
+		// Remote tier outside any rule
+		{Key: "media/cold.mp4", VersionID: "c1", IsLatest: true, Size: 10, LastModified: old, StorageClass: "GLACIER"},
+	}
+
+	report := AnalyzeStorageClasses(objects, "", config, at)
+
+	require.Len(t, report.Violations, 4)
+
+	byVersion := make(map[string]TierViolation)
+	for _, violation := range report.Violations {
+		byVersion[violation.Object.VersionID] = violation
+	}
+
+	assert.Contains(t, byVersion["o2"].Reason, "overdue for transition to WARM")
+	assert.Equal(t, "tier-logs", byVersion["o2"].RuleID)
+	assert.Contains(t, byVersion["o1"].Reason, "noncurrent version")
+	assert.Contains(t, byVersion["e1"].Reason, "before the rule's transition date")
+	assert.Contains(t, byVersion["c1"].Reason, "no lifecycle rule transitions it there")
+	assert.Empty(t, byVersion["c1"].RuleID)
+}
+
+func TestAnalyzeStorageClassesNoncurrentTransitionedWhileCurrent(t *testing.T) {
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := at.Add(-100 * 24 * time.Hour)
+
+	config := &lifecycle.Configuration{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "tier-logs",
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{Prefix: "logs/"},
+				Transition: lifecycle.Transition{Days: 30, StorageClass: "WARM"},
+			},
+		},
+	}
+
+	objects := []*compare.ObjectInfo{
+		{Key: "logs/app.log", VersionID: "v2", IsLatest: true, Size: 10, LastModified: at.Add(-24 * time.Hour)},
+		// Transitioned to WARM while it was current, then overwritten
+		{Key: "logs/app.log", VersionID: "v1", Size: 10, LastModified: old, StorageClass: "WARM"},
+		// No rule ever transitions media/ objects
+		{Key: "media/cold.mp4", VersionID: "c2", IsLatest: true, Size: 10, LastModified: at.Add(-24 * time.Hour)},
+		{Key: "media/cold.mp4", VersionID: "c1", Size: 10, LastModified: old, StorageClass: "WARM"},
+	}
+
+	report := AnalyzeStorageClasses(objects, "", config, at)
+
+	require.Len(t, report.Violations, 1)
+	assert.Equal(t, "c1", report.Violations[0].Object.VersionID)
+	assert.Contains(t, report.Violations[0].Reason, "no lifecycle rule transitions it there")
+}
@@ -0,0 +1,141 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// UploadUsage describes the parts already stored for an incomplete multipart upload
+type UploadUsage struct {
+	minio.ObjectMultipartInfo
+	Parts     int
+	PartsSize int64
+}
+
+// ListUploadParts counts the parts of an incomplete upload and the bytes they consume
+func ListUploadParts(ctx context.Context, client *minio.Client, bucket, key, uploadID string) (int, int64, error) {
+	core := minio.Core{Client: client}
+
+	var parts int
+	var size int64
+	partNumberMarker := 0
+
+	for {
+		result, err := core.ListObjectParts(ctx, bucket, key, uploadID, partNumberMarker, 1000)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		for _, part := range result.ObjectParts {
+			parts++
+			size += part.Size
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		partNumberMarker = result.NextPartNumberMarker
+	}
+
+	return parts, size, nil
+}
+
+// MeasureIncompleteUploads lists the parts of each incomplete upload to determine its storage usage.
+// Uploads completed or aborted since they were listed are left out.
+func MeasureIncompleteUploads(ctx context.Context, client *minio.Client, bucket string, uploads []minio.ObjectMultipartInfo) ([]UploadUsage, error) {
+	usages := make([]UploadUsage, 0, len(uploads))
+
+	for _, upload := range uploads {
+		parts, size, err := ListUploadParts(ctx, client, bucket, upload.Key, upload.UploadID)
+		if minio.ToErrorResponse(err).Code == "NoSuchUpload" {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list parts of upload %s for %s: %w", upload.UploadID, upload.Key, err)
+		}
+
+		upload.Size = size
+		usages = append(usages, UploadUsage{
+			ObjectMultipartInfo: upload,
+			Parts:               parts,
+			PartsSize:           size,
+		})
+	}
+
+	return usages, nil
+}
+
+// FilterUploadsOlderThan returns the uploads initiated before the cutoff time
+func FilterUploadsOlderThan(uploads []minio.ObjectMultipartInfo, cutoff time.Time) []minio.ObjectMultipartInfo {
+	var filtered []minio.ObjectMultipartInfo
+
+	for _, upload := range uploads {
+		if upload.Initiated.Before(cutoff) {
+			filtered = append(filtered, upload)
+		}
+	}
+
+	return filtered
+}
+
+// AbortIncompleteUploads aborts each upload and logs its upload ID. In dry-run
+// mode the uploads are only listed. It returns the number of aborted uploads
+// and the part bytes reclaimed.
+func AbortIncompleteUploads(ctx context.Context, client *minio.Client, bucket string, usages []UploadUsage, dryRun bool) (int, int64, error) {
+	core := minio.Core{Client: client}
+
+	var aborted int
+	var reclaimed int64
+
+	for _, usage := range usages {
+		if dryRun {
+			fmt.Printf("Would abort upload: %s (ID: %s, Initiated: %s, Parts: %d, Size: %d bytes)\n",
+				usage.Key, usage.UploadID, usage.Initiated.Format("2006-01-02 15:04:05"), usage.Parts, usage.PartsSize)
+		} else {
+			if err := core.AbortMultipartUpload(ctx, bucket, usage.Key, usage.UploadID); err != nil {
+				return aborted, reclaimed, fmt.Errorf("failed to abort upload %s for %s: %w", usage.UploadID, usage.Key, err)
+			}
+			fmt.Printf("Aborted upload: %s (ID: %s, Initiated: %s, Parts: %d, Size: %d bytes)\n",
+				usage.Key, usage.UploadID, usage.Initiated.Format("2006-01-02 15:04:05"), usage.Parts, usage.PartsSize)
+		}
+
+		aborted++
+		reclaimed += usage.PartsSize
+	}
+
+	return aborted, reclaimed, nil
+}
+
+// ParseAge parses an age such as "7d", "2w" or any Go duration like "36h"
+func ParseAge(age string) (time.Duration, error) {
+	age = strings.TrimSpace(age)
+	if age == "" {
+		return 0, fmt.Errorf("empty age")
+	}
+
+	unit := age[len(age)-1]
+	if unit == 'd' || unit == 'w' {
+		value, err := strconv.Atoi(age[:len(age)-1])
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid age: %s", age)
+		}
+
+		days := value
+		if unit == 'w' {
+			days = value * 7
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(age)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid age: %s", age)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,93 @@
+package analyze
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		age      string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{age: "7d", expected: 7 * 24 * time.Hour},
+		{age: "2w", expected: 14 * 24 * time.Hour},
+		{age: "36h", expected: 36 * time.Hour},
+		{age: "90m", expected: 90 * time.Minute},
+		{age: "0d", expected: 0},
+		{age: "", wantErr: true},
+		{age: "xd", wantErr: true},
+		{age: "-1d", wantErr: true},
+		{age: "seven", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.age, func(t *testing.T) {
+			duration, err := ParseAge(tt.age)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, duration)
+		})
+	}
+}
+
+func TestFilterUploadsOlderThan(t *testing.T) {
+	now := time.Now()
+	uploads := []minio.ObjectMultipartInfo{
+		{Key: "old.bin", UploadID: "u1", Initiated: now.Add(-10 * 24 * time.Hour)},
+		{Key: "recent.bin", UploadID: "u2", Initiated: now.Add(-time.Hour)},
+		{Key: "older.bin", UploadID: "u3", Initiated: now.Add(-30 * 24 * time.Hour)},
+	}
+
+	filtered := FilterUploadsOlderThan(uploads, now.Add(-7*24*time.Hour))
+
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, "u1", filtered[0].UploadID)
+	assert.Equal(t, "u3", filtered[1].UploadID)
+
+	assert.Empty(t, FilterUploadsOlderThan(uploads, now.Add(-60*24*time.Hour)))
+}
+
+func TestMeasureIncompleteUploadsSkipsFinishedUploads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if r.URL.Query().Get("uploadId") == "gone" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchUpload</Code><Message>The specified multipart upload does not exist.</Message></Error>`))
+			return
+		}
+		w.Write([]byte(`<ListPartsResult><IsTruncated>false</IsTruncated>` +
+			`<Part><PartNumber>1</PartNumber><Size>5242880</Size></Part><Part><PartNumber>2</PartNumber><Size>1024</Size></Part>` +
+			`</ListPartsResult>`))
+	}))
+	defer server.Close()
+
+	client, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	usages, err := MeasureIncompleteUploads(context.Background(), client, "data", []minio.ObjectMultipartInfo{
+		{Key: "done.bin", UploadID: "gone"},
+		{Key: "big.bin", UploadID: "live"},
+	})
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, "live", usages[0].UploadID)
+	assert.Equal(t, 2, usages[0].Parts)
+	assert.Equal(t, int64(5243904), usages[0].PartsSize)
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/signer"
+
+	"github.com/liamdn8/mc-tool/pkg/config"
+)
+
+// AdminClient performs signed requests against the MinIO admin API
+type AdminClient struct {
+	endpoint   string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+}
+
+// BucketUsageInfo represents the scanner's usage statistics for a single bucket
+type BucketUsageInfo struct {
+	Size               uint64 `json:"size"`
+	ObjectsCount       uint64 `json:"objectsCount"`
+	VersionsCount      uint64 `json:"versionsCount"`
+	DeleteMarkersCount uint64 `json:"deleteMarkersCount"`
+}
+
+// DataUsageInfo represents the data usage reported by the MinIO scanner
+type DataUsageInfo struct {
+	LastUpdate        time.Time                  `json:"lastUpdate"`
+	ObjectsTotalCount uint64                     `json:"objectsCount"`
+	ObjectsTotalSize  uint64                     `json:"objectsTotalSize"`
+	BucketsCount      uint64                     `json:"bucketsCount"`
+	BucketsUsage      map[string]BucketUsageInfo `json:"bucketsUsageInfo"`
+}
+
+// KMSKeyStatus is the outcome of the server generating and decrypting a data key with
+// a KMS key; the error fields are empty when the key is usable
+type KMSKeyStatus struct {
+	KeyID         string `json:"key-id"`
+	EncryptionErr string `json:"encryption-error,omitempty"`
+	DecryptionErr string `json:"decryption-error,omitempty"`
+}
+
+// RemoteTarget is a remote bucket registered as a replication or tiering target of a bucket
+type RemoteTarget struct {
+	SourceBucket string `json:"sourcebucket"`
+	Endpoint     string `json:"endpoint"`
+	TargetBucket string `json:"targetbucket"`
+	Arn          string `json:"arn"`
+	Type         string `json:"type"`
+}
+
+// CreateAdminClient creates a MinIO admin API client for the specified alias
+func CreateAdminClient(cfg *config.MCConfig, alias string, insecure bool) (*AdminClient, error) {
+	aliasConfig, exists := cfg.Aliases[alias]
+	if !exists {
+		return nil, fmt.Errorf("alias '%s' not found in MC configuration", alias)
+	}
+
+	endpoint := strings.TrimSuffix(aliasConfig.URL, "/")
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("invalid URL for alias '%s': %s", alias, aliasConfig.URL)
+	}
+
+	// Priority: command line flag > config setting > default (false)
+	skipVerify := insecure || aliasConfig.Insecure
+
+	return &AdminClient{
+		endpoint:   endpoint,
+		accessKey:  aliasConfig.AccessKey,
+		secretKey:  aliasConfig.SecretKey,
+		httpClient: &http.Client{Transport: newTransport(endpoint, skipVerify)},
+	}, nil
+}
+
+// DataUsageInfo fetches the cluster's data usage as last computed by the scanner
+func (a *AdminClient) DataUsageInfo(ctx context.Context) (*DataUsageInfo, error) {
+	var usage DataUsageInfo
+	if err := a.get(ctx, "/minio/admin/v3/datausageinfo", nil, &usage); err != nil {
+		return nil, fmt.Errorf("failed to fetch data usage info: %w", err)
+	}
+
+	return &usage, nil
+}
+
+// ListTiers returns the names of the remote tiers lifecycle rules can transition objects to
+func (a *AdminClient) ListTiers(ctx context.Context) ([]string, error) {
+	var tiers []struct {
+		Name string `json:"Name"`
+	}
+	if err := a.get(ctx, "/minio/admin/v3/tier", nil, &tiers); err != nil {
+		return nil, fmt.Errorf("failed to list tiers: %w", err)
+	}
+
+	names := make([]string, 0, len(tiers))
+	for _, tier := range tiers {
+		names = append(names, tier.Name)
+	}
+	return names, nil
+}
+
+// ListRemoteTargets returns the replication targets registered for a bucket
+func (a *AdminClient) ListRemoteTargets(ctx context.Context, bucket string) ([]RemoteTarget, error) {
+	query := url.Values{}
+	query.Set("bucket", bucket)
+	query.Set("type", "replication")
+
+	var targets []RemoteTarget
+	if err := a.get(ctx, "/minio/admin/v3/list-remote-targets", query, &targets); err != nil {
+		return nil, fmt.Errorf("failed to list remote targets: %w", err)
+	}
+
+	// A bucket without targets is reported as null; keep it distinct from "not listed"
+	if targets == nil {
+		targets = []RemoteTarget{}
+	}
+	return targets, nil
+}
+
+// KMSKeyStatus asks the server's KMS to use a key; an empty keyID checks the default key
+func (a *AdminClient) KMSKeyStatus(ctx context.Context, keyID string) (*KMSKeyStatus, error) {
+	query := url.Values{}
+	if keyID != "" {
+		query.Set("key-id", keyID)
+	}
+
+	var status KMSKeyStatus
+	if err := a.get(ctx, "/minio/admin/v3/kms/key/status", query, &status); err != nil {
+		return nil, fmt.Errorf("failed to fetch KMS key status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// get sends a signed GET request and decodes the JSON response into out
+func (a *AdminClient) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	target := a.endpoint + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+
+	emptySum := sha256.Sum256(nil)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(emptySum[:]))
+	req = signer.SignV4(*req, a.accessKey, a.secretKey, "", "")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s", resp.Status, path, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/config"
+)
+
+func newTestAdminClient(t *testing.T, handler http.HandlerFunc) *AdminClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := &config.MCConfig{
+		Aliases: map[string]config.AliasConfig{
+			"test": {URL: server.URL, AccessKey: "admin", SecretKey: "secret"},
+		},
+	}
+
+	adminClient, err := CreateAdminClient(cfg, "test", false)
+	require.NoError(t, err)
+	return adminClient
+}
+
+func TestCreateAdminClient(t *testing.T) {
+	cfg := &config.MCConfig{
+		Aliases: map[string]config.AliasConfig{
+			"valid":   {URL: "https://minio.example.com/", AccessKey: "key", SecretKey: "secret"},
+			"invalid": {URL: "minio.example.com", AccessKey: "key", SecretKey: "secret"},
+		},
+	}
+
+	adminClient, err := CreateAdminClient(cfg, "valid", false)
+	require.NoError(t, err)
+	assert.Equal(t, "https://minio.example.com", adminClient.endpoint)
+
+	_, err = CreateAdminClient(cfg, "invalid", false)
+	assert.Error(t, err)
+
+	_, err = CreateAdminClient(cfg, "missing", false)
+	assert.Error(t, err)
+}
+
+func TestAdminClientDataUsageInfo(t *testing.T) {
+	adminClient := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/minio/admin/v3/datausageinfo", r.URL.Path)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=admin/"))
+		w.Write([]byte(`{
+			"lastUpdate": "2024-01-02T03:04:05Z",
+			"objectsCount": 12,
+			"objectsTotalSize": 4096,
+			"bucketsCount": 1,
+			"bucketsUsageInfo": {
+				"data": {"size": 4096, "objectsCount": 12, "versionsCount": 15, "deleteMarkersCount": 2}
+			}
+		}`))
+	})
+
+	usage, err := adminClient.DataUsageInfo(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(12), usage.ObjectsTotalCount)
+	assert.Equal(t, 2024, usage.LastUpdate.Year())
+	assert.Equal(t, BucketUsageInfo{Size: 4096, ObjectsCount: 12, VersionsCount: 15, DeleteMarkersCount: 2}, usage.BucketsUsage["data"])
+}
+
+func TestAdminClientListTiers(t *testing.T) {
+	adminClient := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/minio/admin/v3/tier", r.URL.Path)
+		w.Write([]byte(`[
+			{"Version": "v1", "Type": "s3", "Name": "WARM", "s3": {"bucket": "warm-tier"}},
+			{"Version": "v1", "Type": "minio", "Name": "COLD"}
+		]`))
+	})
+
+	tiers, err := adminClient.ListTiers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WARM", "COLD"}, tiers)
+}
+
+func TestAdminClientListRemoteTargets(t *testing.T) {
+	adminClient := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/minio/admin/v3/list-remote-targets", r.URL.Path)
+		assert.Equal(t, "replication", r.URL.Query().Get("type"))
+		if r.URL.Query().Get("bucket") == "empty" {
+			w.Write([]byte(`null`))
+			return
+		}
+		w.Write([]byte(`[{"sourcebucket": "data", "endpoint": "dr.example.com:9000", "targetbucket": "data-dr",
+			"arn": "arn:minio:replication::3f1c:data-dr", "type": "replication", "secure": true}]`))
+	})
+
+	targets, err := adminClient.ListRemoteTargets(context.Background(), "data")
+	require.NoError(t, err)
+	assert.Equal(t, []RemoteTarget{{SourceBucket: "data", Endpoint: "dr.example.com:9000", TargetBucket: "data-dr",
+		Arn: "arn:minio:replication::3f1c:data-dr", Type: "replication"}}, targets)
+
+	targets, err = adminClient.ListRemoteTargets(context.Background(), "empty")
+	require.NoError(t, err)
+	assert.NotNil(t, targets)
+	assert.Empty(t, targets)
+}
+
+func TestAdminClientKMSKeyStatus(t *testing.T) {
+	adminClient := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/minio/admin/v3/kms/key/status", r.URL.Path)
+		if r.URL.Query().Get("key-id") == "missing" {
+			w.Write([]byte(`{"key-id": "missing", "encryption-error": "key does not exist"}`))
+			return
+		}
+		w.Write([]byte(`{"key-id": "minio-key"}`))
+	})
+
+	status, err := adminClient.KMSKeyStatus(context.Background(), "minio-key")
+	require.NoError(t, err)
+	assert.Equal(t, KMSKeyStatus{KeyID: "minio-key"}, *status)
+
+	status, err = adminClient.KMSKeyStatus(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Equal(t, "key does not exist", status.EncryptionErr)
+}
+
+func TestAdminClientErrorStatus(t *testing.T) {
+	adminClient := newTestAdminClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "access denied", http.StatusForbidden)
+	})
+
+	_, err := adminClient.DataUsageInfo(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "access denied")
+}
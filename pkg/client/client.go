@@ -30,19 +30,11 @@ func CreateMinIOClient(cfg *config.MCConfig, alias string, insecure bool, verbos
 	// Create credentials
 	creds := credentials.NewStaticV4(aliasConfig.AccessKey, aliasConfig.SecretKey, "")
 
-	// Create transport with TLS configuration
-	transport := &http.Transport{}
-	if useSSL {
-		transport.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: skipVerify,
-		}
-	}
-
 	// Create MinIO client
 	client, err := minio.New(endpoint, &minio.Options{
 		Creds:     creds,
 		Secure:    useSSL,
-		Transport: transport,
+		Transport: newTransport(aliasConfig.URL, skipVerify),
 	})
 
 	if err != nil {
@@ -56,6 +48,18 @@ func CreateMinIOClient(cfg *config.MCConfig, alias string, insecure bool, verbos
 	return client, nil
 }
 
+// newTransport creates an HTTP transport for an alias URL, skipping certificate
+// verification for HTTPS when asked to
+func newTransport(aliasURL string, skipVerify bool) *http.Transport {
+	transport := &http.Transport{}
+	if strings.HasPrefix(aliasURL, "https://") {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: skipVerify,
+		}
+	}
+	return transport
+}
+
 // ParseURL parses a MinIO URL into alias, bucket, and path components
 func ParseURL(url string) (alias, bucket, path string, err error) {
 	parts := strings.SplitN(url, "/", 3)
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/liamdn8/mc-tool/pkg/config"
+)
+
+// versionsPageSize is the number of versions requested per ListObjectVersions page
+const versionsPageSize = 1000
+
+// VersionLister lists object versions starting after a key. minio-go ignores StartAfter
+// for versioned listings and always lists from the first key, so pages are requested
+// with presigned ListObjectVersions URLs that carry the key marker instead.
+type VersionLister struct {
+	client     *minio.Client
+	httpClient *http.Client
+}
+
+// listVersionsEntry is a Version or DeleteMarker element of a ListObjectVersions page
+type listVersionsEntry struct {
+	XMLName      xml.Name
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified time.Time
+	ETag         string
+	Size         int64
+	StorageClass string
+}
+
+// listVersionsPage is a ListObjectVersions response; Entries keeps versions and delete
+// markers in listing order
+type listVersionsPage struct {
+	EncodingType        string
+	IsTruncated         bool
+	NextKeyMarker       string
+	NextVersionIDMarker string              `xml:"NextVersionIdMarker"`
+	Entries             []listVersionsEntry `xml:",any"`
+}
+
+// CreateVersionLister creates a version lister for the specified alias
+func CreateVersionLister(cfg *config.MCConfig, alias string, insecure bool) (*VersionLister, error) {
+	minioClient, err := CreateMinIOClient(cfg, alias, insecure, false)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasConfig := cfg.Aliases[alias]
+	return &VersionLister{
+		client:     minioClient,
+		httpClient: &http.Client{Transport: newTransport(aliasConfig.URL, insecure || aliasConfig.Insecure)},
+	}, nil
+}
+
+// ListVersions lists the versions and delete markers of keys after keyMarker (all keys
+// when empty) in listing order. Listing stops when ctx is canceled.
+func (l *VersionLister) ListVersions(ctx context.Context, bucket, prefix, keyMarker string) <-chan minio.ObjectInfo {
+	versions := make(chan minio.ObjectInfo, 1)
+
+	go func() {
+		defer close(versions)
+
+		versionIDMarker := ""
+		for {
+			page, err := l.listPage(ctx, bucket, prefix, keyMarker, versionIDMarker)
+			if err != nil {
+				select {
+				case versions <- minio.ObjectInfo{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			for _, entry := range page.Entries {
+				if entry.XMLName.Local != "Version" && entry.XMLName.Local != "DeleteMarker" {
+					continue
+				}
+				key := entry.Key
+				if page.EncodingType == "url" {
+					if key, err = url.QueryUnescape(key); err != nil {
+						key = entry.Key
+					}
+				}
+
+				select {
+				case versions <- minio.ObjectInfo{
+					Key:            key,
+					VersionID:      entry.VersionID,
+					IsLatest:       entry.IsLatest,
+					IsDeleteMarker: entry.XMLName.Local == "DeleteMarker",
+					LastModified:   entry.LastModified.Truncate(time.Millisecond),
+					ETag:           trimETag(entry.ETag),
+					Size:           entry.Size,
+					StorageClass:   entry.StorageClass,
+				}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if !page.IsTruncated {
+				return
+			}
+			keyMarker, versionIDMarker = page.NextKeyMarker, page.NextVersionIDMarker
+			if page.EncodingType == "url" {
+				if unescaped, err := url.QueryUnescape(keyMarker); err == nil {
+					keyMarker = unescaped
+				}
+			}
+		}
+	}()
+
+	return versions
+}
+
+// listPage requests one page of versions after the key and version markers
+func (l *VersionLister) listPage(ctx context.Context, bucket, prefix, keyMarker, versionIDMarker string) (*listVersionsPage, error) {
+	query := url.Values{}
+	query.Set("versions", "")
+	query.Set("prefix", prefix)
+	query.Set("max-keys", fmt.Sprint(versionsPageSize))
+	query.Set("encoding-type", "url")
+	if keyMarker != "" {
+		query.Set("key-marker", keyMarker)
+	}
+	if versionIDMarker != "" {
+		query.Set("version-id-marker", versionIDMarker)
+	}
+
+	target, err := l.client.Presign(ctx, http.MethodGet, bucket, "", 15*time.Minute, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign version listing: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := minio.ErrorResponse{StatusCode: resp.StatusCode, BucketName: bucket}
+		if err := xml.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Code == "" {
+			return nil, fmt.Errorf("failed to list versions: %s", resp.Status)
+		}
+		return nil, errResp
+	}
+
+	var page listVersionsPage
+	if err := xml.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode version listing: %w", err)
+	}
+	return &page, nil
+}
+
+// trimETag removes the quotes around a listed ETag, as minio-go does
+func trimETag(etag string) string {
+	if len(etag) >= 2 && etag[0] == '"' && etag[len(etag)-1] == '"' {
+		return etag[1 : len(etag)-1]
+	}
+	return etag
+}
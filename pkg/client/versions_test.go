@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/config"
+)
+
+func newTestVersionLister(t *testing.T, handler http.HandlerFunc) *VersionLister {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.MCConfig{
+		Aliases: map[string]config.AliasConfig{
+			"test": {URL: server.URL, AccessKey: "access", SecretKey: "secret"},
+		},
+	}
+
+	lister, err := CreateVersionLister(cfg, "test", false)
+	require.NoError(t, err)
+	return lister
+}
+
+func TestVersionListerListVersions(t *testing.T) {
+	var markers []string
+	lister := newTestVersionLister(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "/data/", r.URL.Path)
+		assert.Equal(t, "logs/", query.Get("prefix"))
+		assert.NotEmpty(t, query.Get("X-Amz-Signature"))
+		markers = append(markers, query.Get("key-marker")+"|"+query.Get("version-id-marker"))
+
+		if query.Get("key-marker") == "logs/2024/a.log" {
+			w.Write([]byte(`<ListVersionsResult><EncodingType>url</EncodingType><IsTruncated>false</IsTruncated>` +
+				`<Version><Key>logs/2024/b%20c.log</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest>` +
+				`<LastModified>2024-03-01T00:00:00.000Z</LastModified><ETag>"beef"</ETag><Size>7</Size><StorageClass>STANDARD</StorageClass></Version>` +
+				`</ListVersionsResult>`))
+			return
+		}
+		w.Write([]byte(`<ListVersionsResult><Name>data</Name><Prefix>logs/</Prefix><EncodingType>url</EncodingType>` +
+			`<IsTruncated>true</IsTruncated><NextKeyMarker>logs/2024/a.log</NextKeyMarker><NextVersionIdMarker>v1</NextVersionIdMarker>` +
+			`<DeleteMarker><Key>logs/2024/a.log</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest>` +
+			`<LastModified>2024-02-01T00:00:00.000Z</LastModified></DeleteMarker>` +
+			`<Version><Key>logs/2024/a.log</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest>` +
+			`<LastModified>2024-01-01T00:00:00.000Z</LastModified><ETag>"cafe"</ETag><Size>42</Size><StorageClass>STANDARD</StorageClass></Version>` +
+			`</ListVersionsResult>`))
+	})
+
+	var versions []minio.ObjectInfo
+	for info := range lister.ListVersions(context.Background(), "data", "logs/", "logs/2023/z.log") {
+		require.NoError(t, info.Err)
+		versions = append(versions, info)
+	}
+
+	assert.Equal(t, []string{"logs/2023/z.log|", "logs/2024/a.log|v1"}, markers)
+	require.Len(t, versions, 3)
+	assert.True(t, versions[0].IsDeleteMarker)
+	assert.Equal(t, "v2", versions[0].VersionID)
+	assert.False(t, versions[1].IsDeleteMarker)
+	assert.Equal(t, "cafe", versions[1].ETag)
+	assert.Equal(t, int64(42), versions[1].Size)
+	assert.Equal(t, "logs/2024/b c.log", versions[2].Key)
+	assert.True(t, versions[2].IsLatest)
+}
+
+func TestVersionListerError(t *testing.T) {
+	lister := newTestVersionLister(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist</Message></Error>`))
+	})
+
+	info := <-lister.ListVersions(context.Background(), "data", "", "")
+	require.Error(t, info.Err)
+	assert.Equal(t, "NoSuchBucket", minio.ToErrorResponse(info.Err).Code)
+}
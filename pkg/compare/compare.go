@@ -34,7 +34,6 @@ type ComparisonResult struct {
 // CompareObjects performs comparison between two MinIO buckets
 func CompareObjects(sourceClient, targetClient *minio.Client, sourceBucket, sourcePath, targetBucket, targetPath string, versionsMode bool) ([]ComparisonResult, error) {
 	ctx := context.Background()
-	var results []ComparisonResult
 
 	// Get objects from source (always gets all versions)
 	allSourceObjects, err := ListObjects(ctx, sourceClient, sourceBucket, sourcePath)
@@ -48,6 +47,13 @@ func CompareObjects(sourceClient, targetClient *minio.Client, sourceBucket, sour
 		return nil, fmt.Errorf("failed to list target objects: %v", err)
 	}
 
+	return CompareObjectLists(allSourceObjects, allTargetObjects, versionsMode), nil
+}
+
+// CompareObjectLists compares two lists of object versions, e.g. from listings or inventory reports
+func CompareObjectLists(allSourceObjects, allTargetObjects []*ObjectInfo, versionsMode bool) []ComparisonResult {
+	var results []ComparisonResult
+
 	// Filter objects based on comparison mode
 	var sourceObjects, targetObjects []*ObjectInfo
 
@@ -117,7 +123,7 @@ func CompareObjects(sourceClient, targetClient *minio.Client, sourceBucket, sour
 		}
 	}
 
-	return results, nil
+	return results
 }
 
 // ListObjects lists all objects in a bucket with the given prefix
@@ -136,23 +142,26 @@ func ListObjects(ctx context.Context, client *minio.Client, bucket, prefix strin
 			return nil, objInfo.Err
 		}
 
-		obj := &ObjectInfo{
-			Key:            objInfo.Key,
-			ETag:           objInfo.ETag,
-			Size:           objInfo.Size,
-			LastModified:   objInfo.LastModified,
-			VersionID:      objInfo.VersionID,
-			IsLatest:       objInfo.IsLatest,
-			IsDeleteMarker: objInfo.IsDeleteMarker,
-			StorageClass:   objInfo.StorageClass,
-		}
-
-		objects = append(objects, obj)
+		objects = append(objects, NewObjectInfo(objInfo))
 	}
 
 	return objects, nil
 }
 
+// NewObjectInfo converts a listed MinIO object version to an ObjectInfo
+func NewObjectInfo(objInfo minio.ObjectInfo) *ObjectInfo {
+	return &ObjectInfo{
+		Key:            objInfo.Key,
+		ETag:           objInfo.ETag,
+		Size:           objInfo.Size,
+		LastModified:   objInfo.LastModified,
+		VersionID:      objInfo.VersionID,
+		IsLatest:       objInfo.IsLatest,
+		IsDeleteMarker: objInfo.IsDeleteMarker,
+		StorageClass:   objInfo.StorageClass,
+	}
+}
+
 func compareVersions(key string, sourceObjs, targetObjs []*ObjectInfo) []ComparisonResult {
 	var results []ComparisonResult
 
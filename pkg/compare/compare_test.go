@@ -49,6 +49,26 @@ func TestCompareCurrentVersions(t *testing.T) {
 	assert.Equal(t, "missing_target", result.Status)
 }
 
+func TestCompareObjectLists(t *testing.T) {
+	source := []*ObjectInfo{
+		{Key: "same.txt", ETag: "abc", Size: 1, IsLatest: true},
+		{Key: "deleted.txt", IsLatest: true, IsDeleteMarker: true},
+		{Key: "deleted.txt", ETag: "old", Size: 5},
+	}
+	target := []*ObjectInfo{
+		{Key: "same.txt", ETag: "abc", Size: 1, IsLatest: true},
+		{Key: "deleted.txt", ETag: "old", Size: 5, IsLatest: true},
+	}
+
+	// Delete markers hide the object from the current-version comparison
+	results := CompareObjectLists(source, target, false)
+	statuses := make(map[string]string)
+	for _, result := range results {
+		statuses[result.Key] = result.Status
+	}
+	assert.Equal(t, map[string]string{"same.txt": "identical", "deleted.txt": "missing_source"}, statuses)
+}
+
 func TestCompareVersions(t *testing.T) {
 	// Create test objects with different versions
 	sourceObjs := []*ObjectInfo{
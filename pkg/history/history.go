@@ -0,0 +1,154 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/liamdn8/mc-tool/pkg/analyze"
+)
+
+// Record is a single persisted analyze result
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	Alias     string    `json:"alias"`
+	Bucket    string    `json:"bucket"`
+	Prefix    string    `json:"prefix"`
+
+	TotalVersions        int   `json:"total_versions"`
+	CurrentObjects       int   `json:"current_objects"`
+	CurrentSize          int64 `json:"current_size"`
+	NoncurrentVersions   int   `json:"noncurrent_versions"`
+	NoncurrentSize       int64 `json:"noncurrent_size"`
+	DeleteMarkers        int   `json:"delete_markers"`
+	IncompleteUploads    int   `json:"incomplete_uploads"`
+	IncompleteUploadSize int64 `json:"incomplete_upload_size"`
+}
+
+// NewRecord creates a record from an analyze summary
+func NewRecord(alias, prefix string, summary analyze.BucketSummary, at time.Time) Record {
+	return Record{
+		Timestamp:            at.UTC(),
+		Alias:                alias,
+		Bucket:               summary.Bucket,
+		Prefix:               prefix,
+		TotalVersions:        summary.TotalVersions,
+		CurrentObjects:       summary.CurrentObjects,
+		CurrentSize:          summary.CurrentSize,
+		NoncurrentVersions:   summary.NoncurrentVersions,
+		NoncurrentSize:       summary.NoncurrentSize,
+		DeleteMarkers:        summary.DeleteMarkers,
+		IncompleteUploads:    summary.IncompleteUploads,
+		IncompleteUploadSize: summary.IncompleteUploadSize,
+	}
+}
+
+// TotalSize returns the bytes stored by all versions and incomplete uploads
+func (r Record) TotalSize() int64 {
+	return r.CurrentSize + r.NoncurrentSize + r.IncompleteUploadSize
+}
+
+// Store is a directory of JSONL files, one per alias and bucket
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the default history directory
+func DefaultDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+
+	return filepath.Join(homeDir, ".mc-tool", "history"), nil
+}
+
+// OpenStore opens the history store in dir, using DefaultDir when dir is empty
+func OpenStore(dir string) (*Store, error) {
+	if dir == "" {
+		defaultDir, err := DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = defaultDir
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the store's directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+func (s *Store) path(alias, bucket string) string {
+	return filepath.Join(s.dir, alias, bucket+".jsonl")
+}
+
+// Append adds a record to the store
+func (s *Store) Append(record Record) error {
+	path := s.path(record.Alias, record.Bucket)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history record: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns the records of an alias, bucket and prefix, oldest first
+func (s *Store) Load(alias, bucket, prefix string) ([]Record, error) {
+	f, err := os.Open(s.path(alias, bucket))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse history record at line %d: %w", line, err)
+		}
+		if record.Prefix == prefix {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+
+	return records, nil
+}
@@ -0,0 +1,83 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/analyze"
+)
+
+func TestNewRecord(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("ICT", 7*3600))
+	summary := analyze.BucketSummary{
+		Bucket:               "data",
+		TotalVersions:        10,
+		CurrentObjects:       6,
+		CurrentSize:          600,
+		NoncurrentVersions:   3,
+		NoncurrentSize:       300,
+		DeleteMarkers:        1,
+		IncompleteUploads:    2,
+		IncompleteUploadSize: 50,
+	}
+
+	record := NewRecord("minio1", "logs/", summary, at)
+
+	assert.Equal(t, time.UTC, record.Timestamp.Location())
+	assert.Equal(t, "minio1", record.Alias)
+	assert.Equal(t, "data", record.Bucket)
+	assert.Equal(t, "logs/", record.Prefix)
+	assert.Equal(t, int64(950), record.TotalSize())
+}
+
+func TestStoreAppendAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(dir)
+	require.NoError(t, err)
+
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Timestamp: base.Add(48 * time.Hour), Alias: "minio1", Bucket: "data", CurrentObjects: 3},
+		{Timestamp: base, Alias: "minio1", Bucket: "data", CurrentObjects: 1},
+		{Timestamp: base, Alias: "minio1", Bucket: "data", Prefix: "logs/", CurrentObjects: 7},
+		{Timestamp: base, Alias: "minio1", Bucket: "other", CurrentObjects: 9},
+	}
+	for _, record := range records {
+		require.NoError(t, store.Append(record))
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "minio1", "data.jsonl"))
+	require.NoError(t, err)
+
+	loaded, err := store.Load("minio1", "data", "")
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, 1, loaded[0].CurrentObjects)
+	assert.Equal(t, 3, loaded[1].CurrentObjects)
+
+	loaded, err = store.Load("minio1", "data", "logs/")
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, 7, loaded[0].CurrentObjects)
+
+	loaded, err = store.Load("minio2", "data", "")
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+}
+
+func TestStoreLoadInvalidRecord(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "minio1"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "minio1", "data.jsonl"), []byte("{not json}\n"), 0o644))
+
+	store, err := OpenStore(dir)
+	require.NoError(t, err)
+
+	_, err = store.Load("minio1", "data", "")
+	assert.Error(t, err)
+}
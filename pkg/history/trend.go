@@ -0,0 +1,202 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// MetricTrend describes how a single metric changed over the recorded period
+type MetricTrend struct {
+	Name  string
+	First int64
+	Last  int64
+	// PerDay is the least-squares growth rate per day
+	PerDay float64
+}
+
+// Change returns the difference between the last and first value
+func (m MetricTrend) Change() int64 {
+	return m.Last - m.First
+}
+
+// Trend summarizes the growth of an alias, bucket and prefix over time
+type Trend struct {
+	Records []Record
+	Metrics []MetricTrend
+}
+
+// QuotaForecast describes when total bytes are expected to reach a quota
+type QuotaForecast struct {
+	Quota    int64
+	Exceeded bool
+	// Reachable is false when total bytes are not growing
+	Reachable bool
+	At        time.Time
+}
+
+// trendMetrics are the metrics reported by ComputeTrend
+var trendMetrics = []struct {
+	name  string
+	value func(Record) int64
+}{
+	{"Objects", func(r Record) int64 { return int64(r.CurrentObjects) }},
+	{"Bytes", func(r Record) int64 { return r.TotalSize() }},
+	{"Versions", func(r Record) int64 { return int64(r.TotalVersions) }},
+	{"Delete markers", func(r Record) int64 { return int64(r.DeleteMarkers) }},
+}
+
+// linearFit returns the least-squares intercept and slope per day of the values
+// against days elapsed since the first record
+func linearFit(records []Record, value func(Record) int64) (intercept, slope float64) {
+	n := float64(len(records))
+	if n == 0 {
+		return 0, 0
+	}
+
+	start := records[0].Timestamp
+	var sumX, sumY, sumXY, sumXX float64
+	for _, r := range records {
+		x := float64(r.Timestamp.Sub(start)) / float64(day)
+		y := float64(value(r))
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return sumY / n, 0
+	}
+
+	slope = (n*sumXY - sumX*sumY) / denominator
+	intercept = (sumY - slope*sumX) / n
+	return intercept, slope
+}
+
+// ComputeTrend computes growth rates from records sorted oldest first
+func ComputeTrend(records []Record) *Trend {
+	trend := &Trend{Records: records}
+	if len(records) == 0 {
+		return trend
+	}
+
+	first, last := records[0], records[len(records)-1]
+	for _, metric := range trendMetrics {
+		_, slope := linearFit(records, metric.value)
+		trend.Metrics = append(trend.Metrics, MetricTrend{
+			Name:   metric.name,
+			First:  metric.value(first),
+			Last:   metric.value(last),
+			PerDay: slope,
+		})
+	}
+
+	return trend
+}
+
+// ForecastQuota extrapolates total bytes linearly to estimate when quota is reached
+func ForecastQuota(records []Record, quota int64) QuotaForecast {
+	forecast := QuotaForecast{Quota: quota}
+	if len(records) == 0 {
+		return forecast
+	}
+
+	if records[len(records)-1].TotalSize() >= quota {
+		forecast.Exceeded = true
+		forecast.Reachable = true
+		forecast.At = records[len(records)-1].Timestamp
+		return forecast
+	}
+
+	intercept, slope := linearFit(records, Record.TotalSize)
+	if slope <= 0 {
+		return forecast
+	}
+
+	days := (float64(quota) - intercept) / slope
+	forecast.Reachable = true
+	forecast.At = records[0].Timestamp.Add(time.Duration(days * float64(day)))
+	return forecast
+}
+
+// ParseSize parses a byte size such as "500GiB", "2TB" or "1048576"
+func ParseSize(size string) (int64, error) {
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40}, {"PiB", 1 << 50},
+		{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12}, {"PB", 1e15},
+		{"B", 1},
+	}
+
+	trimmed := strings.TrimSpace(size)
+	for _, unit := range units {
+		if strings.HasSuffix(strings.ToUpper(trimmed), strings.ToUpper(unit.suffix)) {
+			number := strings.TrimSpace(trimmed[:len(trimmed)-len(unit.suffix)])
+			value, err := strconv.ParseFloat(number, 64)
+			if err != nil || value < 0 {
+				return 0, fmt.Errorf("invalid size: %s", size)
+			}
+			return int64(value * float64(unit.multiplier)), nil
+		}
+	}
+
+	value, err := strconv.ParseInt(trimmed, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size: %s (expected e.g. 500GiB, 2TB or bytes)", size)
+	}
+	return value, nil
+}
+
+// DisplayTrend displays growth rates and, when quota is positive, a quota forecast
+func DisplayTrend(w io.Writer, target string, trend *Trend, quota int64) {
+	fmt.Fprintf(w, "Trend: %s\n", target)
+	fmt.Fprintln(w, "=================================")
+
+	if len(trend.Records) == 0 {
+		fmt.Fprintln(w, "ℹ No history recorded yet (run analyze with --save-history)")
+		return
+	}
+
+	first, last := trend.Records[0], trend.Records[len(trend.Records)-1]
+	fmt.Fprintf(w, "Snapshots: %d (%s to %s)\n", len(trend.Records),
+		first.Timestamp.Format(time.RFC3339), last.Timestamp.Format(time.RFC3339))
+
+	if len(trend.Records) < 2 {
+		fmt.Fprintln(w, "ℹ At least two snapshots are needed to compute growth rates")
+	}
+
+	fmt.Fprintf(w, "\n  %-16s %16s %16s %16s %16s\n", "Metric", "First", "Latest", "Change", "Per Day")
+	for _, metric := range trend.Metrics {
+		fmt.Fprintf(w, "  %-16s %16d %16d %+16d %+16.1f\n", metric.Name, metric.First, metric.Last, metric.Change(), metric.PerDay)
+	}
+
+	if quota <= 0 {
+		return
+	}
+
+	forecast := ForecastQuota(trend.Records, quota)
+	fmt.Fprintf(w, "\nQuota Forecast (%d bytes):\n", quota)
+	switch {
+	case forecast.Exceeded:
+		fmt.Fprintf(w, "❌ Quota already exceeded: %d bytes stored\n", last.TotalSize())
+	case len(trend.Records) < 2:
+		fmt.Fprintln(w, "➖ Not enough snapshots to forecast")
+	case !forecast.Reachable:
+		fmt.Fprintln(w, "✅ Total bytes are not growing; quota will not be reached at the current rate")
+	default:
+		remaining := forecast.At.Sub(last.Timestamp)
+		if remaining < 0 {
+			remaining = 0
+		}
+		fmt.Fprintf(w, "⚠ Quota expected to be reached on %s (in about %d days)\n",
+			forecast.At.Format("2006-01-02"), int(remaining/day))
+	}
+}
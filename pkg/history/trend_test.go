@@ -0,0 +1,91 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func growingRecords() []Record {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var records []Record
+	for i := 0; i < 5; i++ {
+		records = append(records, Record{
+			Timestamp:      base.Add(time.Duration(i) * 24 * time.Hour),
+			CurrentObjects: 100 + 10*i,
+			CurrentSize:    int64(1000 + 100*i),
+			TotalVersions:  120 + 12*i,
+			DeleteMarkers:  5,
+		})
+	}
+	return records
+}
+
+func TestComputeTrend(t *testing.T) {
+	trend := ComputeTrend(growingRecords())
+
+	require.Len(t, trend.Metrics, 4)
+
+	objects := trend.Metrics[0]
+	assert.Equal(t, "Objects", objects.Name)
+	assert.Equal(t, int64(100), objects.First)
+	assert.Equal(t, int64(140), objects.Last)
+	assert.Equal(t, int64(40), objects.Change())
+	assert.InDelta(t, 10, objects.PerDay, 0.001)
+
+	bytes := trend.Metrics[1]
+	assert.InDelta(t, 100, bytes.PerDay, 0.001)
+
+	deleteMarkers := trend.Metrics[3]
+	assert.InDelta(t, 0, deleteMarkers.PerDay, 0.001)
+}
+
+func TestForecastQuota(t *testing.T) {
+	records := growingRecords()
+	base := records[0].Timestamp
+
+	forecast := ForecastQuota(records, 2000)
+	assert.True(t, forecast.Reachable)
+	assert.False(t, forecast.Exceeded)
+	assert.Equal(t, base.Add(10*24*time.Hour), forecast.At)
+
+	forecast = ForecastQuota(records, 1200)
+	assert.True(t, forecast.Exceeded)
+
+	flat := []Record{
+		{Timestamp: base, CurrentSize: 500},
+		{Timestamp: base.Add(24 * time.Hour), CurrentSize: 400},
+	}
+	forecast = ForecastQuota(flat, 2000)
+	assert.False(t, forecast.Reachable)
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "1048576", expected: 1048576},
+		{input: "500GiB", expected: 500 << 30},
+		{input: "2TB", expected: 2e12},
+		{input: "1.5 MiB", expected: 3 << 19},
+		{input: "10kb", expected: 10000},
+		{input: "-1", wantErr: true},
+		{input: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			size, err := ParseSize(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+}
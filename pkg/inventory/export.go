@@ -0,0 +1,116 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ExportSummary describes an exported inventory report
+type ExportSummary struct {
+	Manifest *Manifest
+	Records  int
+	// Vanished counts versions deleted while the export ran, which are left out
+	Vanished int
+	// Incomplete lists the versions written without some optional fields, with the reason
+	Incomplete []string
+}
+
+// Export lists every version of a bucket and writes it as an inventory report in dir.
+// Versions are written as they are listed, so memory use does not grow with the bucket.
+// Versions whose optional fields cannot be read are still written and reported; on any
+// other error the data files written so far are removed.
+func Export(ctx context.Context, client *minio.Client, bucket, dir string, opts Options) (*ExportSummary, error) {
+	writer, err := NewWriter(dir, bucket, opts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := minio.ListObjectsOptions{
+		Prefix:       opts.Prefix,
+		Recursive:    true,
+		WithVersions: true,
+	}
+
+	summary := &ExportSummary{}
+	for objInfo := range client.ListObjects(ctx, bucket, listOpts) {
+		if objInfo.Err != nil {
+			writer.Abort()
+			return nil, fmt.Errorf("failed to list objects: %w", objInfo.Err)
+		}
+
+		record := Record{
+			Bucket:         bucket,
+			Key:            objInfo.Key,
+			VersionID:      objInfo.VersionID,
+			IsLatest:       objInfo.IsLatest,
+			IsDeleteMarker: objInfo.IsDeleteMarker,
+			Size:           objInfo.Size,
+			LastModified:   objInfo.LastModified,
+			ETag:           objInfo.ETag,
+			StorageClass:   objInfo.StorageClass,
+		}
+
+		if !objInfo.IsDeleteMarker {
+			if err := enrichRecord(ctx, client, &record, opts); err == errVanished {
+				summary.Vanished++
+				continue
+			} else if err != nil {
+				summary.Incomplete = append(summary.Incomplete, err.Error())
+			}
+		}
+
+		if err := writer.Write(record); err != nil {
+			writer.Abort()
+			return nil, err
+		}
+	}
+
+	manifest, err := writer.Close()
+	if err != nil {
+		writer.Abort()
+		return nil, err
+	}
+
+	summary.Manifest = manifest
+	summary.Records = writer.Records()
+	return summary, nil
+}
+
+// errVanished is returned for a version deleted after it was listed
+var errVanished = errors.New("version no longer exists")
+
+// vanished reports whether an error means the object version no longer exists
+func vanished(err error) bool {
+	code := minio.ToErrorResponse(err).Code
+	return code == "NoSuchKey" || code == "NoSuchVersion"
+}
+
+// enrichRecord fills the optional fields that are not part of the listing
+func enrichRecord(ctx context.Context, client *minio.Client, record *Record, opts Options) error {
+	if opts.Encryption || opts.Replication {
+		stat, err := client.StatObject(ctx, record.Bucket, record.Key, minio.StatObjectOptions{VersionID: record.VersionID})
+		if vanished(err) {
+			return errVanished
+		} else if err != nil {
+			return fmt.Errorf("failed to stat %s (version %s): %w", record.Key, record.VersionID, err)
+		}
+		record.EncryptionStatus = encryptionStatus(stat.Metadata)
+		record.ReplicationStatus = stat.ReplicationStatus
+	}
+
+	if opts.Tags {
+		objectTags, err := client.GetObjectTagging(ctx, record.Bucket, record.Key, minio.GetObjectTaggingOptions{VersionID: record.VersionID})
+		if vanished(err) {
+			return errVanished
+		} else if err != nil {
+			return fmt.Errorf("failed to get tags of %s (version %s): %w", record.Key, record.VersionID, err)
+		}
+		record.Tags = objectTags.String()
+	}
+
+	return nil
+}
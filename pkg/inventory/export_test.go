@@ -0,0 +1,68 @@
+package inventory
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSkipsVanishedVersions(t *testing.T) {
+	version := func(key, id string) string {
+		return `<Version><Key>` + key + `</Key><VersionId>` + id + `</VersionId><IsLatest>true</IsLatest>` +
+			`<LastModified>2024-01-01T00:00:00.000Z</LastModified><ETag>"abc"</ETag><Size>3</Size>` +
+			`<StorageClass>STANDARD</StorageClass></Version>`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		switch {
+		case r.URL.Query().Has("versions"):
+			w.Write([]byte(`<ListVersionsResult><Name>data</Name><IsTruncated>false</IsTruncated>` +
+				version("kept.txt", "v1") + version("gone.txt", "v2") + version("locked.txt", "v3") +
+				`</ListVersionsResult>`))
+		case strings.HasSuffix(r.URL.Path, "/gone.txt"):
+			// Deleted after it was listed
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchVersion</Code><Message>The specified version does not exist.</Message></Error>`))
+		case strings.HasSuffix(r.URL.Path, "/locked.txt"):
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`))
+		default:
+			w.Write([]byte(`<Tagging><TagSet><Tag><Key>team</Key><Value>a</Value></Tag></TagSet></Tagging>`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	summary, err := Export(context.Background(), client, "data", dir, Options{Tags: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, summary.Records)
+	assert.Equal(t, 1, summary.Vanished)
+	require.Len(t, summary.Incomplete, 1)
+	assert.Contains(t, summary.Incomplete[0], "locked.txt")
+
+	require.Len(t, summary.Manifest.Files, 1)
+	rows := readRows(t, filepath.Join(dir, summary.Manifest.Files[0].Key))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "kept.txt", rows[0][1])
+	assert.Equal(t, "locked.txt", rows[1][1])
+
+	_, err = os.Stat(filepath.Join(dir, "manifest.json"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,153 @@
+package inventory
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Inventory file formats
+const (
+	FormatCSV     = "CSV"
+	FormatParquet = "Parquet"
+	FormatORC     = "ORC"
+)
+
+// manifestVersion is the S3 Inventory manifest version
+const manifestVersion = "2016-11-30"
+
+// Inventory fields, named as in S3 Inventory reports
+const (
+	FieldBucket            = "Bucket"
+	FieldKey               = "Key"
+	FieldVersionID         = "VersionId"
+	FieldIsLatest          = "IsLatest"
+	FieldIsDeleteMarker    = "IsDeleteMarker"
+	FieldSize              = "Size"
+	FieldLastModifiedDate  = "LastModifiedDate"
+	FieldETag              = "ETag"
+	FieldStorageClass      = "StorageClass"
+	FieldEncryptionStatus  = "EncryptionStatus"
+	FieldReplicationStatus = "ReplicationStatus"
+	// FieldTags is not part of S3 Inventory; tags are written URL-encoded as k1=v1&k2=v2
+	FieldTags = "Tags"
+)
+
+// S3 Inventory encryption statuses
+const (
+	EncryptionNone   = "NOT-SSE"
+	EncryptionSSES3  = "SSE-S3"
+	EncryptionSSEC   = "SSE-C"
+	EncryptionSSEKMS = "SSE-KMS"
+)
+
+// lastModifiedLayout is the timestamp format of LastModifiedDate
+const lastModifiedLayout = "2006-01-02T15:04:05.000Z"
+
+// Record is one object version in an inventory
+type Record struct {
+	Bucket            string
+	Key               string
+	VersionID         string
+	IsLatest          bool
+	IsDeleteMarker    bool
+	Size              int64
+	LastModified      time.Time
+	ETag              string
+	StorageClass      string
+	EncryptionStatus  string
+	ReplicationStatus string
+	Tags              string
+}
+
+// ManifestFile is a data file listed in a manifest
+type ManifestFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5Checksum string `json:"MD5checksum"`
+}
+
+// Manifest describes an inventory report in the S3 Inventory manifest.json format
+type Manifest struct {
+	SourceBucket      string         `json:"sourceBucket"`
+	DestinationBucket string         `json:"destinationBucket,omitempty"`
+	Version           string         `json:"version"`
+	CreationTimestamp string         `json:"creationTimestamp"`
+	FileFormat        string         `json:"fileFormat"`
+	FileSchema        string         `json:"fileSchema"`
+	Files             []ManifestFile `json:"files"`
+}
+
+// Fields returns the field names of the manifest's file schema
+func (m *Manifest) Fields() []string {
+	var fields []string
+	for _, field := range strings.Split(m.FileSchema, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Options selects the format and optional fields of an exported inventory and how it
+// is chunked
+type Options struct {
+	Prefix string
+	// Format is FormatCSV (the default) or FormatParquet
+	Format string
+	// ChunkSize is the uncompressed size after which a new data file is started
+	ChunkSize int64
+	// Encryption, Replication and Tags add fields that need one request per version
+	Encryption  bool
+	Replication bool
+	Tags        bool
+}
+
+// Fields returns the inventory fields written with these options
+func (o Options) Fields() []string {
+	fields := []string{
+		FieldBucket, FieldKey, FieldVersionID, FieldIsLatest, FieldIsDeleteMarker,
+		FieldSize, FieldLastModifiedDate, FieldETag, FieldStorageClass,
+	}
+	if o.Encryption {
+		fields = append(fields, FieldEncryptionStatus)
+	}
+	if o.Replication {
+		fields = append(fields, FieldReplicationStatus)
+	}
+	if o.Tags {
+		fields = append(fields, FieldTags)
+	}
+	return fields
+}
+
+// ParseFormat validates an inventory output format
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", "csv":
+		return FormatCSV, nil
+	case "parquet":
+		return FormatParquet, nil
+	case "orc":
+		return "", fmt.Errorf("ORC output is not supported; use csv or parquet")
+	default:
+		return "", fmt.Errorf("invalid format: %s (expected csv or parquet)", format)
+	}
+}
+
+// encryptionStatus maps an object's response headers to an S3 Inventory encryption status
+func encryptionStatus(header http.Header) string {
+	if header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "" {
+		return EncryptionSSEC
+	}
+
+	switch header.Get("X-Amz-Server-Side-Encryption") {
+	case "":
+		return EncryptionNone
+	case "aws:kms", "aws:kms:dsse":
+		return EncryptionSSEKMS
+	default:
+		return EncryptionSSES3
+	}
+}
@@ -0,0 +1,60 @@
+package inventory
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("csv")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = ParseFormat("Parquet")
+	require.NoError(t, err)
+	assert.Equal(t, FormatParquet, format)
+
+	_, err = ParseFormat("orc")
+	assert.ErrorContains(t, err, "not supported")
+
+	_, err = ParseFormat("xlsx")
+	assert.Error(t, err)
+}
+
+func TestOptionsFields(t *testing.T) {
+	assert.Len(t, Options{}.Fields(), 9)
+
+	fields := Options{Encryption: true, Replication: true, Tags: true}.Fields()
+	assert.Equal(t, []string{FieldEncryptionStatus, FieldReplicationStatus, FieldTags}, fields[9:])
+}
+
+func TestManifestFields(t *testing.T) {
+	manifest := &Manifest{FileSchema: "Bucket, Key,VersionId , Size"}
+	assert.Equal(t, []string{"Bucket", "Key", "VersionId", "Size"}, manifest.Fields())
+}
+
+func TestEncryptionStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   map[string]string
+		expected string
+	}{
+		{name: "unencrypted", expected: EncryptionNone},
+		{name: "sse-s3", header: map[string]string{"X-Amz-Server-Side-Encryption": "AES256"}, expected: EncryptionSSES3},
+		{name: "sse-kms", header: map[string]string{"X-Amz-Server-Side-Encryption": "aws:kms"}, expected: EncryptionSSEKMS},
+		{name: "sse-c", header: map[string]string{"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256"}, expected: EncryptionSSEC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+			assert.Equal(t, tt.expected, encryptionStatus(header))
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package inventory
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// parquetColumns maps inventory fields to the column names of S3 Inventory Parquet files
+var parquetColumns = map[string]string{
+	FieldBucket:            "bucket",
+	FieldKey:               "key",
+	FieldVersionID:         "version_id",
+	FieldIsLatest:          "is_latest",
+	FieldIsDeleteMarker:    "is_delete_marker",
+	FieldSize:              "size",
+	FieldLastModifiedDate:  "last_modified_date",
+	FieldETag:              "e_tag",
+	FieldStorageClass:      "storage_class",
+	FieldEncryptionStatus:  "encryption_status",
+	FieldReplicationStatus: "replication_status",
+	FieldTags:              "tags",
+}
+
+// parquetSchema builds the schema of a Parquet data file with the given fields
+func parquetSchema(fields []string) *parquet.Schema {
+	group := parquet.Group{}
+	for _, field := range fields {
+		var node parquet.Node
+		switch field {
+		case FieldBucket, FieldKey:
+			node = parquet.String()
+		case FieldIsLatest, FieldIsDeleteMarker:
+			node = parquet.Leaf(parquet.BooleanType)
+		case FieldSize:
+			// Delete markers have no size
+			node = parquet.Optional(parquet.Int(64))
+		case FieldLastModifiedDate:
+			node = parquet.Timestamp(parquet.Millisecond)
+		default:
+			node = parquet.Optional(parquet.String())
+		}
+		group[parquetColumns[field]] = node
+	}
+	return parquet.NewSchema("inventory", group)
+}
+
+// parquetEncoder writes records as Snappy-compressed Parquet rows. Keys are written as
+// is; unlike CSV files, S3 Inventory Parquet files do not URL-encode them.
+type parquetEncoder struct {
+	fields []string
+	writer *parquet.Writer
+	size   int64
+}
+
+func newParquetEncoder(w io.Writer, fields []string) *parquetEncoder {
+	writer := parquet.NewWriter(w, parquetSchema(fields), parquet.Compression(&parquet.Snappy))
+	return &parquetEncoder{fields: fields, writer: writer}
+}
+
+func (e *parquetEncoder) Write(record Record) error {
+	row := make(map[string]any, len(e.fields))
+	for _, field := range e.fields {
+		var value any
+		switch field {
+		case FieldBucket:
+			value = record.Bucket
+		case FieldKey:
+			value = record.Key
+		case FieldVersionID:
+			value = optionalString(record.VersionID)
+		case FieldIsLatest:
+			value = record.IsLatest
+		case FieldIsDeleteMarker:
+			value = record.IsDeleteMarker
+		case FieldSize:
+			if !record.IsDeleteMarker {
+				value = record.Size
+			}
+		case FieldLastModifiedDate:
+			value = record.LastModified.UTC()
+		case FieldETag:
+			value = optionalString(record.ETag)
+		case FieldStorageClass:
+			value = optionalString(record.StorageClass)
+		case FieldEncryptionStatus:
+			value = optionalString(record.EncryptionStatus)
+		case FieldReplicationStatus:
+			value = optionalString(record.ReplicationStatus)
+		case FieldTags:
+			value = optionalString(record.Tags)
+		}
+		row[parquetColumns[field]] = value
+	}
+
+	if err := e.writer.Write(row); err != nil {
+		return err
+	}
+
+	// Rows are buffered until the file is closed, so the size is estimated from the values
+	e.size += int64(len(record.Bucket)+len(record.Key)+len(record.VersionID)+len(record.ETag)+
+		len(record.StorageClass)+len(record.EncryptionStatus)+len(record.ReplicationStatus)+len(record.Tags)) + 18
+	return nil
+}
+
+func (e *parquetEncoder) Size() int64 {
+	return e.size
+}
+
+func (e *parquetEncoder) Close() error {
+	return e.writer.Close()
+}
+
+// optionalString returns nil for an empty string so it is written as null
+func optionalString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// readParquetFile parses a Parquet data file. Columns are matched by their S3 Inventory
+// names, so the file's own schema is used rather than the manifest's.
+func readParquetFile(path string) ([]*compare.ObjectInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open inventory file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open inventory file: %w", err)
+	}
+
+	file, err := parquet.OpenFile(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	var objects []*compare.ObjectInfo
+	for n := 1; ; n++ {
+		row := map[string]any{}
+		if err := reader.Read(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		obj, err := parseParquetRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("invalid record in %s at row %d: %w", path, n, err)
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// parseParquetRow converts a Parquet row to an ObjectInfo. Inventories without version
+// columns list only current objects.
+func parseParquetRow(row map[string]any) (*compare.ObjectInfo, error) {
+	obj := &compare.ObjectInfo{IsLatest: true}
+
+	for column, value := range row {
+		if value == nil {
+			continue
+		}
+
+		var ok bool
+		switch column {
+		case "key":
+			obj.Key, ok = parquetString(value)
+		case "version_id":
+			obj.VersionID, ok = parquetString(value)
+		case "is_latest":
+			obj.IsLatest, ok = value.(bool)
+		case "is_delete_marker":
+			obj.IsDeleteMarker, ok = value.(bool)
+		case "size":
+			obj.Size, ok = parquetInt(value)
+		case "last_modified_date":
+			obj.LastModified, ok = parquetTime(value)
+		case "e_tag":
+			obj.ETag, ok = parquetString(value)
+		case "storage_class":
+			obj.StorageClass, ok = parquetString(value)
+		default:
+			ok = true
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %v", column, value)
+		}
+	}
+
+	return obj, nil
+}
+
+func parquetString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
+
+func parquetInt(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// parquetTime accepts timestamps read as time values, milliseconds since the epoch or
+// RFC 3339 strings
+func parquetTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC(), true
+	case int64:
+		return time.UnixMilli(v).UTC(), true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
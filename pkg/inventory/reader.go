@@ -0,0 +1,188 @@
+package inventory
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liamdn8/mc-tool/pkg/compare"
+)
+
+// IsManifestPath reports whether arg names a local inventory manifest rather than an alias/bucket URL
+func IsManifestPath(arg string) bool {
+	if !strings.HasSuffix(arg, ".json") {
+		return false
+	}
+	info, err := os.Stat(arg)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// LoadManifest reads an S3 Inventory manifest.json
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if strings.TrimSpace(manifest.FileSchema) == "" {
+		return nil, fmt.Errorf("manifest %s has no fileSchema", path)
+	}
+
+	return &manifest, nil
+}
+
+// ReadObjects loads every version listed by the CSV or Parquet data files of an inventory
+// manifest, ordered by key with versions in their listed order
+func ReadObjects(manifestPath string) ([]*compare.ObjectInfo, *Manifest, error) {
+	manifest, err := LoadManifest(manifestPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var readFile func(path string) ([]*compare.ObjectInfo, error)
+	switch {
+	case strings.EqualFold(manifest.FileFormat, FormatCSV):
+		fields := manifest.Fields()
+		readFile = func(path string) ([]*compare.ObjectInfo, error) {
+			return readDataFile(path, fields)
+		}
+	case strings.EqualFold(manifest.FileFormat, FormatParquet):
+		readFile = readParquetFile
+	case strings.EqualFold(manifest.FileFormat, FormatORC):
+		return nil, nil, fmt.Errorf("ORC inventories are not supported; configure the bucket inventory to write CSV or Parquet")
+	default:
+		return nil, nil, fmt.Errorf("inventory format %s is not supported (expected CSV or Parquet)", manifest.FileFormat)
+	}
+
+	var objects []*compare.ObjectInfo
+	for _, file := range manifest.Files {
+		path, err := resolveDataFile(filepath.Dir(manifestPath), file.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fileObjects, err := readFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		objects = append(objects, fileObjects...)
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+
+	return objects, manifest, nil
+}
+
+// resolveDataFile finds a data file next to the manifest. S3 manifests list keys relative to
+// the destination bucket (e.g. source/config/data/file.csv.gz) while the manifest lives in a
+// dated directory beside data/, so the key's trailing components are tried against the
+// manifest directory and its parents.
+func resolveDataFile(manifestDir, key string) (string, error) {
+	components := strings.Split(key, "/")
+	for dir, level := manifestDir, 0; level < 3; dir, level = filepath.Dir(dir), level+1 {
+		for i := range components {
+			candidate := filepath.Join(append([]string{dir}, components[i:]...)...)
+			if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+				return candidate, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("inventory data file %s not found near %s", key, manifestDir)
+}
+
+// readDataFile parses a CSV data file, gzip-compressed when its name ends in .gz
+func readDataFile(path string, fields []string) ([]*compare.ObjectInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open inventory file: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(fields)
+
+	var objects []*compare.ObjectInfo
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		obj, err := parseRow(fields, row)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("invalid record in %s at line %d: %w", path, line, err)
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// parseRow converts a data file row to an ObjectInfo. Inventories without version fields
+// list only current objects.
+func parseRow(fields, row []string) (*compare.ObjectInfo, error) {
+	obj := &compare.ObjectInfo{IsLatest: true}
+
+	for i, field := range fields {
+		value := row[i]
+		var err error
+
+		switch field {
+		case FieldKey:
+			obj.Key, err = url.QueryUnescape(value)
+		case FieldVersionID:
+			obj.VersionID = value
+		case FieldIsLatest:
+			obj.IsLatest, err = strconv.ParseBool(value)
+		case FieldIsDeleteMarker:
+			obj.IsDeleteMarker, err = strconv.ParseBool(value)
+		case FieldSize:
+			if value != "" {
+				obj.Size, err = strconv.ParseInt(value, 10, 64)
+			}
+		case FieldLastModifiedDate:
+			obj.LastModified, err = time.Parse(time.RFC3339Nano, value)
+		case FieldETag:
+			obj.ETag = value
+		case FieldStorageClass:
+			obj.StorageClass = value
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", field, value, err)
+		}
+	}
+
+	return obj, nil
+}
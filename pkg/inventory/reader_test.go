@@ -0,0 +1,143 @@
+package inventory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsManifestPath(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "manifest.json")
+	require.NoError(t, os.WriteFile(manifestPath, []byte("{}"), 0o644))
+
+	assert.True(t, IsManifestPath(manifestPath))
+	assert.False(t, IsManifestPath(filepath.Join(dir, "missing.json")))
+	assert.False(t, IsManifestPath("alias/bucket/path"))
+	assert.False(t, IsManifestPath(dir))
+}
+
+func TestReadObjectsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	modified := time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC)
+
+	writer, err := NewWriter(dir, "data", Options{ChunkSize: 1}, time.Now())
+	require.NoError(t, err)
+	records := []Record{
+		{Bucket: "data", Key: "b/file two.txt", VersionID: "b1", IsLatest: true, Size: 7, LastModified: modified, ETag: "e2"},
+		{Bucket: "data", Key: "a.txt", VersionID: "a2", IsLatest: true, IsDeleteMarker: true, LastModified: modified},
+		{Bucket: "data", Key: "a.txt", VersionID: "a1", Size: 3, LastModified: modified, ETag: "e1", StorageClass: "STANDARD"},
+	}
+	for _, record := range records {
+		require.NoError(t, writer.Write(record))
+	}
+	_, err = writer.Close()
+	require.NoError(t, err)
+
+	objects, manifest, err := ReadObjects(filepath.Join(dir, "manifest.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", manifest.SourceBucket)
+	require.Len(t, objects, 3)
+
+	assert.Equal(t, "a.txt", objects[0].Key)
+	assert.True(t, objects[0].IsDeleteMarker)
+	assert.Equal(t, "a1", objects[1].VersionID)
+	assert.False(t, objects[1].IsLatest)
+	assert.Equal(t, int64(3), objects[1].Size)
+	assert.Equal(t, "STANDARD", objects[1].StorageClass)
+	assert.Equal(t, "b/file two.txt", objects[2].Key)
+	assert.Equal(t, modified, objects[2].LastModified)
+}
+
+func TestReadObjectsParquet(t *testing.T) {
+	dir := t.TempDir()
+	modified := time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC)
+
+	writer, err := NewWriter(dir, "data", Options{Format: FormatParquet, ChunkSize: 1}, time.Now())
+	require.NoError(t, err)
+	records := []Record{
+		{Bucket: "data", Key: "b/file two.txt", VersionID: "b1", IsLatest: true, Size: 7, LastModified: modified, ETag: "e2"},
+		{Bucket: "data", Key: "a.txt", VersionID: "a2", IsLatest: true, IsDeleteMarker: true, LastModified: modified},
+		{Bucket: "data", Key: "a.txt", VersionID: "a1", Size: 3, LastModified: modified, ETag: "e1", StorageClass: "STANDARD"},
+	}
+	for _, record := range records {
+		require.NoError(t, writer.Write(record))
+	}
+	manifest, err := writer.Close()
+	require.NoError(t, err)
+	require.Len(t, manifest.Files, 3)
+
+	objects, _, err := ReadObjects(filepath.Join(dir, "manifest.json"))
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+
+	assert.Equal(t, "a.txt", objects[0].Key)
+	assert.True(t, objects[0].IsDeleteMarker)
+	assert.Equal(t, int64(0), objects[0].Size)
+	assert.Equal(t, "a1", objects[1].VersionID)
+	assert.False(t, objects[1].IsLatest)
+	assert.Equal(t, int64(3), objects[1].Size)
+	assert.Equal(t, "STANDARD", objects[1].StorageClass)
+	assert.Equal(t, "b/file two.txt", objects[2].Key)
+	assert.Equal(t, "e2", objects[2].ETag)
+	assert.Equal(t, modified, objects[2].LastModified)
+}
+
+func TestParseParquetRowCurrentOnly(t *testing.T) {
+	obj, err := parseParquetRow(map[string]any{"bucket": "data", "key": "a.txt", "size": int64(3)})
+	require.NoError(t, err)
+	assert.True(t, obj.IsLatest, "inventories without version columns list current objects")
+
+	_, err = parseParquetRow(map[string]any{"size": "big"})
+	assert.Error(t, err)
+}
+
+func TestReadObjectsS3Layout(t *testing.T) {
+	// destination/source-bucket/config-id/{data/,2024-03-01T00-00Z/manifest.json}
+	root := t.TempDir()
+	configDir := filepath.Join(root, "source-bucket", "config-id")
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "data"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "2024-03-01T00-00Z"), 0o755))
+
+	data := "\"source-bucket\",\"logs%2Fapp.log\",\"100\",\"2024-02-29T23:00:00.000Z\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "data", "abc.csv"), []byte(data), 0o644))
+
+	manifest := Manifest{
+		SourceBucket: "source-bucket",
+		FileFormat:   "CSV",
+		FileSchema:   "Bucket, Key, Size, LastModifiedDate",
+		Files:        []ManifestFile{{Key: "source-bucket/config-id/data/abc.csv"}},
+	}
+	encoded, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	manifestPath := filepath.Join(configDir, "2024-03-01T00-00Z", "manifest.json")
+	require.NoError(t, os.WriteFile(manifestPath, encoded, 0o644))
+
+	objects, _, err := ReadObjects(manifestPath)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "logs/app.log", objects[0].Key)
+	assert.Equal(t, int64(100), objects[0].Size)
+	assert.True(t, objects[0].IsLatest, "unversioned inventories list current objects")
+}
+
+func TestReadObjectsUnsupportedFormat(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "manifest.json")
+	encoded, err := json.Marshal(Manifest{FileFormat: FormatORC, FileSchema: "struct<bucket:string,key:string>"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestPath, encoded, 0o644))
+
+	_, _, err = ReadObjects(manifestPath)
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestParseRowInvalid(t *testing.T) {
+	_, err := parseRow([]string{FieldKey, FieldSize}, []string{"a.txt", "big"})
+	assert.Error(t, err)
+}
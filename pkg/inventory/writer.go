@@ -0,0 +1,283 @@
+package inventory
+
+import (
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultChunkSize is the default uncompressed size of a data file
+const DefaultChunkSize = 256 << 20
+
+// Writer writes records as gzip-compressed CSV or Parquet data files plus a manifest
+type Writer struct {
+	dir     string
+	bucket  string
+	fields  []string
+	opts    Options
+	created time.Time
+
+	file    *os.File
+	md5     hash.Hash
+	encoder chunkEncoder
+	name    string
+
+	files   []ManifestFile
+	records int
+}
+
+// chunkEncoder encodes the records of one data file
+type chunkEncoder interface {
+	Write(record Record) error
+	// Size is the uncompressed size of the records written so far
+	Size() int64
+	// Close finishes the data file without closing the underlying writer
+	Close() error
+}
+
+// csvEncoder writes gzip-compressed CSV rows
+type csvEncoder struct {
+	fields  []string
+	gzip    *gzip.Writer
+	csv     *csv.Writer
+	counter *countingWriter
+}
+
+func newCSVEncoder(w io.Writer, fields []string) *csvEncoder {
+	gz := gzip.NewWriter(w)
+	counter := &countingWriter{w: gz}
+	return &csvEncoder{fields: fields, gzip: gz, csv: csv.NewWriter(counter), counter: counter}
+}
+
+func (e *csvEncoder) Write(record Record) error {
+	if err := e.csv.Write(csvRow(e.fields, record)); err != nil {
+		return err
+	}
+	// csv buffers, so flush before the size is measured
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+func (e *csvEncoder) Size() int64 {
+	return e.counter.n
+}
+
+func (e *csvEncoder) Close() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+	return e.gzip.Close()
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// NewWriter creates a writer for an inventory of bucket in dir
+func NewWriter(dir, bucket string, opts Options, created time.Time) (*Writer, error) {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	if opts.Format == "" {
+		opts.Format = FormatCSV
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create inventory directory: %w", err)
+	}
+
+	return &Writer{
+		dir:     dir,
+		bucket:  bucket,
+		fields:  opts.Fields(),
+		opts:    opts,
+		created: created.UTC(),
+	}, nil
+}
+
+// Records returns the number of records written
+func (w *Writer) Records() int {
+	return w.records
+}
+
+// Write appends a record, starting a new data file when the current one is full
+func (w *Writer) Write(record Record) error {
+	if w.encoder == nil {
+		if err := w.openChunk(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.encoder.Write(record); err != nil {
+		return fmt.Errorf("failed to write inventory record: %w", err)
+	}
+	w.records++
+
+	if w.encoder.Size() >= w.opts.ChunkSize {
+		return w.closeChunk()
+	}
+
+	return nil
+}
+
+// Close finishes the last data file and writes manifest.json and manifest.checksum
+func (w *Writer) Close() (*Manifest, error) {
+	if w.encoder != nil {
+		if err := w.closeChunk(); err != nil {
+			return nil, err
+		}
+	}
+
+	// S3 lists CSV fields by name and describes Parquet files with their message schema
+	schema := strings.Join(w.fields, ", ")
+	if w.opts.Format == FormatParquet {
+		schema = parquetSchema(w.fields).String()
+	}
+
+	manifest := &Manifest{
+		SourceBucket:      w.bucket,
+		Version:           manifestVersion,
+		CreationTimestamp: strconv.FormatInt(w.created.UnixMilli(), 10),
+		FileFormat:        w.opts.Format,
+		FileSchema:        schema,
+		Files:             w.files,
+	}
+	if manifest.Files == nil {
+		manifest.Files = []ManifestFile{}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(w.dir, "manifest.json"), data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	sum := md5.Sum(data)
+	if err := os.WriteFile(filepath.Join(w.dir, "manifest.checksum"), []byte(hex.EncodeToString(sum[:])), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest checksum: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// Abort closes the open data file and removes the data files written so far, so a failed
+// export leaves no inventory without a manifest behind
+func (w *Writer) Abort() {
+	if w.encoder != nil {
+		w.file.Close()
+		os.Remove(filepath.Join(w.dir, w.name))
+		w.encoder = nil
+	}
+	for _, file := range w.files {
+		os.Remove(filepath.Join(w.dir, file.Key))
+	}
+	w.files = nil
+}
+
+func (w *Writer) openChunk() error {
+	extension := "csv.gz"
+	if w.opts.Format == FormatParquet {
+		extension = "parquet"
+	}
+	w.name = fmt.Sprintf("data/%s-%05d.%s", w.bucket, len(w.files)+1, extension)
+
+	f, err := os.Create(filepath.Join(w.dir, w.name))
+	if err != nil {
+		return fmt.Errorf("failed to create inventory file: %w", err)
+	}
+
+	w.file = f
+	w.md5 = md5.New()
+	out := io.MultiWriter(f, w.md5)
+	if w.opts.Format == FormatParquet {
+		w.encoder = newParquetEncoder(out, w.fields)
+	} else {
+		w.encoder = newCSVEncoder(out, w.fields)
+	}
+	return nil
+}
+
+func (w *Writer) closeChunk() error {
+	if err := w.encoder.Close(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to write inventory file: %w", err)
+	}
+
+	info, err := w.file.Stat()
+	if err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to stat inventory file: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close inventory file: %w", err)
+	}
+
+	w.files = append(w.files, ManifestFile{
+		Key:         w.name,
+		Size:        info.Size(),
+		MD5Checksum: hex.EncodeToString(w.md5.Sum(nil)),
+	})
+	w.encoder = nil
+	return nil
+}
+
+// csvRow formats a record in field order. Keys are URL-encoded as in S3 Inventory CSV files.
+func csvRow(fields []string, record Record) []string {
+	row := make([]string, 0, len(fields))
+	for _, field := range fields {
+		var value string
+		switch field {
+		case FieldBucket:
+			value = record.Bucket
+		case FieldKey:
+			value = url.QueryEscape(record.Key)
+		case FieldVersionID:
+			value = record.VersionID
+		case FieldIsLatest:
+			value = strconv.FormatBool(record.IsLatest)
+		case FieldIsDeleteMarker:
+			value = strconv.FormatBool(record.IsDeleteMarker)
+		case FieldSize:
+			if !record.IsDeleteMarker {
+				value = strconv.FormatInt(record.Size, 10)
+			}
+		case FieldLastModifiedDate:
+			value = record.LastModified.UTC().Format(lastModifiedLayout)
+		case FieldETag:
+			value = record.ETag
+		case FieldStorageClass:
+			value = record.StorageClass
+		case FieldEncryptionStatus:
+			value = record.EncryptionStatus
+		case FieldReplicationStatus:
+			value = record.ReplicationStatus
+		case FieldTags:
+			value = record.Tags
+		}
+		row = append(row, value)
+	}
+	return row
+}
@@ -0,0 +1,189 @@
+package inventory
+
+import (
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readRows(t *testing.T, path string) [][]string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(gz).ReadAll()
+	require.NoError(t, err)
+	return rows
+}
+
+func TestWriterSingleChunk(t *testing.T) {
+	dir := t.TempDir()
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	modified := time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC)
+
+	writer, err := NewWriter(dir, "data", Options{Tags: true}, created)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write(Record{
+		Bucket: "data", Key: "reports/q1 final.csv", VersionID: "v2", IsLatest: true,
+		Size: 42, LastModified: modified, ETag: "abc", StorageClass: "STANDARD", Tags: "team=finance",
+	}))
+	require.NoError(t, writer.Write(Record{
+		Bucket: "data", Key: "old.txt", VersionID: "v1", IsLatest: true, IsDeleteMarker: true, LastModified: modified,
+	}))
+
+	manifest, err := writer.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, "data", manifest.SourceBucket)
+	assert.Equal(t, FormatCSV, manifest.FileFormat)
+	assert.Equal(t, "1709251200000", manifest.CreationTimestamp)
+	assert.Equal(t, Options{Tags: true}.Fields(), manifest.Fields())
+	require.Len(t, manifest.Files, 1)
+
+	file := manifest.Files[0]
+	data, err := os.ReadFile(filepath.Join(dir, file.Key))
+	require.NoError(t, err)
+	sum := md5.Sum(data)
+	assert.Equal(t, hex.EncodeToString(sum[:]), file.MD5Checksum)
+	assert.Equal(t, int64(len(data)), file.Size)
+
+	rows := readRows(t, filepath.Join(dir, file.Key))
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"data", "reports%2Fq1+final.csv", "v2", "true", "false", "42",
+		"2024-02-01T10:30:00.000Z", "abc", "STANDARD", "team=finance"}, rows[0])
+	assert.Equal(t, "", rows[1][5], "delete markers have no size")
+
+	manifestData, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	require.NoError(t, err)
+	var decoded Manifest
+	require.NoError(t, json.Unmarshal(manifestData, &decoded))
+	assert.Equal(t, *manifest, decoded)
+
+	checksum, err := os.ReadFile(filepath.Join(dir, "manifest.checksum"))
+	require.NoError(t, err)
+	manifestSum := md5.Sum(manifestData)
+	assert.Equal(t, hex.EncodeToString(manifestSum[:]), string(checksum))
+}
+
+func TestWriterParquet(t *testing.T) {
+	dir := t.TempDir()
+	modified := time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC)
+
+	writer, err := NewWriter(dir, "data", Options{Format: FormatParquet, Tags: true}, time.Now())
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write(Record{
+		Bucket: "data", Key: "reports/q1 final.csv", VersionID: "v2", IsLatest: true,
+		Size: 42, LastModified: modified, ETag: "abc", StorageClass: "STANDARD", Tags: "team=finance",
+	}))
+	require.NoError(t, writer.Write(Record{
+		Bucket: "data", Key: "old.txt", VersionID: "v1", IsLatest: true, IsDeleteMarker: true, LastModified: modified,
+	}))
+
+	manifest, err := writer.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatParquet, manifest.FileFormat)
+	assert.Contains(t, manifest.FileSchema, "last_modified_date")
+	require.Len(t, manifest.Files, 1)
+	assert.Equal(t, "data/data-00001.parquet", manifest.Files[0].Key)
+
+	f, err := os.Open(filepath.Join(dir, manifest.Files[0].Key))
+	require.NoError(t, err)
+	defer f.Close()
+	stat, err := f.Stat()
+	require.NoError(t, err)
+	file, err := parquet.OpenFile(f, stat.Size())
+	require.NoError(t, err)
+
+	reader := parquet.NewReader(file)
+	var rows []map[string]any
+	for {
+		row := map[string]any{}
+		if err := reader.Read(&row); err == io.EOF {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+		rows = append(rows, row)
+	}
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, "reports/q1 final.csv", rows[0]["key"], "keys are not URL-encoded")
+	assert.Equal(t, int64(42), rows[0]["size"])
+	assert.Equal(t, modified.UnixMilli(), rows[0]["last_modified_date"])
+	assert.Equal(t, "team=finance", rows[0]["tags"])
+	assert.Nil(t, rows[1]["size"], "delete markers have no size")
+}
+
+func TestWriterChunking(t *testing.T) {
+	dir := t.TempDir()
+
+	writer, err := NewWriter(dir, "data", Options{ChunkSize: 100}, time.Now())
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, writer.Write(Record{Bucket: "data", Key: "some/longer/key/name.bin", Size: 1}))
+	}
+
+	manifest, err := writer.Close()
+	require.NoError(t, err)
+	assert.Equal(t, 10, writer.Records())
+	require.Greater(t, len(manifest.Files), 1)
+
+	total := 0
+	for _, file := range manifest.Files {
+		total += len(readRows(t, filepath.Join(dir, file.Key)))
+	}
+	assert.Equal(t, 10, total)
+}
+
+func TestWriterEmpty(t *testing.T) {
+	dir := t.TempDir()
+
+	writer, err := NewWriter(dir, "empty", Options{}, time.Now())
+	require.NoError(t, err)
+
+	manifest, err := writer.Close()
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Files)
+}
+
+func TestWriterAbort(t *testing.T) {
+	dir := t.TempDir()
+
+	writer, err := NewWriter(dir, "data", Options{Format: FormatParquet, ChunkSize: 30}, time.Now())
+	require.NoError(t, err)
+
+	// The first data file is full after two records, the second is still open
+	require.NoError(t, writer.Write(Record{Bucket: "data", Key: "a", LastModified: time.Now()}))
+	require.NoError(t, writer.Write(Record{Bucket: "data", Key: "reports/b", LastModified: time.Now()}))
+	require.NoError(t, writer.Write(Record{Bucket: "data", Key: "c", LastModified: time.Now()}))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "data"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	writer.Abort()
+
+	entries, err = os.ReadDir(filepath.Join(dir, "data"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	_, err = os.Stat(filepath.Join(dir, "manifest.json"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,445 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"gopkg.in/yaml.v3"
+)
+
+// Baseline describes the settings required of buckets, per bucket name pattern
+type Baseline struct {
+	Name  string         `json:"name" yaml:"name"`
+	Rules []BaselineRule `json:"rules" yaml:"rules"`
+}
+
+// BaselineRule lists the requirements for the buckets matching any of its patterns
+type BaselineRule struct {
+	Name       string                 `json:"name" yaml:"name"`
+	Buckets    []string               `json:"buckets" yaml:"buckets"`
+	Severity   Severity               `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Versioning bool                   `json:"versioning,omitempty" yaml:"versioning,omitempty"`
+	Encryption *EncryptionRequirement `json:"encryption,omitempty" yaml:"encryption,omitempty"`
+	Lifecycle  *LifecycleRequirement  `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	Policy     *PolicyRequirement     `json:"policy,omitempty" yaml:"policy,omitempty"`
+	ObjectLock *ObjectLockRequirement `json:"object_lock,omitempty" yaml:"object_lock,omitempty"`
+}
+
+// EncryptionRequirement requires default encryption, optionally with a given algorithm and KMS key
+type EncryptionRequirement struct {
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	KMSKeyID  string `json:"kms_key_id,omitempty" yaml:"kms_key_id,omitempty"`
+}
+
+// LifecycleRequirement requires whole-bucket lifecycle rules within the given day limits
+type LifecycleRequirement struct {
+	MaxNoncurrentExpirationDays  int `json:"max_noncurrent_expiration_days,omitempty" yaml:"max_noncurrent_expiration_days,omitempty"`
+	MaxAbortIncompleteUploadDays int `json:"max_abort_incomplete_upload_days,omitempty" yaml:"max_abort_incomplete_upload_days,omitempty"`
+}
+
+// PolicyRequirement constrains the bucket policy; public: false forbids anonymous access
+type PolicyRequirement struct {
+	Public *bool `json:"public,omitempty" yaml:"public,omitempty"`
+}
+
+// ObjectLockRequirement requires object lock to be enabled (Required) and/or a default
+// retention mode and minimum period; the minimum also applies to objects checked by the
+// retention scan
+type ObjectLockRequirement struct {
+	Required         bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	Mode             string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	MinRetentionDays int    `json:"min_retention_days,omitempty" yaml:"min_retention_days,omitempty"`
+}
+
+// LoadBaseline reads a baseline from a YAML or JSON file (chosen by extension)
+func LoadBaseline(filename string) (*Baseline, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file: %w", err)
+	}
+
+	var baseline Baseline
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&baseline)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&baseline)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse baseline file: %w", err)
+	}
+
+	if err := baseline.Validate(); err != nil {
+		return nil, err
+	}
+	return &baseline, nil
+}
+
+// Validate checks the baseline for mistakes and fills in defaults
+func (b *Baseline) Validate() error {
+	if len(b.Rules) == 0 {
+		return fmt.Errorf("baseline has no rules")
+	}
+
+	for i := range b.Rules {
+		rule := &b.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if len(rule.Buckets) == 0 {
+			return fmt.Errorf("baseline rule '%s' has no bucket patterns", rule.Name)
+		}
+		for _, pattern := range rule.Buckets {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("baseline rule '%s' has an invalid bucket pattern %q: %w", rule.Name, pattern, err)
+			}
+		}
+
+		switch rule.Severity {
+		case "":
+			rule.Severity = SeverityHigh
+		case SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh:
+		default:
+			return fmt.Errorf("baseline rule '%s' has an invalid severity: %s", rule.Name, rule.Severity)
+		}
+
+		if rule.Encryption != nil && rule.Encryption.Algorithm != "" {
+			algorithm, err := normalizeSSEAlgorithm(rule.Encryption.Algorithm)
+			if err != nil {
+				return fmt.Errorf("baseline rule '%s': %w", rule.Name, err)
+			}
+			rule.Encryption.Algorithm = algorithm
+		}
+		if rule.Encryption != nil && rule.Encryption.KMSKeyID != "" && rule.Encryption.Algorithm == "" {
+			rule.Encryption.Algorithm = "aws:kms"
+		}
+
+		if rule.ObjectLock != nil {
+			rule.ObjectLock.Mode = strings.ToUpper(rule.ObjectLock.Mode)
+			switch rule.ObjectLock.Mode {
+			case "", RetentionGovernance, RetentionCompliance:
+			default:
+				return fmt.Errorf("baseline rule '%s' has an invalid retention mode: %s (expected GOVERNANCE or COMPLIANCE)",
+					rule.Name, rule.ObjectLock.Mode)
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizeSSEAlgorithm maps sse-s3/sse-kms aliases to the S3 algorithm names
+func normalizeSSEAlgorithm(algorithm string) (string, error) {
+	switch strings.ToLower(algorithm) {
+	case "sse-s3", "aes256":
+		return "AES256", nil
+	case "sse-kms", "aws:kms", "kms":
+		return "aws:kms", nil
+	default:
+		return "", fmt.Errorf("invalid encryption algorithm: %s (expected sse-s3 or sse-kms)", algorithm)
+	}
+}
+
+// Matches reports whether the rule applies to a bucket
+func (r BaselineRule) Matches(bucket string) bool {
+	for _, pattern := range r.Buckets {
+		if matched, _ := path.Match(pattern, bucket); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchingRules returns the rules that apply to a bucket
+func (b *Baseline) MatchingRules(bucket string) []BaselineRule {
+	var rules []BaselineRule
+	for _, rule := range b.Rules {
+		if rule.Matches(bucket) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// MinRetention returns the strictest minimum retention of the rules matching a bucket
+func (b *Baseline) MinRetention(bucket string) time.Duration {
+	days := 0
+	for _, rule := range b.MatchingRules(bucket) {
+		if rule.ObjectLock != nil && rule.ObjectLock.MinRetentionDays > days {
+			days = rule.ObjectLock.MinRetentionDays
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Evaluate checks a bucket configuration against every matching baseline rule
+func (b *Baseline) Evaluate(cfg *BucketConfig) []CheckResult {
+	rules := b.MatchingRules(cfg.Bucket)
+	if len(rules) == 0 {
+		return []CheckResult{{
+			ID:       CheckBaselineMatched,
+			Category: CategoryBaseline,
+			Status:   StatusSkip,
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("No baseline rule matches bucket %s", cfg.Bucket),
+		}}
+	}
+
+	var results []CheckResult
+	for _, rule := range rules {
+		results = append(results, rule.evaluate(cfg)...)
+	}
+	return results
+}
+
+// evaluate checks a bucket configuration against the requirements of one rule
+func (r BaselineRule) evaluate(cfg *BucketConfig) []CheckResult {
+	var results []CheckResult
+
+	result := func(id, category string, passed bool, message, remediation string, evidence ...string) CheckResult {
+		checkResult := CheckResult{
+			ID:       id,
+			Category: category,
+			Status:   StatusPass,
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("[%s] %s", r.Name, message),
+			Evidence: evidence,
+		}
+		if !passed {
+			checkResult.Status = StatusFail
+			checkResult.Severity = r.Severity
+			checkResult.Remediation = remediation
+		}
+		return checkResult
+	}
+
+	if r.Versioning {
+		status := cfg.Versioning.Status
+		if cfg.VersioningErr != nil {
+			status = fmt.Sprintf("unknown (%v)", cfg.VersioningErr)
+		} else if status == "" {
+			status = "Unversioned"
+		}
+		results = append(results, result(CheckBaselineVersioning, CategoryVersioning,
+			cfg.VersioningErr == nil && cfg.Versioning.Status == "Enabled",
+			fmt.Sprintf("Versioning required: %s", status),
+			fmt.Sprintf("mc version enable <alias>/%s", cfg.Bucket)))
+	}
+
+	if r.Encryption != nil {
+		results = append(results, r.evaluateEncryption(cfg, result)...)
+	}
+
+	if r.Lifecycle != nil {
+		results = append(results, r.evaluateLifecycle(cfg, result)...)
+	}
+
+	if r.Policy != nil && r.Policy.Public != nil && !*r.Policy.Public {
+		results = append(results, r.evaluatePublicPolicy(cfg, result))
+	}
+
+	if r.ObjectLock != nil {
+		results = append(results, r.evaluateObjectLock(cfg, result)...)
+	}
+
+	return results
+}
+
+type resultBuilder func(id, category string, passed bool, message, remediation string, evidence ...string) CheckResult
+
+func (r BaselineRule) evaluateEncryption(cfg *BucketConfig, result resultBuilder) []CheckResult {
+	required := r.Encryption
+	wanted := required.Algorithm
+	if wanted == "" {
+		wanted = "any algorithm"
+	}
+
+	if cfg.Encryption == nil || len(cfg.Encryption.Rules) == 0 {
+		return []CheckResult{result(CheckBaselineEncryption, CategoryEncryption, false,
+			fmt.Sprintf("Default encryption required (%s): not configured", wanted),
+			encryptionRemediation(cfg.Bucket, required))}
+	}
+
+	apply := cfg.Encryption.Rules[0].Apply
+	results := []CheckResult{result(CheckBaselineEncryption, CategoryEncryption,
+		required.Algorithm == "" || apply.SSEAlgorithm == required.Algorithm,
+		fmt.Sprintf("Default encryption required (%s): %s configured", wanted, apply.SSEAlgorithm),
+		encryptionRemediation(cfg.Bucket, required))}
+
+	if required.KMSKeyID != "" {
+		actual := apply.KmsMasterKeyID
+		if actual == "" {
+			actual = "none"
+		}
+		results = append(results, result(CheckBaselineKMSKey, CategoryEncryption,
+			kmsKeyMatches(apply.KmsMasterKeyID, required.KMSKeyID),
+			fmt.Sprintf("KMS key %s required: %s configured", required.KMSKeyID, actual),
+			encryptionRemediation(cfg.Bucket, required)))
+	}
+
+	return results
+}
+
+// kmsKeyMatches compares key IDs, accepting ARNs that end with the required key
+func kmsKeyMatches(actual, required string) bool {
+	return actual == required || strings.HasSuffix(actual, ":"+required) || strings.HasSuffix(actual, "/"+required)
+}
+
+func encryptionRemediation(bucket string, required *EncryptionRequirement) string {
+	if required.Algorithm == "aws:kms" {
+		key := required.KMSKeyID
+		if key == "" {
+			key = "<key-id>"
+		}
+		return fmt.Sprintf("mc encrypt set sse-kms %s <alias>/%s", key, bucket)
+	}
+	return fmt.Sprintf("mc encrypt set sse-s3 <alias>/%s", bucket)
+}
+
+// wholeBucketRules returns the enabled lifecycle rules without a prefix or tag filter
+func wholeBucketRules(cfg *lifecycle.Configuration) []lifecycle.Rule {
+	if cfg == nil {
+		return nil
+	}
+	var rules []lifecycle.Rule
+	for _, rule := range cfg.Rules {
+		if rule.Status == "Enabled" && rule.Prefix == "" && rule.RuleFilter.IsNull() {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func (r BaselineRule) evaluateLifecycle(cfg *BucketConfig, result resultBuilder) []CheckResult {
+	var results []CheckResult
+	rules := wholeBucketRules(cfg.Lifecycle)
+
+	if limit := r.Lifecycle.MaxNoncurrentExpirationDays; limit > 0 {
+		best := 0
+		var evidence []string
+		for _, rule := range rules {
+			if days := int(rule.NoncurrentVersionExpiration.NoncurrentDays); days > 0 {
+				evidence = append(evidence, fmt.Sprintf("Rule '%s' expires noncurrent versions after %d days", rule.ID, days))
+				if best == 0 || days < best {
+					best = days
+				}
+			}
+		}
+		message := fmt.Sprintf("Noncurrent expiration within %d days required: ", limit)
+		if best == 0 {
+			message += "no whole-bucket rule expires noncurrent versions"
+		} else {
+			message += fmt.Sprintf("%d days configured", best)
+		}
+		results = append(results, result(CheckBaselineNoncurrentExpiration, CategoryLifecycle,
+			best > 0 && best <= limit, message,
+			fmt.Sprintf("Add an enabled rule without a filter with NoncurrentVersionExpiration NoncurrentDays <= %d", limit),
+			evidence...))
+	}
+
+	if limit := r.Lifecycle.MaxAbortIncompleteUploadDays; limit > 0 {
+		best := 0
+		var evidence []string
+		for _, rule := range rules {
+			if days := int(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation); days > 0 {
+				evidence = append(evidence, fmt.Sprintf("Rule '%s' aborts incomplete uploads after %d days", rule.ID, days))
+				if best == 0 || days < best {
+					best = days
+				}
+			}
+		}
+		message := fmt.Sprintf("Incomplete uploads aborted within %d days required: ", limit)
+		if best == 0 {
+			message += "no whole-bucket rule aborts incomplete uploads"
+		} else {
+			message += fmt.Sprintf("%d days configured", best)
+		}
+		results = append(results, result(CheckBaselineAbortUploads, CategoryLifecycle,
+			best > 0 && best <= limit, message,
+			fmt.Sprintf("Add an enabled rule without a filter with AbortIncompleteMultipartUpload DaysAfterInitiation <= %d", limit),
+			evidence...))
+	}
+
+	return results
+}
+
+func (r BaselineRule) evaluatePublicPolicy(cfg *BucketConfig, result resultBuilder) CheckResult {
+	remediation := fmt.Sprintf("Remove anonymous statements: mc anonymous set none <alias>/%s", cfg.Bucket)
+	if cfg.Policy == "" {
+		return result(CheckBaselinePublicPolicy, CategoryPolicy, true, "Public access forbidden: no policy configured", remediation)
+	}
+
+	document, err := ParsePolicy(cfg.Policy)
+	if err != nil {
+		return result(CheckBaselinePublicPolicy, CategoryPolicy, false,
+			fmt.Sprintf("Public access forbidden: %v", err), remediation)
+	}
+
+	public := document.PublicStatements()
+	var evidence []string
+	for _, statement := range public {
+		evidence = append(evidence, statement.describe())
+	}
+	message := "Public access forbidden: no anonymous statements"
+	if len(public) > 0 {
+		message = fmt.Sprintf("Public access forbidden: %d anonymous statements", len(public))
+	}
+	return result(CheckBaselinePublicPolicy, CategoryPolicy, len(public) == 0, message, remediation, evidence...)
+}
+
+func (r BaselineRule) evaluateObjectLock(cfg *BucketConfig, result resultBuilder) []CheckResult {
+	required := r.ObjectLock
+	lock := cfg.ObjectLock
+	status := "disabled"
+	if cfg.ObjectLockErr != nil {
+		status = fmt.Sprintf("unknown (%v)", cfg.ObjectLockErr)
+	} else if lock.Enabled {
+		status = "enabled"
+	}
+
+	var results []CheckResult
+	if required.Required {
+		results = append(results, result(CheckBaselineObjectLock, CategoryObjectLock,
+			cfg.ObjectLockErr == nil && lock.Enabled,
+			fmt.Sprintf("Object lock required: %s", status),
+			"Object lock can only be enabled when a bucket is created: mc mb --with-lock <alias>/<bucket>, then copy the data"))
+	}
+
+	defaultRetention := "no default retention"
+	switch {
+	case cfg.ObjectLockErr != nil || !lock.Enabled:
+		defaultRetention = fmt.Sprintf("object lock %s", status)
+	case lock.Mode != "":
+		defaultRetention = fmt.Sprintf("%s for %d days", lock.Mode, lock.Days)
+	}
+
+	if required.Mode != "" {
+		results = append(results, result(CheckBaselineRetentionMode, CategoryObjectLock,
+			modeSatisfies(lock.Mode, required.Mode),
+			fmt.Sprintf("Default retention mode %s required: %s", required.Mode, defaultRetention),
+			fmt.Sprintf("mc retention set --default %s %dd <alias>/%s", required.Mode, max(required.MinRetentionDays, 1), cfg.Bucket)))
+	}
+
+	if required.MinRetentionDays > 0 {
+		mode := required.Mode
+		if mode == "" {
+			mode = RetentionGovernance
+		}
+		results = append(results, result(CheckBaselineRetentionPeriod, CategoryObjectLock,
+			lock.Mode != "" && lock.Days >= required.MinRetentionDays,
+			fmt.Sprintf("Default retention of at least %d days required: %s", required.MinRetentionDays, defaultRetention),
+			fmt.Sprintf("mc retention set --default %s %dd <alias>/%s", mode, required.MinRetentionDays, cfg.Bucket)))
+	}
+
+	return results
+}
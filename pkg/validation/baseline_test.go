@@ -0,0 +1,227 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBaselineYAML = `name: production
+rules:
+  - name: prod
+    buckets: ["prod-*"]
+    versioning: true
+    encryption:
+      algorithm: sse-kms
+      kms_key_id: prod-key
+    lifecycle:
+      max_noncurrent_expiration_days: 90
+      max_abort_incomplete_upload_days: 7
+    policy:
+      public: false
+  - buckets: ["dev-*"]
+    severity: low
+    encryption: {}
+`
+
+func writeBaseline(t *testing.T, name, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	return filename
+}
+
+func TestLoadBaseline(t *testing.T) {
+	baseline, err := LoadBaseline(writeBaseline(t, "baseline.yaml", testBaselineYAML))
+	require.NoError(t, err)
+
+	require.Len(t, baseline.Rules, 2)
+	assert.Equal(t, "production", baseline.Name)
+	assert.Equal(t, SeverityHigh, baseline.Rules[0].Severity)
+	assert.Equal(t, "aws:kms", baseline.Rules[0].Encryption.Algorithm)
+	assert.Equal(t, "rule 2", baseline.Rules[1].Name)
+	assert.Equal(t, SeverityLow, baseline.Rules[1].Severity)
+
+	jsonBaseline, err := LoadBaseline(writeBaseline(t, "baseline.json",
+		`{"rules": [{"name": "all", "buckets": ["*"], "versioning": true}]}`))
+	require.NoError(t, err)
+	assert.True(t, jsonBaseline.Rules[0].Versioning)
+}
+
+func TestLoadBaselineErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		errMsg  string
+	}{
+		{"no rules", "b.yaml", "name: empty\n", "has no rules"},
+		{"no patterns", "b.yaml", "rules:\n  - name: x\n    versioning: true\n", "no bucket patterns"},
+		{"bad pattern", "b.yaml", "rules:\n  - buckets: [\"prod-[\"]\n", "invalid bucket pattern"},
+		{"bad severity", "b.yaml", "rules:\n  - buckets: [\"*\"]\n    severity: urgent\n", "invalid severity"},
+		{"bad algorithm", "b.yaml", "rules:\n  - buckets: [\"*\"]\n    encryption:\n      algorithm: rot13\n", "invalid encryption algorithm"},
+		{"unknown yaml key", "b.yaml", "rules:\n  - buckets: [\"*\"]\n    versionning: true\n", "failed to parse"},
+		{"unknown json key", "b.json", `{"rules": [{"buckets": ["*"], "versionning": true}]}`, "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadBaseline(writeBaseline(t, tt.file, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func loadTestBaseline(t *testing.T) *Baseline {
+	t.Helper()
+	baseline, err := LoadBaseline(writeBaseline(t, "baseline.yaml", testBaselineYAML))
+	require.NoError(t, err)
+	return baseline
+}
+
+func resultsByID(results []CheckResult) map[string]CheckResult {
+	byID := make(map[string]CheckResult)
+	for _, result := range results {
+		byID[result.ID] = result
+	}
+	return byID
+}
+
+func TestBaselineEvaluateCompliant(t *testing.T) {
+	cfg := &BucketConfig{
+		Bucket:     "prod-data",
+		Versioning: minio.BucketVersioningConfiguration{Status: "Enabled"},
+		Encryption: sse.NewConfigurationSSEKMS("arn:aws:kms:prod-key"),
+		Lifecycle: &lifecycle.Configuration{Rules: []lifecycle.Rule{
+			{ID: "scoped", Status: "Enabled", RuleFilter: lifecycle.Filter{Prefix: "logs/"},
+				NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 1}},
+			{ID: "cleanup", Status: "Enabled",
+				NoncurrentVersionExpiration:    lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 30},
+				AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{DaysAfterInitiation: 3}},
+		}},
+		Policy: `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": ["arn:aws:iam::1:user/app"]}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::prod-data/*"}]}`,
+	}
+
+	results := loadTestBaseline(t).Evaluate(cfg)
+	require.Len(t, results, 6)
+	for _, result := range results {
+		assert.Equal(t, StatusPass, result.Status, result.Message)
+	}
+
+	byID := resultsByID(results)
+	assert.Equal(t, "[prod] Noncurrent expiration within 90 days required: 30 days configured",
+		byID[CheckBaselineNoncurrentExpiration].Message)
+}
+
+func TestBaselineEvaluateViolations(t *testing.T) {
+	cfg := &BucketConfig{
+		Bucket:     "prod-data",
+		Versioning: minio.BucketVersioningConfiguration{Status: "Suspended"},
+		Encryption: sse.NewConfigurationSSES3(),
+		Lifecycle: &lifecycle.Configuration{Rules: []lifecycle.Rule{
+			{ID: "too-long", Status: "Enabled",
+				NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 365}},
+			{ID: "disabled", Status: "Disabled",
+				AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{DaysAfterInitiation: 1}},
+		}},
+		Policy: `{"Statement": [{"Sid": "Public", "Effect": "Allow", "Principal": "*", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::prod-data/*"]}]}`,
+	}
+
+	results := loadTestBaseline(t).Evaluate(cfg)
+	byID := resultsByID(results)
+	for _, id := range []string{CheckBaselineVersioning, CheckBaselineEncryption, CheckBaselineKMSKey,
+		CheckBaselineNoncurrentExpiration, CheckBaselineAbortUploads, CheckBaselinePublicPolicy} {
+		result, ok := byID[id]
+		require.True(t, ok, id)
+		assert.Equal(t, StatusFail, result.Status, id)
+		assert.Equal(t, SeverityHigh, result.Severity, id)
+		assert.NotEmpty(t, result.Remediation, id)
+	}
+
+	assert.Equal(t, "[prod] Versioning required: Suspended", byID[CheckBaselineVersioning].Message)
+	assert.Equal(t, "[prod] KMS key prod-key required: none configured", byID[CheckBaselineKMSKey].Message)
+	assert.Contains(t, byID[CheckBaselineKMSKey].Remediation, "mc encrypt set sse-kms prod-key <alias>/prod-data")
+	assert.Equal(t, []string{"Rule 'too-long' expires noncurrent versions after 365 days"},
+		byID[CheckBaselineNoncurrentExpiration].Evidence)
+	assert.Contains(t, byID[CheckBaselineAbortUploads].Message, "no whole-bucket rule aborts incomplete uploads")
+	assert.Equal(t, []string{"Statement Public: Allow [s3:GetObject] on [arn:aws:s3:::prod-data/*]"},
+		byID[CheckBaselinePublicPolicy].Evidence)
+}
+
+func TestBaselineEvaluateMatching(t *testing.T) {
+	baseline := loadTestBaseline(t)
+
+	results := baseline.Evaluate(&BucketConfig{Bucket: "scratch"})
+	require.Len(t, results, 1)
+	assert.Equal(t, CheckBaselineMatched, results[0].ID)
+	assert.Equal(t, StatusSkip, results[0].Status)
+
+	results = baseline.Evaluate(&BucketConfig{Bucket: "dev-logs"})
+	require.Len(t, results, 1)
+	assert.Equal(t, CheckBaselineEncryption, results[0].ID)
+	assert.Equal(t, StatusFail, results[0].Status)
+	assert.Equal(t, SeverityLow, results[0].Severity)
+	assert.Contains(t, results[0].Message, "[rule 2]")
+}
+
+func TestBaselineObjectLockNotRequired(t *testing.T) {
+	baseline, err := LoadBaseline(writeBaseline(t, "baseline.yaml", `rules:
+  - name: retained
+    buckets: ["*"]
+    object_lock:
+      required: false
+      mode: governance
+`))
+	require.NoError(t, err)
+
+	byID := resultsByID(baseline.Evaluate(&BucketConfig{Bucket: "scratch"}))
+	assert.NotContains(t, byID, CheckBaselineObjectLock)
+	assert.Equal(t, StatusFail, byID[CheckBaselineRetentionMode].Status)
+	assert.Equal(t, "[retained] Default retention mode GOVERNANCE required: object lock disabled", byID[CheckBaselineRetentionMode].Message)
+}
+
+func TestBaselineObjectLock(t *testing.T) {
+	baseline, err := LoadBaseline(writeBaseline(t, "baseline.yaml", `rules:
+  - name: compliance
+    buckets: ["legal-*"]
+    object_lock:
+      required: true
+      mode: governance
+      min_retention_days: 365
+  - name: archive
+    buckets: ["legal-archive"]
+    object_lock:
+      min_retention_days: 3650
+`))
+	require.NoError(t, err)
+	assert.Equal(t, RetentionGovernance, baseline.Rules[0].ObjectLock.Mode)
+	assert.Equal(t, 3650*24*time.Hour, baseline.MinRetention("legal-archive"))
+	assert.Equal(t, 365*24*time.Hour, baseline.MinRetention("legal-docs"))
+	assert.Zero(t, baseline.MinRetention("scratch"))
+
+	byID := resultsByID(baseline.Evaluate(&BucketConfig{
+		Bucket:     "legal-docs",
+		ObjectLock: ObjectLockConfig{Enabled: true, Mode: RetentionCompliance, Days: 400},
+	}))
+	assert.Equal(t, StatusPass, byID[CheckBaselineObjectLock].Status)
+	assert.Equal(t, StatusPass, byID[CheckBaselineRetentionMode].Status, "COMPLIANCE satisfies GOVERNANCE")
+	assert.Equal(t, StatusPass, byID[CheckBaselineRetentionPeriod].Status)
+
+	byID = resultsByID(baseline.Evaluate(&BucketConfig{Bucket: "legal-docs"}))
+	assert.Equal(t, StatusFail, byID[CheckBaselineObjectLock].Status)
+	assert.Equal(t, "[compliance] Object lock required: disabled", byID[CheckBaselineObjectLock].Message)
+	assert.Equal(t, StatusFail, byID[CheckBaselineRetentionMode].Status)
+	assert.Equal(t, "mc retention set --default GOVERNANCE 365d <alias>/legal-docs", byID[CheckBaselineRetentionPeriod].Remediation)
+
+	_, err = LoadBaseline(writeBaseline(t, "bad.yaml", "rules:\n  - buckets: [\"*\"]\n    object_lock:\n      mode: strict\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid retention mode")
+}
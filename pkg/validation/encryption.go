@@ -0,0 +1,171 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/sse"
+
+	"github.com/liamdn8/mc-tool/pkg/client"
+)
+
+// Object encryption types reported by encryption sampling
+const (
+	EncryptionSSES3  = "SSE-S3"
+	EncryptionSSEKMS = "SSE-KMS"
+	EncryptionSSEC   = "SSE-C"
+)
+
+// KMSKeyStatusFunc asks the server's KMS whether a key can be used
+type KMSKeyStatusFunc func(ctx context.Context, keyID string) (*client.KMSKeyStatus, error)
+
+// defaultKMSKey returns the KMS key of an SSE-KMS default encryption configuration, with
+// any ARN prefix removed; ok is false for other configurations
+func defaultKMSKey(encryption *sse.Configuration) (keyID string, ok bool) {
+	if encryption == nil || len(encryption.Rules) == 0 || encryption.Rules[0].Apply.SSEAlgorithm != "aws:kms" {
+		return "", false
+	}
+	return strings.TrimPrefix(encryption.Rules[0].Apply.KmsMasterKeyID, "arn:aws:kms:"), true
+}
+
+// evaluateKMSKey reports whether the default encryption key exists and can encrypt and
+// decrypt; err means the status could not be fetched (e.g. without admin credentials)
+func evaluateKMSKey(keyID string, status *client.KMSKeyStatus, err error) CheckResult {
+	name := keyID
+	if name == "" {
+		name = "(server default)"
+	}
+	result := CheckResult{
+		ID:       CheckEncryptionKMSKey,
+		Category: CategoryEncryption,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  fmt.Sprintf("KMS key %s exists and can encrypt and decrypt", name),
+	}
+
+	switch {
+	case err != nil:
+		result.Status = StatusSkip
+		result.Message = fmt.Sprintf("KMS key %s could not be verified: %v", name, err)
+	case status.EncryptionErr != "" || status.DecryptionErr != "":
+		result.Status = StatusFail
+		result.Severity = SeverityHigh
+		result.Message = fmt.Sprintf("KMS key %s is not usable; writes to the bucket will fail", name)
+		result.Remediation = "Create the key in the KMS (mc admin kms key create <alias> <key>) or point default encryption at an existing key"
+		if status.EncryptionErr != "" {
+			result.Evidence = append(result.Evidence, "Encryption: "+status.EncryptionErr)
+		}
+		if status.DecryptionErr != "" {
+			result.Evidence = append(result.Evidence, "Decryption: "+status.DecryptionErr)
+		}
+	}
+	return result
+}
+
+// ObjectEncryption is the encryption of one object; Type is empty for unencrypted objects
+type ObjectEncryption struct {
+	Key      string
+	Type     string
+	KMSKeyID string
+}
+
+// ScanEncryption reads how up to limit current objects are encrypted (all objects when
+// limit is not positive)
+func ScanEncryption(ctx context.Context, client *minio.Client, bucketName string, limit int) ([]ObjectEncryption, error) {
+	var objects []ObjectEncryption
+	err := statObjects(ctx, client, bucketName, limit, func(key string, info minio.ObjectInfo, err error) error {
+		if err != nil {
+			// SSE-C objects cannot be read, not even their headers, without the customer key
+			if minio.ToErrorResponse(err).StatusCode == http.StatusBadRequest {
+				objects = append(objects, ObjectEncryption{Key: key, Type: EncryptionSSEC})
+				return nil
+			}
+			return fmt.Errorf("failed to stat object %s: %w", key, err)
+		}
+		objects = append(objects, objectEncryption(info))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// objectEncryption extracts the server-side encryption headers of a stat response
+func objectEncryption(info minio.ObjectInfo) ObjectEncryption {
+	entry := ObjectEncryption{Key: info.Key}
+	switch {
+	case info.Metadata.Get(encrypt.SseCustomerAlgorithm) != "":
+		entry.Type = EncryptionSSEC
+	case info.Metadata.Get(encrypt.SseGenericHeader) == "aws:kms":
+		entry.Type = EncryptionSSEKMS
+		entry.KMSKeyID = strings.TrimPrefix(info.Metadata.Get(encrypt.SseKmsKeyID), "arn:aws:kms:")
+	case info.Metadata.Get(encrypt.SseGenericHeader) != "":
+		entry.Type = EncryptionSSES3
+	}
+	return entry
+}
+
+// evaluateEncryptionSample counts sampled objects per encryption type and reports
+// unencrypted objects, which default encryption doesn't cover when they were written
+// before it was enabled, and objects encrypted with a key other than the default key
+func evaluateEncryptionSample(objects []ObjectEncryption, encryption *sse.Configuration) CheckResult {
+	result := CheckResult{
+		ID:       CheckEncryptionObjects,
+		Category: CategoryEncryption,
+		Status:   StatusSkip,
+		Severity: SeverityInfo,
+		Message:  "No objects to sample",
+	}
+	if len(objects) == 0 {
+		return result
+	}
+
+	defaultKey, kms := defaultKMSKey(encryption)
+	counts := make(map[string]int)
+	var unencrypted, otherKey []string
+	for _, object := range objects {
+		label := object.Type
+		switch {
+		case object.Type == "":
+			label = "unencrypted"
+			unencrypted = append(unencrypted, object.Key+": not encrypted")
+		case object.Type == EncryptionSSEKMS:
+			label = fmt.Sprintf("%s (%s)", EncryptionSSEKMS, object.KMSKeyID)
+			if kms && defaultKey != "" && !kmsKeyMatches(object.KMSKeyID, defaultKey) {
+				otherKey = append(otherKey, fmt.Sprintf("%s: key %s", object.Key, object.KMSKeyID))
+			}
+		}
+		counts[label]++
+	}
+
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = fmt.Sprintf("%d %s", counts[label], label)
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Sampled %d objects: %s", len(objects), strings.Join(parts, ", "))
+	switch {
+	case len(unencrypted) > 0:
+		result.Status = StatusWarn
+		result.Severity = SeverityMedium
+		result.Remediation = "Default encryption only applies to new writes; copy or re-upload the unencrypted objects to encrypt them"
+	case len(otherKey) > 0:
+		result.Status = StatusWarn
+		result.Severity = SeverityLow
+		result.Remediation = fmt.Sprintf("Objects written with another key stay encrypted with it; re-encrypt them with %s if that key is being retired", defaultKey)
+	}
+	result.Evidence = append(firstN(unencrypted, maxExamples), firstN(otherKey, maxExamples)...)
+	return result
+}
@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/liamdn8/mc-tool/pkg/client"
+)
+
+func TestDefaultKMSKey(t *testing.T) {
+	key, ok := defaultKMSKey(sse.NewConfigurationSSEKMS("arn:aws:kms:minio-key"))
+	assert.True(t, ok)
+	assert.Equal(t, "minio-key", key)
+
+	_, ok = defaultKMSKey(sse.NewConfigurationSSES3())
+	assert.False(t, ok)
+
+	_, ok = defaultKMSKey(nil)
+	assert.False(t, ok)
+}
+
+func TestEvaluateKMSKey(t *testing.T) {
+	result := evaluateKMSKey("minio-key", &client.KMSKeyStatus{KeyID: "minio-key"}, nil)
+	assert.Equal(t, StatusPass, result.Status)
+
+	result = evaluateKMSKey("missing", &client.KMSKeyStatus{KeyID: "missing", EncryptionErr: "key does not exist"}, nil)
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, SeverityHigh, result.Severity)
+	assert.Equal(t, []string{"Encryption: key does not exist"}, result.Evidence)
+
+	result = evaluateKMSKey("", nil, errors.New("403 Forbidden"))
+	assert.Equal(t, StatusSkip, result.Status)
+	assert.Equal(t, "KMS key (server default) could not be verified: 403 Forbidden", result.Message)
+}
+
+func TestObjectEncryption(t *testing.T) {
+	info := minio.ObjectInfo{Key: "a", Metadata: http.Header{}}
+	assert.Equal(t, ObjectEncryption{Key: "a"}, objectEncryption(info))
+
+	info.Metadata.Set("X-Amz-Server-Side-Encryption", "AES256")
+	assert.Equal(t, EncryptionSSES3, objectEncryption(info).Type)
+
+	info.Metadata.Set("X-Amz-Server-Side-Encryption", "aws:kms")
+	info.Metadata.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", "arn:aws:kms:minio-key")
+	assert.Equal(t, ObjectEncryption{Key: "a", Type: EncryptionSSEKMS, KMSKeyID: "minio-key"}, objectEncryption(info))
+
+	info.Metadata = http.Header{}
+	info.Metadata.Set("X-Amz-Server-Side-Encryption-Customer-Algorithm", "AES256")
+	assert.Equal(t, EncryptionSSEC, objectEncryption(info).Type)
+}
+
+func TestEvaluateEncryptionSample(t *testing.T) {
+	result := evaluateEncryptionSample(nil, nil)
+	assert.Equal(t, StatusSkip, result.Status)
+
+	objects := []ObjectEncryption{
+		{Key: "a", Type: EncryptionSSEKMS, KMSKeyID: "minio-key"},
+		{Key: "b", Type: EncryptionSSEKMS, KMSKeyID: "minio-key"},
+		{Key: "c", Type: EncryptionSSEKMS, KMSKeyID: "old-key"},
+		{Key: "d", Type: EncryptionSSEC},
+	}
+	encryption := sse.NewConfigurationSSEKMS("minio-key")
+
+	result = evaluateEncryptionSample(objects, encryption)
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, SeverityLow, result.Severity)
+	assert.Equal(t, "Sampled 4 objects: 1 SSE-C, 2 SSE-KMS (minio-key), 1 SSE-KMS (old-key)", result.Message)
+	assert.Equal(t, []string{"c: key old-key"}, result.Evidence)
+
+	result = evaluateEncryptionSample(objects[:2], encryption)
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Empty(t, result.Evidence)
+
+	for i := 0; i < 12; i++ {
+		objects = append(objects, ObjectEncryption{Key: fmt.Sprintf("plain-%02d", i)})
+	}
+	result = evaluateEncryptionSample(objects, encryption)
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, SeverityMedium, result.Severity)
+	assert.Contains(t, result.Message, "12 unencrypted")
+	assert.Len(t, result.Evidence, maxExamples+2)
+	assert.Equal(t, "... and 2 more", result.Evidence[maxExamples])
+	assert.Equal(t, "c: key old-key", result.Evidence[maxExamples+1])
+}
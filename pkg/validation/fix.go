@@ -0,0 +1,578 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/sse"
+)
+
+// Fix kinds
+const (
+	FixEnableVersioning       = "enable-versioning"
+	FixDefaultEncryption      = "default-encryption"
+	FixAbortIncompleteUploads = "abort-incomplete-uploads"
+	FixRemovePublicStatements = "remove-public-statements"
+)
+
+// AbortRuleID is the ID of the lifecycle rule added by the abort-incomplete-uploads fix
+const AbortRuleID = "mc-tool-abort-incomplete-uploads"
+
+// DefaultAbortDays is used when no baseline sets a limit for aborting incomplete uploads
+const DefaultAbortDays = 7
+
+// fixKinds maps the checks --fix can remediate to the fix that does it
+var fixKinds = map[string]string{
+	CheckVersioningEnabled:     FixEnableVersioning,
+	CheckBaselineVersioning:    FixEnableVersioning,
+	CheckEncryptionDefault:     FixDefaultEncryption,
+	CheckBaselineEncryption:    FixDefaultEncryption,
+	CheckBaselineKMSKey:        FixDefaultEncryption,
+	CheckLifecycleAbortUploads: FixAbortIncompleteUploads,
+	CheckBaselineAbortUploads:  FixAbortIncompleteUploads,
+	CheckPolicyAnonymousAccess: FixRemovePublicStatements,
+	CheckPolicyPublicPrefixes:  FixRemovePublicStatements,
+	CheckBaselinePublicPolicy:  FixRemovePublicStatements,
+}
+
+var fixOrder = []string{FixEnableVersioning, FixDefaultEncryption, FixAbortIncompleteUploads, FixRemovePublicStatements}
+
+// Fix is a configuration change that remediates one or more checks
+type Fix struct {
+	Kind        string
+	Checks      []string
+	Description string
+	KMSKeyID    string // default-encryption: SSE-KMS key, empty for SSE-S3
+	Days        int    // abort-incomplete-uploads: days after initiation
+	AllPublic   bool   // remove-public-statements: every anonymous statement, not only write grants
+}
+
+// FixPlan lists the fixes for a report and the problems left for manual action
+type FixPlan struct {
+	Bucket string
+	Fixes  []Fix
+	Manual []CheckResult
+}
+
+// Kinds returns the kinds of the planned fixes
+func (p *FixPlan) Kinds() []string {
+	kinds := make([]string, len(p.Fixes))
+	for i, fix := range p.Fixes {
+		kinds[i] = fix.Kind
+	}
+	return kinds
+}
+
+// PlanFixes works out the fixes for the warnings and failures of a report. Requirements
+// of matching baseline rules (KMS key, abort days) are honoured; baseline may be nil.
+func PlanFixes(report *Report, baseline *Baseline) *FixPlan {
+	plan := &FixPlan{Bucket: report.Bucket}
+
+	var rules []BaselineRule
+	if baseline != nil {
+		rules = baseline.MatchingRules(report.Bucket)
+	}
+
+	if result, ok := report.Result(CheckBucketExists); ok && result.Status != StatusPass {
+		plan.Manual = append(plan.Manual, result)
+		return plan
+	}
+
+	checks := make(map[string][]string)
+	for _, result := range report.Results {
+		if result.Status != StatusWarn && result.Status != StatusFail {
+			continue
+		}
+		kind, ok := fixKinds[result.ID]
+		// Anonymous read or list access may be intended, so statements are only removed
+		// for anonymous write access or a baseline forbidding public access
+		if !ok || (kind == FixRemovePublicStatements && result.Status != StatusFail) {
+			plan.Manual = append(plan.Manual, result)
+			continue
+		}
+		checks[kind] = appendUnique(checks[kind], result.ID)
+	}
+
+	for _, kind := range fixOrder {
+		if len(checks[kind]) == 0 {
+			continue
+		}
+		fix := Fix{Kind: kind, Checks: checks[kind]}
+
+		switch kind {
+		case FixEnableVersioning:
+			fix.Description = "Enable versioning"
+		case FixDefaultEncryption:
+			algorithm, keyID := requiredEncryption(rules)
+			if algorithm == "aws:kms" && keyID == "" {
+				for _, result := range report.Results {
+					if fixKinds[result.ID] == kind && (result.Status == StatusWarn || result.Status == StatusFail) {
+						plan.Manual = append(plan.Manual, result)
+					}
+				}
+				continue
+			}
+			fix.KMSKeyID = keyID
+			fix.Description = "Set default encryption to SSE-S3"
+			if keyID != "" {
+				fix.Description = fmt.Sprintf("Set default encryption to SSE-KMS with key %s", keyID)
+			}
+		case FixAbortIncompleteUploads:
+			fix.Days = requiredAbortDays(rules)
+			fix.Description = fmt.Sprintf("Add lifecycle rule '%s' aborting incomplete uploads after %d days", AbortRuleID, fix.Days)
+		case FixRemovePublicStatements:
+			// Only a baseline forbidding public access fails on anonymous read grants
+			for _, id := range fix.Checks {
+				fix.AllPublic = fix.AllPublic || id == CheckBaselinePublicPolicy
+			}
+			fix.Description = "Remove statements granting anonymous write access from the bucket policy"
+			if fix.AllPublic {
+				fix.Description = "Remove all statements granting anonymous access from the bucket policy (forbidden by the baseline)"
+			}
+		}
+
+		plan.Fixes = append(plan.Fixes, fix)
+	}
+
+	return plan
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// requiredEncryption returns the algorithm and KMS key required by baseline rules
+func requiredEncryption(rules []BaselineRule) (string, string) {
+	algorithm := "AES256"
+	for _, rule := range rules {
+		if rule.Encryption == nil {
+			continue
+		}
+		if rule.Encryption.Algorithm == "aws:kms" {
+			algorithm = "aws:kms"
+		}
+		if rule.Encryption.KMSKeyID != "" {
+			return "aws:kms", rule.Encryption.KMSKeyID
+		}
+	}
+	return algorithm, ""
+}
+
+// requiredAbortDays returns the strictest abort limit of the baseline rules
+func requiredAbortDays(rules []BaselineRule) int {
+	days := 0
+	for _, rule := range rules {
+		if rule.Lifecycle == nil || rule.Lifecycle.MaxAbortIncompleteUploadDays <= 0 {
+			continue
+		}
+		if days == 0 || rule.Lifecycle.MaxAbortIncompleteUploadDays < days {
+			days = rule.Lifecycle.MaxAbortIncompleteUploadDays
+		}
+	}
+	if days == 0 {
+		return DefaultAbortDays
+	}
+	return days
+}
+
+// DisplayFixPlan prints the planned fixes and the problems that need manual action
+func DisplayFixPlan(plan *FixPlan) {
+	fmt.Printf("\nRemediation plan for %s:\n", plan.Bucket)
+	fmt.Printf("=====================\n")
+
+	if len(plan.Fixes) == 0 {
+		fmt.Printf("Nothing to fix automatically\n")
+	}
+	for i, fix := range plan.Fixes {
+		fmt.Printf("%d. %s\n", i+1, fix.Description)
+		fmt.Printf("   Resolves: %v\n", fix.Checks)
+	}
+
+	if len(plan.Manual) > 0 {
+		fmt.Printf("\nNeeds manual action:\n")
+		for _, result := range plan.Manual {
+			fmt.Printf("%s %s: %s\n", statusMarker(result.Status), result.Category, result.Message)
+			if result.Remediation != "" {
+				fmt.Printf("   💡 %s\n", result.Remediation)
+			}
+		}
+	}
+}
+
+// withAbortRule returns a copy of a lifecycle configuration (nil for none) that aborts
+// incomplete uploads after the given days, updating the mc-tool rule if present
+func withAbortRule(cfg *lifecycle.Configuration, days int) *lifecycle.Configuration {
+	updated := lifecycle.NewConfiguration()
+	if cfg != nil {
+		updated.Rules = append(updated.Rules, cfg.Rules...)
+	}
+
+	abort := lifecycle.AbortIncompleteMultipartUpload{DaysAfterInitiation: lifecycle.ExpirationDays(days)}
+	for i := range updated.Rules {
+		if updated.Rules[i].ID == AbortRuleID {
+			updated.Rules[i].Status = "Enabled"
+			updated.Rules[i].AbortIncompleteMultipartUpload = abort
+			return updated
+		}
+	}
+
+	updated.Rules = append(updated.Rules, lifecycle.Rule{
+		ID:                             AbortRuleID,
+		Status:                         "Enabled",
+		AbortIncompleteMultipartUpload: abort,
+	})
+	return updated
+}
+
+// removePublicStatements drops the Allow statements granting anonymous access to a
+// bucket: those granting write or manage access that is not overridden by a Deny, or
+// every one of them when all is set. Anonymous read and list grants are otherwise kept.
+// It returns the remaining policy ("" when no statement is left) and the number removed.
+// The rest of the policy is kept byte for byte, so fields this package does not model
+// (Id, non-AWS principals, ...) survive the rewrite.
+func removePublicStatements(policy, bucket string, all bool) (string, int, error) {
+	if policy == "" {
+		return "", 0, nil
+	}
+
+	document, err := ParsePolicy(policy)
+	if err != nil {
+		return "", 0, err
+	}
+
+	statements, err := locateStatements(policy)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(statements) != len(document.Statement) {
+		return "", 0, fmt.Errorf("failed to parse bucket policy: statements could not be located")
+	}
+
+	effective := make(map[string]bool)
+	for _, prefix := range document.PublicAccess(bucket) {
+		for _, name := range prefix.Statements {
+			effective[name] = true
+		}
+	}
+
+	var kept []policySpan
+	for i, span := range statements {
+		statement := document.Statement[i]
+		if statement.Effect == "Allow" && statement.Anonymous() &&
+			(all || (effective[statement.name()] && grantsWrite(statement))) {
+			continue
+		}
+		kept = append(kept, span)
+	}
+
+	removed := len(statements) - len(kept)
+	if removed == 0 {
+		return policy, 0, nil
+	}
+	if len(kept) == 0 {
+		return "", removed, nil
+	}
+
+	// Reuse the original separator between statements to keep the layout
+	separator := ", "
+	if len(statements) > 1 {
+		separator = policy[statements[0].end:statements[1].start]
+	}
+
+	var b strings.Builder
+	b.WriteString(policy[:statements[0].start])
+	for i, span := range kept {
+		if i > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(policy[span.start:span.end])
+	}
+	b.WriteString(policy[statements[len(statements)-1].end:])
+	return b.String(), removed, nil
+}
+
+// grantsWrite reports whether a statement grants write or manage access
+func grantsWrite(statement Statement) bool {
+	for _, access := range statement.Access() {
+		if access == AccessWrite || access == AccessManage {
+			return true
+		}
+	}
+	return false
+}
+
+// policySpan is the byte range of a statement within a policy document
+type policySpan struct {
+	start, end int
+}
+
+// locateStatements returns the byte ranges of the elements of a policy's Statement array
+func locateStatements(policy string) ([]policySpan, error) {
+	decoder := json.NewDecoder(strings.NewReader(policy))
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("failed to parse bucket policy: %w", err)
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bucket policy: %w", err)
+		}
+		if key != "Statement" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return nil, fmt.Errorf("failed to parse bucket policy: %w", err)
+			}
+			continue
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("failed to parse bucket policy: %w", err)
+		}
+		var spans []policySpan
+		for decoder.More() {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				return nil, fmt.Errorf("failed to parse bucket policy: %w", err)
+			}
+			end := int(decoder.InputOffset())
+			spans = append(spans, policySpan{start: end - len(raw), end: end})
+		}
+		return spans, nil
+	}
+
+	return nil, nil
+}
+
+// Backup is the configuration of a bucket before fixes were applied
+type Backup struct {
+	Bucket     string    `json:"bucket"`
+	CreatedAt  time.Time `json:"created_at"`
+	Fixes      []string  `json:"fixes"`
+	Versioning string    `json:"versioning"`
+	Encryption string    `json:"encryption,omitempty"` // XML; empty when not configured
+	Lifecycle  string    `json:"lifecycle,omitempty"`  // XML; empty when not configured
+	Policy     string    `json:"policy,omitempty"`
+}
+
+// DefaultBackupPath returns the backup file name used when none is given
+func DefaultBackupPath(bucket string, at time.Time) string {
+	return fmt.Sprintf("checklist-backup-%s-%s.json", bucket, at.Format("20060102-150405"))
+}
+
+// CaptureBackup reads the configuration the given fixes may change. Only "not
+// configured" responses count as empty; any other error aborts the backup.
+func CaptureBackup(ctx context.Context, client *minio.Client, bucket string, fixes []string) (*Backup, error) {
+	backup := &Backup{Bucket: bucket, CreatedAt: time.Now().UTC(), Fixes: fixes}
+
+	versioning, err := client.GetBucketVersioning(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read versioning configuration: %w", err)
+	}
+	backup.Versioning = versioning.Status
+
+	encryption, err := client.GetBucketEncryption(ctx, bucket)
+	switch {
+	case err == nil:
+		if backup.Encryption, err = marshalXML(encryption); err != nil {
+			return nil, err
+		}
+	case minio.ToErrorResponse(err).Code != "ServerSideEncryptionConfigurationNotFoundError":
+		return nil, fmt.Errorf("failed to read encryption configuration: %w", err)
+	}
+
+	lifecycleConfig, err := client.GetBucketLifecycle(ctx, bucket)
+	switch {
+	case err == nil:
+		if backup.Lifecycle, err = marshalXML(lifecycleConfig); err != nil {
+			return nil, err
+		}
+	case minio.ToErrorResponse(err).Code != "NoSuchLifecycleConfiguration":
+		return nil, fmt.Errorf("failed to read lifecycle configuration: %w", err)
+	}
+
+	if backup.Policy, err = client.GetBucketPolicy(ctx, bucket); err != nil {
+		return nil, fmt.Errorf("failed to read bucket policy: %w", err)
+	}
+
+	return backup, nil
+}
+
+func marshalXML(v interface{}) (string, error) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return string(data), nil
+}
+
+// Save writes the backup as JSON
+func (b *Backup) Save(filename string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0600); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	return nil
+}
+
+// LoadBackup reads a backup written by Save
+func LoadBackup(filename string) (*Backup, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup file: %w", err)
+	}
+
+	var backup Backup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("failed to parse backup file: %w", err)
+	}
+	if backup.Bucket == "" {
+		return nil, fmt.Errorf("backup file has no bucket")
+	}
+	return &backup, nil
+}
+
+// ApplyFixes backs up the bucket configuration to backupPath, then applies the plan.
+// On error the backup remains and can be restored with Rollback.
+func ApplyFixes(ctx context.Context, client *minio.Client, plan *FixPlan, backupPath string) error {
+	backup, err := CaptureBackup(ctx, client, plan.Bucket, plan.Kinds())
+	if err != nil {
+		return err
+	}
+	if err := backup.Save(backupPath); err != nil {
+		return err
+	}
+	fmt.Printf("Saved previous configuration to %s\n", backupPath)
+
+	for _, fix := range plan.Fixes {
+		if err := applyFix(ctx, client, plan.Bucket, fix); err != nil {
+			return fmt.Errorf("failed to %s (restore with --rollback %s): %w", fix.Kind, backupPath, err)
+		}
+		fmt.Printf("✅ %s\n", fix.Description)
+	}
+
+	return nil
+}
+
+func applyFix(ctx context.Context, client *minio.Client, bucket string, fix Fix) error {
+	switch fix.Kind {
+	case FixEnableVersioning:
+		return client.EnableVersioning(ctx, bucket)
+
+	case FixDefaultEncryption:
+		config := sse.NewConfigurationSSES3()
+		if fix.KMSKeyID != "" {
+			config = sse.NewConfigurationSSEKMS(fix.KMSKeyID)
+		}
+		return client.SetBucketEncryption(ctx, bucket, config)
+
+	case FixAbortIncompleteUploads:
+		current, err := client.GetBucketLifecycle(ctx, bucket)
+		if err != nil && minio.ToErrorResponse(err).Code != "NoSuchLifecycleConfiguration" {
+			return err
+		}
+		return client.SetBucketLifecycle(ctx, bucket, withAbortRule(current, fix.Days))
+
+	case FixRemovePublicStatements:
+		current, err := client.GetBucketPolicy(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		policy, removed, err := removePublicStatements(current, bucket, fix.AllPublic)
+		if err != nil || removed == 0 {
+			return err
+		}
+		return client.SetBucketPolicy(ctx, bucket, policy)
+
+	default:
+		return fmt.Errorf("unknown fix: %s", fix.Kind)
+	}
+}
+
+// RestoreSteps describes what Rollback will change
+func (b *Backup) RestoreSteps() []string {
+	var steps []string
+	for _, kind := range b.Fixes {
+		switch kind {
+		case FixEnableVersioning:
+			if b.Versioning == "Enabled" {
+				continue
+			}
+			steps = append(steps, "Suspend versioning (a bucket cannot return to unversioned)")
+		case FixDefaultEncryption:
+			if b.Encryption == "" {
+				steps = append(steps, "Remove default encryption")
+			} else {
+				steps = append(steps, "Restore the previous default encryption")
+			}
+		case FixAbortIncompleteUploads:
+			if b.Lifecycle == "" {
+				steps = append(steps, "Remove the lifecycle configuration")
+			} else {
+				steps = append(steps, "Restore the previous lifecycle configuration")
+			}
+		case FixRemovePublicStatements:
+			if b.Policy == "" {
+				steps = append(steps, "Remove the bucket policy")
+			} else {
+				steps = append(steps, "Restore the previous bucket policy")
+			}
+		}
+	}
+	return steps
+}
+
+// Rollback restores the configuration changed by the fixes recorded in a backup
+func Rollback(ctx context.Context, client *minio.Client, backup *Backup) error {
+	for _, kind := range backup.Fixes {
+		var err error
+		switch kind {
+		case FixEnableVersioning:
+			if backup.Versioning != "Enabled" {
+				err = client.SuspendVersioning(ctx, backup.Bucket)
+			}
+		case FixDefaultEncryption:
+			if backup.Encryption == "" {
+				err = client.RemoveBucketEncryption(ctx, backup.Bucket)
+			} else {
+				var config sse.Configuration
+				if err = xml.Unmarshal([]byte(backup.Encryption), &config); err == nil {
+					err = client.SetBucketEncryption(ctx, backup.Bucket, &config)
+				}
+			}
+		case FixAbortIncompleteUploads:
+			config := lifecycle.NewConfiguration()
+			if backup.Lifecycle != "" {
+				err = xml.Unmarshal([]byte(backup.Lifecycle), config)
+			}
+			if err == nil {
+				// An empty configuration removes the bucket lifecycle
+				err = client.SetBucketLifecycle(ctx, backup.Bucket, config)
+			}
+		case FixRemovePublicStatements:
+			err = client.SetBucketPolicy(ctx, backup.Bucket, backup.Policy)
+		default:
+			err = fmt.Errorf("unknown fix: %s", kind)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to roll back %s: %w", kind, err)
+		}
+		fmt.Printf("✅ Rolled back %s\n", kind)
+	}
+	return nil
+}
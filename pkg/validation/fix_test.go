@@ -0,0 +1,214 @@
+package validation
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanFixes(t *testing.T) {
+	report := &Report{Bucket: "data", Results: []CheckResult{
+		{ID: CheckBucketExists, Status: StatusPass},
+		{ID: CheckVersioningEnabled, Status: StatusWarn},
+		{ID: CheckEncryptionDefault, Status: StatusWarn},
+		{ID: CheckLifecycleAbortUploads, Status: StatusWarn},
+		{ID: CheckPolicyAnonymousAccess, Status: StatusFail},
+		{ID: CheckPolicyPublicPrefixes, Status: StatusWarn},
+		{ID: CheckPolicyConflicts, Status: StatusWarn, Message: "conflict"},
+		{ID: CheckNotificationConfigured, Status: StatusSkip},
+	}}
+
+	plan := PlanFixes(report, nil)
+	assert.Equal(t, []string{FixEnableVersioning, FixDefaultEncryption, FixAbortIncompleteUploads, FixRemovePublicStatements}, plan.Kinds())
+	assert.Equal(t, "Set default encryption to SSE-S3", plan.Fixes[1].Description)
+	assert.Equal(t, DefaultAbortDays, plan.Fixes[2].Days)
+	assert.Equal(t, []string{CheckPolicyAnonymousAccess}, plan.Fixes[3].Checks)
+	assert.False(t, plan.Fixes[3].AllPublic, "anonymous read grants are kept")
+
+	require.Len(t, plan.Manual, 2)
+	assert.Equal(t, CheckPolicyPublicPrefixes, plan.Manual[0].ID)
+	assert.Equal(t, CheckPolicyConflicts, plan.Manual[1].ID)
+}
+
+func TestPlanFixesPublicReadIsManual(t *testing.T) {
+	report := &Report{Bucket: "assets", Results: []CheckResult{
+		{ID: CheckPolicyAnonymousAccess, Status: StatusWarn},
+		{ID: CheckPolicyPublicPrefixes, Status: StatusWarn},
+	}}
+
+	plan := PlanFixes(report, nil)
+	assert.Empty(t, plan.Fixes)
+	assert.Len(t, plan.Manual, 2)
+
+	// A baseline forbidding public access has every anonymous statement removed
+	report.Add(CheckResult{ID: CheckBaselinePublicPolicy, Status: StatusFail})
+	plan = PlanFixes(report, nil)
+	require.Len(t, plan.Fixes, 1)
+	assert.True(t, plan.Fixes[0].AllPublic)
+}
+
+func TestPlanFixesWithBaseline(t *testing.T) {
+	baseline := &Baseline{Rules: []BaselineRule{
+		{Name: "prod", Buckets: []string{"prod-*"}, Encryption: &EncryptionRequirement{KMSKeyID: "prod-key"},
+			Lifecycle: &LifecycleRequirement{MaxAbortIncompleteUploadDays: 3}},
+		{Name: "strict", Buckets: []string{"prod-kms-*"}, Encryption: &EncryptionRequirement{Algorithm: "aws:kms"}},
+	}}
+	require.NoError(t, baseline.Validate())
+
+	report := &Report{Bucket: "prod-data", Results: []CheckResult{
+		{ID: CheckBaselineKMSKey, Status: StatusFail},
+		{ID: CheckBaselineAbortUploads, Status: StatusFail},
+	}}
+	plan := PlanFixes(report, baseline)
+	require.Len(t, plan.Fixes, 2)
+	assert.Equal(t, "prod-key", plan.Fixes[0].KMSKeyID)
+	assert.Equal(t, 3, plan.Fixes[1].Days)
+
+	// SSE-KMS without a key cannot be fixed automatically
+	baseline.Rules = baseline.Rules[1:]
+	report = &Report{Bucket: "prod-kms-data", Results: []CheckResult{
+		{ID: CheckBaselineEncryption, Status: StatusFail},
+	}}
+	plan = PlanFixes(report, baseline)
+	assert.Empty(t, plan.Fixes)
+	require.Len(t, plan.Manual, 1)
+	assert.Equal(t, CheckBaselineEncryption, plan.Manual[0].ID)
+}
+
+func TestPlanFixesMissingBucket(t *testing.T) {
+	report := &Report{Bucket: "gone", Results: []CheckResult{{ID: CheckBucketExists, Status: StatusFail}}}
+	plan := PlanFixes(report, nil)
+	assert.Empty(t, plan.Fixes)
+	assert.Len(t, plan.Manual, 1)
+}
+
+func TestWithAbortRule(t *testing.T) {
+	updated := withAbortRule(nil, 7)
+	require.Len(t, updated.Rules, 1)
+	assert.Equal(t, AbortRuleID, updated.Rules[0].ID)
+	assert.Equal(t, lifecycle.ExpirationDays(7), updated.Rules[0].AbortIncompleteMultipartUpload.DaysAfterInitiation)
+
+	existing := &lifecycle.Configuration{Rules: []lifecycle.Rule{
+		{ID: "expire", Status: "Enabled", Expiration: lifecycle.Expiration{Days: 30}},
+		{ID: AbortRuleID, Status: "Disabled", AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{DaysAfterInitiation: 30}},
+	}}
+	updated = withAbortRule(existing, 3)
+	require.Len(t, updated.Rules, 2)
+	assert.Equal(t, "Enabled", updated.Rules[1].Status)
+	assert.Equal(t, lifecycle.ExpirationDays(3), updated.Rules[1].AbortIncompleteMultipartUpload.DaysAfterInitiation)
+	assert.Equal(t, "Disabled", existing.Rules[1].Status, "the original configuration is left untouched")
+
+	_, err := xml.Marshal(updated)
+	assert.NoError(t, err)
+}
+
+func TestRemovePublicStatements(t *testing.T) {
+	policy := `{"Version": "2012-10-17", "Statement": [
+		{"Sid": "Public", "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"},
+		{"Sid": "App", "Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::1:user/app"}, "Action": "s3:PutObject", "Resource": "arn:aws:s3:::b/*",
+			"Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}}
+	]}`
+
+	// A read grant is only removed when every anonymous statement has to go
+	remaining, removed, err := removePublicStatements(policy, "b", false)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+	assert.Equal(t, policy, remaining)
+
+	remaining, removed, err = removePublicStatements(policy, "b", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	document, err := ParsePolicy(remaining)
+	require.NoError(t, err)
+	require.Len(t, document.Statement, 1)
+	assert.Equal(t, "App", document.Statement[0].Sid)
+	assert.Equal(t, []string{"IpAddress:aws:SourceIp"}, document.Statement[0].ConditionKeys())
+	assert.Equal(t, "2012-10-17", document.Version)
+
+	// Everything but the removed statements is kept as written
+	policy = `{
+  "Version": "2012-10-17",
+  "Id": "assets-policy",
+  "Statement": [
+    {"Sid": "Cdn", "Effect": "Allow", "Principal": {"Service": "cloudfront.amazonaws.com"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"},
+    {"Sid": "Public", "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"},
+    {"Sid": "Guard", "Effect": "Deny", "Principal": {"AWS": "*"}, "NotAction": "s3:GetObject", "NotResource": "arn:aws:s3:::b/public/*"}
+  ]
+}`
+	remaining, removed, err = removePublicStatements(policy, "b", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, `{
+  "Version": "2012-10-17",
+  "Id": "assets-policy",
+  "Statement": [
+    {"Sid": "Cdn", "Effect": "Allow", "Principal": {"Service": "cloudfront.amazonaws.com"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"},
+    {"Sid": "Guard", "Effect": "Deny", "Principal": {"AWS": "*"}, "NotAction": "s3:GetObject", "NotResource": "arn:aws:s3:::b/public/*"}
+  ]
+}`, remaining)
+
+	remaining, removed, err = removePublicStatements(`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "*"}]}`, "b", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Empty(t, remaining)
+
+	remaining, removed, err = removePublicStatements("", "b", true)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+	assert.Empty(t, remaining)
+}
+
+func TestRemovePublicWriteStatements(t *testing.T) {
+	policy := `{"Statement": [
+		{"Sid": "Read", "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"},
+		{"Sid": "Upload", "Effect": "Allow", "Principal": "*", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::b/incoming/*"},
+		{"Sid": "Blocked", "Effect": "Allow", "Principal": "*", "Action": "s3:DeleteObject", "Resource": "arn:aws:s3:::b/*"},
+		{"Sid": "NoDelete", "Effect": "Deny", "Principal": "*", "Action": "s3:DeleteObject", "Resource": "arn:aws:s3:::b/*"}
+	]}`
+
+	remaining, removed, err := removePublicStatements(policy, "b", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	document, err := ParsePolicy(remaining)
+	require.NoError(t, err)
+	var sids []string
+	for _, statement := range document.Statement {
+		sids = append(sids, statement.Sid)
+	}
+	assert.Equal(t, []string{"Read", "Blocked", "NoDelete"}, sids, "read grants and overridden grants are kept")
+
+	byID := resultsByID(evaluatePolicy("b", remaining))
+	assert.Equal(t, StatusWarn, byID[CheckPolicyAnonymousAccess].Status)
+}
+
+func TestBackupSaveLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "backup.json")
+	backup := &Backup{
+		Bucket:    "data",
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Fixes:     []string{FixEnableVersioning, FixDefaultEncryption, FixAbortIncompleteUploads, FixRemovePublicStatements},
+		Lifecycle: "<LifecycleConfiguration></LifecycleConfiguration>",
+		Policy:    `{"Statement": []}`,
+	}
+	require.NoError(t, backup.Save(filename))
+
+	loaded, err := LoadBackup(filename)
+	require.NoError(t, err)
+	assert.Equal(t, backup, loaded)
+
+	assert.Equal(t, []string{
+		"Suspend versioning (a bucket cannot return to unversioned)",
+		"Remove default encryption",
+		"Restore the previous lifecycle configuration",
+		"Restore the previous bucket policy",
+	}, loaded.RestoreSteps())
+
+	assert.Equal(t, "checklist-backup-data-20240501-120000.json", DefaultBackupPath("data", backup.CreatedAt))
+}
@@ -0,0 +1,334 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+// lifecycleRule is an enabled lifecycle rule with its name and scope resolved
+type lifecycleRule struct {
+	Name  string
+	Scope ruleScope
+	Rule  lifecycle.Rule
+}
+
+// lifecycleScope returns the objects a lifecycle rule applies to, including the
+// deprecated top-level prefix
+func lifecycleScope(rule lifecycle.Rule) ruleScope {
+	scope := ruleScope{Prefix: rule.Prefix, Tags: make(map[string]string)}
+	if scope.Prefix == "" {
+		scope.Prefix = rule.RuleFilter.Prefix
+	}
+	if scope.Prefix == "" {
+		scope.Prefix = rule.RuleFilter.And.Prefix
+	}
+	if !rule.RuleFilter.Tag.IsEmpty() {
+		scope.Tags[rule.RuleFilter.Tag.Key] = rule.RuleFilter.Tag.Value
+	}
+	for _, tag := range rule.RuleFilter.And.Tags {
+		scope.Tags[tag.Key] = tag.Value
+	}
+	return scope
+}
+
+// wholeBucket reports whether the scope has no prefix or tag filter
+func (s ruleScope) wholeBucket() bool {
+	return s.Prefix == "" && len(s.Tags) == 0
+}
+
+// describe formats a scope for evidence, e.g. `prefix "logs/", tags env=prod`
+func (s ruleScope) describe() string {
+	if s.wholeBucket() {
+		return "whole bucket"
+	}
+	var parts []string
+	if s.Prefix != "" {
+		parts = append(parts, fmt.Sprintf("prefix %q", s.Prefix))
+	}
+	if len(s.Tags) > 0 {
+		tags := make([]string, 0, len(s.Tags))
+		for key, value := range s.Tags {
+			tags = append(tags, key+"="+value)
+		}
+		sort.Strings(tags)
+		parts = append(parts, "tags "+strings.Join(tags, ","))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// evaluateLifecycleRules validates lifecycle rules against each other and against the
+// bucket's versioning, object lock and the server's remote tiers
+func evaluateLifecycleRules(cfg *BucketConfig) []CheckResult {
+	var rules []lifecycleRule
+	var disabled []string
+	if cfg.Lifecycle != nil {
+		for i, rule := range cfg.Lifecycle.Rules {
+			name := ruleName(rule.ID, i)
+			if rule.Status != "Enabled" {
+				disabled = append(disabled, fmt.Sprintf("Rule '%s' is disabled", name))
+				continue
+			}
+			rules = append(rules, lifecycleRule{Name: name, Scope: lifecycleScope(rule), Rule: rule})
+		}
+	}
+
+	var results []CheckResult
+	if cfg.Lifecycle != nil && len(cfg.Lifecycle.Rules) > 0 {
+		results = append(results, evaluateDisabledLifecycleRules(disabled), evaluateLifecycleConflicts(rules))
+	}
+	if cfg.Versioning.Status == "Enabled" || cfg.Versioning.Status == "Suspended" {
+		results = append(results,
+			evaluateNoncurrentExpiration(cfg.Bucket, rules),
+			evaluateDeleteMarkerCleanup(cfg.Bucket, rules))
+	}
+	if result, ok := evaluateTransitionTiers(rules, cfg.Tiers); ok {
+		results = append(results, result)
+	}
+	if result, ok := evaluateLifecycleRetention(rules, cfg.ObjectLock); ok {
+		results = append(results, result)
+	}
+	return results
+}
+
+func evaluateDisabledLifecycleRules(disabled []string) CheckResult {
+	result := CheckResult{
+		ID:       CheckLifecycleDisabledRules,
+		Category: CategoryLifecycle,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "All rules are enabled",
+	}
+	if len(disabled) > 0 {
+		result.Status = StatusWarn
+		result.Severity = SeverityLow
+		result.Message = fmt.Sprintf("%d rules are disabled and have no effect", len(disabled))
+		result.Remediation = "Enable the rules (mc ilm rule edit --id <rule>) or remove them if they are obsolete"
+		result.Evidence = disabled
+	}
+	return result
+}
+
+// evaluateLifecycleConflicts finds rules that apply to the same objects but disagree:
+// different expiration or transition schedules, or an expiration that removes objects
+// before another rule's transition is due
+func evaluateLifecycleConflicts(rules []lifecycleRule) CheckResult {
+	result := CheckResult{
+		ID:       CheckLifecycleConflicts,
+		Category: CategoryLifecycle,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "No overlapping rules with conflicting actions",
+	}
+
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			a, b := rules[i], rules[j]
+			if !a.Scope.overlaps(b.Scope) {
+				continue
+			}
+			for _, conflict := range lifecycleConflicts(a, b) {
+				result.Evidence = append(result.Evidence, fmt.Sprintf("Rules '%s' (%s) and '%s' (%s) %s",
+					a.Name, a.Scope.describe(), b.Name, b.Scope.describe(), conflict))
+			}
+		}
+	}
+
+	if len(result.Evidence) > 0 {
+		result.Status = StatusWarn
+		result.Severity = SeverityMedium
+		result.Message = fmt.Sprintf("%d conflicts between overlapping rules; the earliest action wins", len(result.Evidence))
+		result.Remediation = "Narrow the rule filters so they don't overlap, or align their schedules"
+	}
+	return result
+}
+
+// lifecycleConflicts describes the conflicting actions of two overlapping rules
+func lifecycleConflicts(a, b lifecycleRule) []string {
+	var conflicts []string
+	ra, rb := a.Rule, b.Rule
+
+	if ra.Expiration.Days > 0 && rb.Expiration.Days > 0 && ra.Expiration.Days != rb.Expiration.Days {
+		conflicts = append(conflicts, fmt.Sprintf("expire objects after %d and %d days",
+			ra.Expiration.Days, rb.Expiration.Days))
+	}
+	if days := ra.NoncurrentVersionExpiration.NoncurrentDays; days > 0 && rb.NoncurrentVersionExpiration.NoncurrentDays > 0 &&
+		days != rb.NoncurrentVersionExpiration.NoncurrentDays {
+		conflicts = append(conflicts, fmt.Sprintf("expire noncurrent versions after %d and %d days",
+			days, rb.NoncurrentVersionExpiration.NoncurrentDays))
+	}
+	if ra.Transition.StorageClass != "" && rb.Transition.StorageClass != "" &&
+		(ra.Transition.StorageClass != rb.Transition.StorageClass || ra.Transition.Days != rb.Transition.Days) {
+		conflicts = append(conflicts, fmt.Sprintf("transition objects to %s after %d days and to %s after %d days",
+			ra.Transition.StorageClass, ra.Transition.Days, rb.Transition.StorageClass, rb.Transition.Days))
+	}
+	if conflict, ok := expiresBeforeTransition(a, b); ok {
+		conflicts = append(conflicts, conflict)
+	}
+	if conflict, ok := expiresBeforeTransition(b, a); ok {
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// expiresBeforeTransition reports whether expiring rule removes objects no later than
+// transitioning rule moves them, making the transition pointless
+func expiresBeforeTransition(expiring, transitioning lifecycleRule) (string, bool) {
+	expiration := expiring.Rule.Expiration.Days
+	transition := transitioning.Rule.Transition
+	if expiration <= 0 || transition.StorageClass == "" || transition.Days < expiration {
+		return "", false
+	}
+	return fmt.Sprintf("conflict: '%s' expires objects after %d days, before '%s' transitions them to %s after %d days",
+		expiring.Name, expiration, transitioning.Name, transition.StorageClass, transition.Days), true
+}
+
+// evaluateNoncurrentExpiration checks that a versioned bucket expires noncurrent
+// versions, which otherwise accumulate forever
+func evaluateNoncurrentExpiration(bucketName string, rules []lifecycleRule) CheckResult {
+	result := CheckResult{
+		ID:          CheckLifecycleNoncurrentExpiration,
+		Category:    CategoryLifecycle,
+		Status:      StatusWarn,
+		Severity:    SeverityMedium,
+		Message:     "Versioned bucket has no rule expiring noncurrent versions; old versions are kept forever",
+		Remediation: fmt.Sprintf("mc ilm rule add --noncurrent-expire-days 30 <alias>/%s", bucketName),
+	}
+
+	wholeBucket := false
+	for _, rule := range rules {
+		expiration := rule.Rule.NoncurrentVersionExpiration
+		if expiration.NoncurrentDays <= 0 && expiration.NewerNoncurrentVersions <= 0 {
+			continue
+		}
+		evidence := fmt.Sprintf("Rule '%s' (%s) expires noncurrent versions after %d days", rule.Name, rule.Scope.describe(), expiration.NoncurrentDays)
+		if expiration.NewerNoncurrentVersions > 0 {
+			evidence += fmt.Sprintf(", keeping %d newer versions", expiration.NewerNoncurrentVersions)
+		}
+		result.Evidence = append(result.Evidence, evidence)
+		if rule.Scope.wholeBucket() {
+			wholeBucket = true
+		}
+	}
+
+	switch {
+	case wholeBucket:
+		result.Status = StatusPass
+		result.Severity = SeverityInfo
+		result.Message = "Noncurrent versions expire"
+		result.Remediation = ""
+	case len(result.Evidence) > 0:
+		result.Severity = SeverityLow
+		result.Message = "Noncurrent versions only expire for part of the bucket"
+	}
+	return result
+}
+
+// evaluateDeleteMarkerCleanup checks that a versioned bucket removes delete markers
+// left without any noncurrent versions
+func evaluateDeleteMarkerCleanup(bucketName string, rules []lifecycleRule) CheckResult {
+	result := CheckResult{
+		ID:          CheckLifecycleDeleteMarkers,
+		Category:    CategoryLifecycle,
+		Status:      StatusWarn,
+		Severity:    SeverityLow,
+		Message:     "No rule removes expired object delete markers; they slow down listings as they accumulate",
+		Remediation: fmt.Sprintf("mc ilm rule add --expire-delete-marker <alias>/%s", bucketName),
+	}
+
+	for _, rule := range rules {
+		if rule.Rule.Expiration.DeleteMarker.IsEnabled() {
+			result.Status = StatusPass
+			result.Severity = SeverityInfo
+			result.Message = fmt.Sprintf("Rule '%s' removes expired object delete markers", rule.Name)
+			result.Remediation = ""
+			result.Evidence = nil
+			return result
+		}
+		if rule.Rule.NoncurrentVersionExpiration.NoncurrentDays > 0 {
+			result.Evidence = append(result.Evidence,
+				fmt.Sprintf("Rule '%s' expires noncurrent versions but leaves their delete markers behind", rule.Name))
+		}
+	}
+	return result
+}
+
+// evaluateTransitionTiers checks that transitions target existing remote tiers; tiers is
+// nil when the server's tiers could not be listed. It reports nothing without transitions.
+func evaluateTransitionTiers(rules []lifecycleRule, tiers []string) (CheckResult, bool) {
+	known := make(map[string]bool, len(tiers))
+	for _, tier := range tiers {
+		known[tier] = true
+	}
+
+	var transitions, missing []string
+	for _, rule := range rules {
+		for _, class := range []string{rule.Rule.Transition.StorageClass, rule.Rule.NoncurrentVersionTransition.StorageClass} {
+			if class == "" {
+				continue
+			}
+			transitions = append(transitions, fmt.Sprintf("Rule '%s' -> %s", rule.Name, class))
+			if !known[class] {
+				missing = append(missing, fmt.Sprintf("Rule '%s' transitions to tier '%s', which does not exist", rule.Name, class))
+			}
+		}
+	}
+	if len(transitions) == 0 {
+		return CheckResult{}, false
+	}
+
+	result := CheckResult{
+		ID:       CheckLifecycleTransitionTiers,
+		Category: CategoryLifecycle,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "All transitions target configured tiers",
+		Evidence: transitions,
+	}
+	switch {
+	case tiers == nil:
+		result.Status = StatusSkip
+		result.Message = "Remote tiers could not be listed (admin credentials are required)"
+	case len(missing) > 0:
+		result.Status = StatusFail
+		result.Severity = SeverityHigh
+		result.Message = fmt.Sprintf("%d transitions target tiers that do not exist; those objects are never transitioned", len(missing))
+		result.Remediation = "Create the tier (mc ilm tier add) or point the rules at an existing one (mc ilm tier ls)"
+		result.Evidence = missing
+	}
+	return result, true
+}
+
+// evaluateLifecycleRetention finds noncurrent version expirations that can fall due
+// before the default object lock retention ends; the server cannot delete those versions,
+// so the rules don't do what they say. Expiring current objects only adds a delete marker,
+// which retention does not prevent. It reports nothing without a default retention.
+func evaluateLifecycleRetention(rules []lifecycleRule, objectLock ObjectLockConfig) (CheckResult, bool) {
+	if !objectLock.Enabled || objectLock.Days <= 0 {
+		return CheckResult{}, false
+	}
+
+	result := CheckResult{
+		ID:       CheckLifecycleRetention,
+		Category: CategoryLifecycle,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  fmt.Sprintf("No noncurrent version expiration is shorter than the %d-day default retention", objectLock.Days),
+	}
+	for _, rule := range rules {
+		if days := int(rule.Rule.NoncurrentVersionExpiration.NoncurrentDays); days > 0 && days < objectLock.Days {
+			result.Evidence = append(result.Evidence, fmt.Sprintf("Rule '%s' expires noncurrent versions after %d days", rule.Name, days))
+		}
+	}
+
+	if len(result.Evidence) > 0 {
+		result.Status = StatusWarn
+		result.Severity = SeverityMedium
+		result.Message = fmt.Sprintf("%d rules can expire noncurrent versions before the %d-day %s default retention ends; locked versions are kept until it does",
+			len(result.Evidence), objectLock.Days, objectLock.Mode)
+		result.Remediation = "Extend the noncurrent version expirations past the retention period, or shorten the default retention"
+	}
+	return result, true
+}
@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func versionedLifecycle(rules ...lifecycle.Rule) *BucketConfig {
+	return &BucketConfig{
+		Bucket:     "data",
+		Versioning: minio.BucketVersioningConfiguration{Status: "Enabled"},
+		Lifecycle:  &lifecycle.Configuration{Rules: rules},
+	}
+}
+
+func TestLifecycleScope(t *testing.T) {
+	scope := lifecycleScope(lifecycle.Rule{RuleFilter: lifecycle.Filter{And: lifecycle.And{
+		Prefix: "logs/", Tags: []lifecycle.Tag{{Key: "env", Value: "prod"}, {Key: "app", Value: "web"}},
+	}}})
+	assert.Equal(t, "logs/", scope.Prefix)
+	assert.Equal(t, `prefix "logs/", tags app=web,env=prod`, scope.describe())
+
+	assert.Equal(t, "old/", lifecycleScope(lifecycle.Rule{Prefix: "old/"}).Prefix)
+	assert.Equal(t, "whole bucket", lifecycleScope(lifecycle.Rule{}).describe())
+}
+
+func TestEvaluateLifecycleRulesUnversioned(t *testing.T) {
+	assert.Empty(t, evaluateLifecycleRules(&BucketConfig{Bucket: "data"}))
+
+	results := evaluateLifecycleRules(&BucketConfig{Bucket: "data", Lifecycle: &lifecycle.Configuration{Rules: []lifecycle.Rule{
+		{ID: "expire", Status: "Enabled", Expiration: lifecycle.Expiration{Days: 30}},
+		{ID: "old", Status: "Disabled"},
+	}}})
+	require.Len(t, results, 2)
+
+	byID := resultsByID(results)
+	assert.Equal(t, StatusWarn, byID[CheckLifecycleDisabledRules].Status)
+	assert.Equal(t, []string{"Rule 'old' is disabled"}, byID[CheckLifecycleDisabledRules].Evidence)
+	assert.Equal(t, StatusPass, byID[CheckLifecycleConflicts].Status)
+}
+
+func TestEvaluateLifecycleConflicts(t *testing.T) {
+	cfg := versionedLifecycle(
+		lifecycle.Rule{ID: "all", Status: "Enabled", Expiration: lifecycle.Expiration{Days: 90}},
+		lifecycle.Rule{ID: "logs", Status: "Enabled", RuleFilter: lifecycle.Filter{Prefix: "logs/"},
+			Expiration: lifecycle.Expiration{Days: 30}},
+		lifecycle.Rule{ID: "archive", Status: "Enabled", RuleFilter: lifecycle.Filter{Prefix: "logs/archive/"},
+			Transition: lifecycle.Transition{StorageClass: "COLD", Days: 60}},
+		lifecycle.Rule{ID: "data", Status: "Enabled", RuleFilter: lifecycle.Filter{Prefix: "data/"},
+			Expiration: lifecycle.Expiration{Days: 90}},
+	)
+
+	result := resultsByID(evaluateLifecycleRules(cfg))[CheckLifecycleConflicts]
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, "2 conflicts between overlapping rules; the earliest action wins", result.Message)
+	assert.Equal(t, []string{
+		`Rules 'all' (whole bucket) and 'logs' (prefix "logs/") expire objects after 90 and 30 days`,
+		`Rules 'logs' (prefix "logs/") and 'archive' (prefix "logs/archive/") conflict: ` +
+			`'logs' expires objects after 30 days, before 'archive' transitions them to COLD after 60 days`,
+	}, result.Evidence)
+}
+
+func TestEvaluateLifecycleConflictsTags(t *testing.T) {
+	tagged := func(id, value string, days int) lifecycle.Rule {
+		return lifecycle.Rule{ID: id, Status: "Enabled", Expiration: lifecycle.Expiration{Days: lifecycle.ExpirationDays(days)},
+			RuleFilter: lifecycle.Filter{Tag: lifecycle.Tag{Key: "env", Value: value}}}
+	}
+
+	result := resultsByID(evaluateLifecycleRules(versionedLifecycle(tagged("prod", "prod", 365), tagged("dev", "dev", 7))))
+	assert.Equal(t, StatusPass, result[CheckLifecycleConflicts].Status)
+
+	result = resultsByID(evaluateLifecycleRules(versionedLifecycle(tagged("prod", "prod", 365), tagged("prod-short", "prod", 7))))
+	assert.Equal(t, StatusWarn, result[CheckLifecycleConflicts].Status)
+}
+
+func TestEvaluateNoncurrentExpiration(t *testing.T) {
+	byID := resultsByID(evaluateLifecycleRules(versionedLifecycle(
+		lifecycle.Rule{ID: "expire", Status: "Enabled", Expiration: lifecycle.Expiration{Days: 30}},
+	)))
+	assert.Equal(t, StatusWarn, byID[CheckLifecycleNoncurrentExpiration].Status)
+	assert.Equal(t, SeverityMedium, byID[CheckLifecycleNoncurrentExpiration].Severity)
+	assert.Equal(t, "mc ilm rule add --noncurrent-expire-days 30 <alias>/data", byID[CheckLifecycleNoncurrentExpiration].Remediation)
+
+	byID = resultsByID(evaluateLifecycleRules(versionedLifecycle(
+		lifecycle.Rule{ID: "logs", Status: "Enabled", RuleFilter: lifecycle.Filter{Prefix: "logs/"},
+			NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 7}},
+	)))
+	result := byID[CheckLifecycleNoncurrentExpiration]
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, SeverityLow, result.Severity)
+	assert.Equal(t, []string{`Rule 'logs' (prefix "logs/") expires noncurrent versions after 7 days`}, result.Evidence)
+
+	byID = resultsByID(evaluateLifecycleRules(versionedLifecycle(
+		lifecycle.Rule{ID: "versions", Status: "Enabled",
+			NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 30, NewerNoncurrentVersions: 3}},
+	)))
+	result = byID[CheckLifecycleNoncurrentExpiration]
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Empty(t, result.Remediation)
+	assert.Equal(t, []string{"Rule 'versions' (whole bucket) expires noncurrent versions after 30 days, keeping 3 newer versions"}, result.Evidence)
+}
+
+func TestEvaluateDeleteMarkerCleanup(t *testing.T) {
+	noncurrent := lifecycle.Rule{ID: "versions", Status: "Enabled",
+		NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 30}}
+
+	result := resultsByID(evaluateLifecycleRules(versionedLifecycle(noncurrent)))[CheckLifecycleDeleteMarkers]
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, []string{"Rule 'versions' expires noncurrent versions but leaves their delete markers behind"}, result.Evidence)
+
+	markers := lifecycle.Rule{ID: "markers", Status: "Enabled", Expiration: lifecycle.Expiration{DeleteMarker: true}}
+	result = resultsByID(evaluateLifecycleRules(versionedLifecycle(noncurrent, markers)))[CheckLifecycleDeleteMarkers]
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "Rule 'markers' removes expired object delete markers", result.Message)
+	assert.Empty(t, result.Evidence)
+}
+
+func TestEvaluateTransitionTiers(t *testing.T) {
+	cfg := versionedLifecycle(
+		lifecycle.Rule{ID: "warm", Status: "Enabled", Transition: lifecycle.Transition{StorageClass: "WARM", Days: 30}},
+		lifecycle.Rule{ID: "versions", Status: "Enabled",
+			NoncurrentVersionTransition: lifecycle.NoncurrentVersionTransition{StorageClass: "GLACIER", NoncurrentDays: 7}},
+	)
+
+	result := resultsByID(evaluateLifecycleRules(cfg))[CheckLifecycleTransitionTiers]
+	assert.Equal(t, StatusSkip, result.Status)
+	assert.Equal(t, []string{"Rule 'warm' -> WARM", "Rule 'versions' -> GLACIER"}, result.Evidence)
+
+	cfg.Tiers = []string{"WARM"}
+	result = resultsByID(evaluateLifecycleRules(cfg))[CheckLifecycleTransitionTiers]
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, SeverityHigh, result.Severity)
+	assert.Equal(t, []string{"Rule 'versions' transitions to tier 'GLACIER', which does not exist"}, result.Evidence)
+
+	cfg.Tiers = []string{"WARM", "GLACIER"}
+	result = resultsByID(evaluateLifecycleRules(cfg))[CheckLifecycleTransitionTiers]
+	assert.Equal(t, StatusPass, result.Status)
+
+	_, ok := resultsByID(evaluateLifecycleRules(versionedLifecycle()))[CheckLifecycleTransitionTiers]
+	assert.False(t, ok, "no result without transitions")
+}
+
+func TestEvaluateLifecycleRetention(t *testing.T) {
+	cfg := versionedLifecycle(
+		lifecycle.Rule{ID: "expire", Status: "Enabled", Expiration: lifecycle.Expiration{Days: 30},
+			NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 400}},
+		lifecycle.Rule{ID: "versions", Status: "Enabled",
+			NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{NoncurrentDays: 90}},
+	)
+
+	_, ok := resultsByID(evaluateLifecycleRules(cfg))[CheckLifecycleRetention]
+	assert.False(t, ok, "no result without default retention")
+
+	cfg.ObjectLock = ObjectLockConfig{Enabled: true, Mode: RetentionCompliance, Days: 365}
+	result := resultsByID(evaluateLifecycleRules(cfg))[CheckLifecycleRetention]
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, "1 rules can expire noncurrent versions before the 365-day COMPLIANCE default retention ends; locked versions are kept until it does", result.Message)
+	assert.Equal(t, []string{
+		"Rule 'versions' expires noncurrent versions after 90 days",
+	}, result.Evidence, "expiring current objects only adds delete markers")
+
+	cfg.ObjectLock.Days = 7
+	result = resultsByID(evaluateLifecycleRules(cfg))[CheckLifecycleRetention]
+	assert.Equal(t, StatusPass, result.Status)
+}
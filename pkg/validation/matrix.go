@@ -0,0 +1,344 @@
+package validation
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Matrix output formats
+const (
+	MatrixText = "text"
+	MatrixCSV  = "csv"
+	MatrixHTML = "html"
+)
+
+// ParseMatrixFormat validates a compliance matrix output format
+func ParseMatrixFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return MatrixText, nil
+	case MatrixText, MatrixCSV, MatrixHTML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid matrix output format: %s (expected text, csv or html)", format)
+	}
+}
+
+// BucketReport is the checklist report of one bucket, or the error that prevented it
+type BucketReport struct {
+	Bucket string
+	Report *Report
+	Err    error
+}
+
+// CheckBuckets checks the given buckets with at most concurrency buckets in flight.
+// Reports are returned in the order of the buckets.
+func CheckBuckets(ctx context.Context, client *minio.Client, buckets []string, opts CheckOptions, concurrency int) []BucketReport {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	reports := make([]BucketReport, len(buckets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, bucket := range buckets {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, bucket string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			report, err := CheckBucketConfiguration(ctx, client, bucket, opts)
+			reports[i] = BucketReport{Bucket: bucket, Report: report, Err: err}
+		}(i, bucket)
+	}
+
+	wg.Wait()
+	return reports
+}
+
+// Matrix is the compliance of the buckets of an alias: one row per bucket and one
+// column per check ID
+type Matrix struct {
+	Alias     string
+	Generated time.Time
+	Checks    []string
+	Rows      []MatrixRow
+}
+
+// MatrixRow holds the status of each check of one bucket; Err is set when the bucket
+// could not be checked
+type MatrixRow struct {
+	Bucket   string
+	Err      error
+	Statuses map[string]Status
+}
+
+// statusRank orders statuses from least to most severe
+var statusRank = map[Status]int{StatusSkip: 0, StatusPass: 1, StatusWarn: 2, StatusFail: 3}
+
+// NewMatrix builds a compliance matrix from bucket reports. Checks are ordered as they
+// first appear; a check reported several times for a bucket (e.g. by several baseline
+// rules) takes its most severe status.
+func NewMatrix(alias string, reports []BucketReport, generated time.Time) *Matrix {
+	matrix := &Matrix{Alias: alias, Generated: generated}
+	seen := make(map[string]bool)
+
+	for _, bucketReport := range reports {
+		row := MatrixRow{Bucket: bucketReport.Bucket, Err: bucketReport.Err, Statuses: make(map[string]Status)}
+		if bucketReport.Report != nil {
+			for _, result := range bucketReport.Report.Results {
+				if !seen[result.ID] {
+					seen[result.ID] = true
+					matrix.Checks = append(matrix.Checks, result.ID)
+				}
+				if current, ok := row.Statuses[result.ID]; !ok || statusRank[result.Status] > statusRank[current] {
+					row.Statuses[result.ID] = result.Status
+				}
+			}
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+
+	return matrix
+}
+
+// complianceScore is the percentage of evaluated checks that passed; skipped checks are
+// not evaluated, and ok is false when nothing was
+func complianceScore(statuses []Status) (score float64, ok bool) {
+	passed, evaluated := 0, 0
+	for _, status := range statuses {
+		switch status {
+		case StatusPass:
+			passed++
+			evaluated++
+		case StatusWarn, StatusFail:
+			evaluated++
+		}
+	}
+	if evaluated == 0 {
+		return 0, false
+	}
+	return 100 * float64(passed) / float64(evaluated), true
+}
+
+// Score returns the compliance score of a bucket
+func (r MatrixRow) Score() (float64, bool) {
+	statuses := make([]Status, 0, len(r.Statuses))
+	for _, status := range r.Statuses {
+		statuses = append(statuses, status)
+	}
+	return complianceScore(statuses)
+}
+
+// CheckScore returns the compliance score of a check across the buckets
+func (m *Matrix) CheckScore(id string) (float64, bool) {
+	var statuses []Status
+	for _, row := range m.Rows {
+		if status, ok := row.Statuses[id]; ok {
+			statuses = append(statuses, status)
+		}
+	}
+	return complianceScore(statuses)
+}
+
+// Score returns the compliance score over every bucket and check
+func (m *Matrix) Score() (float64, bool) {
+	var statuses []Status
+	for _, row := range m.Rows {
+		for _, status := range row.Statuses {
+			statuses = append(statuses, status)
+		}
+	}
+	return complianceScore(statuses)
+}
+
+// cell returns the matrix cell of a bucket and check
+func (r MatrixRow) cell(id string) string {
+	if r.Err != nil {
+		return "error"
+	}
+	if status, ok := r.Statuses[id]; ok {
+		return string(status)
+	}
+	return "-"
+}
+
+// formatScore formats a score as a whole percentage, or n/a
+func formatScore(score float64, ok bool) string {
+	if !ok {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.0f%%", score)
+}
+
+// WriteMatrix writes a compliance matrix in the given output format
+func WriteMatrix(w io.Writer, matrix *Matrix, format string) error {
+	switch format {
+	case MatrixText, "":
+		return writeMatrixText(w, matrix)
+	case MatrixCSV:
+		return writeMatrixCSV(w, matrix)
+	case MatrixHTML:
+		return writeMatrixHTML(w, matrix)
+	default:
+		return fmt.Errorf("invalid matrix output format: %s", format)
+	}
+}
+
+// writeMatrixText lists the checks down the side and the buckets across, which fits a
+// terminal better than a column per check
+func writeMatrixText(w io.Writer, matrix *Matrix) error {
+	var b strings.Builder
+
+	title := fmt.Sprintf("Compliance Matrix: %s (%d buckets)", matrix.Alias, len(matrix.Rows))
+	fmt.Fprintf(&b, "%s\n%s\n", title, strings.Repeat("=", len(title)))
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	header := []string{"CHECK"}
+	for _, row := range matrix.Rows {
+		header = append(header, row.Bucket)
+	}
+	fmt.Fprintln(tw, strings.Join(append(header, "SCORE"), "\t"))
+
+	for _, id := range matrix.Checks {
+		line := []string{id}
+		for _, row := range matrix.Rows {
+			line = append(line, row.cell(id))
+		}
+		fmt.Fprintln(tw, strings.Join(append(line, formatScore(matrix.CheckScore(id))), "\t"))
+	}
+
+	scores := []string{"SCORE"}
+	for _, row := range matrix.Rows {
+		scores = append(scores, formatScore(row.Score()))
+	}
+	fmt.Fprintln(tw, strings.Join(scores, "\t"))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&b, "\nOverall compliance: %s\n", formatScore(matrix.Score()))
+	for _, row := range matrix.Rows {
+		if row.Err != nil {
+			fmt.Fprintf(&b, "❌ %s: %v\n", row.Bucket, row.Err)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMatrixCSV(w io.Writer, matrix *Matrix) error {
+	writer := csv.NewWriter(w)
+
+	header := append([]string{"bucket"}, matrix.Checks...)
+	if err := writer.Write(append(header, "score")); err != nil {
+		return err
+	}
+
+	for _, row := range matrix.Rows {
+		record := []string{row.Bucket}
+		for _, id := range matrix.Checks {
+			record = append(record, row.cell(id))
+		}
+		if err := writer.Write(append(record, csvScore(row.Score()))); err != nil {
+			return err
+		}
+	}
+
+	scores := []string{"score"}
+	for _, id := range matrix.Checks {
+		scores = append(scores, csvScore(matrix.CheckScore(id)))
+	}
+	if err := writer.Write(append(scores, csvScore(matrix.Score()))); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvScore formats a score for spreadsheets: a number, or empty for n/a
+func csvScore(score float64, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", score)
+}
+
+var matrixHTMLTemplate = template.Must(template.New("matrix").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Compliance matrix: {{.Alias}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: center; }
+th.bucket, td.bucket { text-align: left; }
+.pass { background: #d4edda; }
+.warn { background: #fff3cd; }
+.fail, .error { background: #f8d7da; }
+.skip { color: #888; }
+</style>
+</head>
+<body>
+<h1>Compliance matrix: {{.Alias}}</h1>
+<p>Generated {{.Generated}} &middot; {{len .Rows}} buckets &middot; overall compliance {{.Overall}}</p>
+<table>
+<tr><th class="bucket">Bucket</th>{{range .Checks}}<th>{{.}}</th>{{end}}<th>Score</th></tr>
+{{range .Rows}}<tr><td class="bucket" title="{{.Error}}">{{.Bucket}}</td>{{range .Cells}}<td class="{{.}}">{{.}}</td>{{end}}<th>{{.Score}}</th></tr>
+{{end}}<tr><th class="bucket">Score</th>{{range .CheckScores}}<th>{{.}}</th>{{end}}<th>{{.Overall}}</th></tr>
+</table>
+</body>
+</html>
+`))
+
+func writeMatrixHTML(w io.Writer, matrix *Matrix) error {
+	type htmlRow struct {
+		Bucket string
+		Error  string
+		Cells  []string
+		Score  string
+	}
+	data := struct {
+		Alias       string
+		Generated   string
+		Overall     string
+		Checks      []string
+		Rows        []htmlRow
+		CheckScores []string
+	}{
+		Alias:     matrix.Alias,
+		Generated: matrix.Generated.Format("2006-01-02 15:04 MST"),
+		Overall:   formatScore(matrix.Score()),
+		Checks:    matrix.Checks,
+	}
+
+	for _, row := range matrix.Rows {
+		entry := htmlRow{Bucket: row.Bucket, Score: formatScore(row.Score())}
+		if row.Err != nil {
+			entry.Error = row.Err.Error()
+		}
+		for _, id := range matrix.Checks {
+			entry.Cells = append(entry.Cells, row.cell(id))
+		}
+		data.Rows = append(data.Rows, entry)
+	}
+	for _, id := range matrix.Checks {
+		data.CheckScores = append(data.CheckScores, formatScore(matrix.CheckScore(id)))
+	}
+
+	return matrixHTMLTemplate.Execute(w, data)
+}
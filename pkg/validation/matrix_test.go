@@ -0,0 +1,118 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleMatrix() *Matrix {
+	reports := []BucketReport{
+		{Bucket: "prod", Report: &Report{Bucket: "prod", Results: []CheckResult{
+			{ID: CheckBucketExists, Status: StatusPass},
+			{ID: CheckVersioningEnabled, Status: StatusPass},
+			{ID: CheckEncryptionDefault, Status: StatusPass},
+			{ID: CheckBaselineVersioning, Status: StatusPass},
+			{ID: CheckBaselineVersioning, Status: StatusFail},
+		}}},
+		{Bucket: "logs", Report: &Report{Bucket: "logs", Results: []CheckResult{
+			{ID: CheckBucketExists, Status: StatusPass},
+			{ID: CheckVersioningEnabled, Status: StatusWarn},
+			{ID: CheckEncryptionDefault, Status: StatusSkip},
+			{ID: CheckPolicyConfigured, Status: StatusSkip},
+		}}},
+		{Bucket: "broken", Err: errors.New("failed to check bucket existence: access denied")},
+	}
+	return NewMatrix("prod-alias", reports, time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC))
+}
+
+func TestParseMatrixFormat(t *testing.T) {
+	format, err := ParseMatrixFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, MatrixText, format)
+
+	_, err = ParseMatrixFormat("sarif")
+	assert.Error(t, err)
+}
+
+func TestNewMatrix(t *testing.T) {
+	matrix := sampleMatrix()
+
+	assert.Equal(t, []string{CheckBucketExists, CheckVersioningEnabled, CheckEncryptionDefault,
+		CheckBaselineVersioning, CheckPolicyConfigured}, matrix.Checks)
+	require.Len(t, matrix.Rows, 3)
+	assert.Equal(t, StatusFail, matrix.Rows[0].Statuses[CheckBaselineVersioning], "most severe status wins")
+	assert.Equal(t, "-", matrix.Rows[1].cell(CheckBaselineVersioning))
+	assert.Equal(t, "error", matrix.Rows[2].cell(CheckBucketExists))
+}
+
+func TestMatrixScores(t *testing.T) {
+	matrix := sampleMatrix()
+
+	score, ok := matrix.Rows[0].Score()
+	require.True(t, ok)
+	assert.Equal(t, 75.0, score)
+
+	score, ok = matrix.Rows[1].Score()
+	require.True(t, ok)
+	assert.Equal(t, 50.0, score, "skipped checks are not evaluated")
+
+	_, ok = matrix.Rows[2].Score()
+	assert.False(t, ok)
+
+	score, ok = matrix.CheckScore(CheckVersioningEnabled)
+	require.True(t, ok)
+	assert.Equal(t, 50.0, score)
+
+	_, ok = matrix.CheckScore(CheckPolicyConfigured)
+	assert.False(t, ok)
+
+	score, ok = matrix.Score()
+	require.True(t, ok)
+	assert.InDelta(t, 66.67, score, 0.01)
+}
+
+func TestWriteMatrixText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMatrix(&buf, sampleMatrix(), MatrixText))
+
+	out := buf.String()
+	assert.Contains(t, out, "Compliance Matrix: prod-alias (3 buckets)\n")
+	assert.Contains(t, out, "CHECK                prod  logs  broken  SCORE\n")
+	assert.Contains(t, out, "versioning.enabled   pass  warn  error   50%\n")
+	assert.Contains(t, out, "policy.configured    -     skip  error   n/a\n")
+	assert.Contains(t, out, "SCORE                75%   50%   n/a\n")
+	assert.Contains(t, out, "Overall compliance: 67%\n")
+	assert.Contains(t, out, "❌ broken: failed to check bucket existence: access denied\n")
+}
+
+func TestWriteMatrixCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMatrix(&buf, sampleMatrix(), MatrixCSV))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	assert.Equal(t, []string{"bucket", "bucket.exists", "versioning.enabled", "encryption.default",
+		"baseline.versioning", "policy.configured", "score"}, records[0])
+	assert.Equal(t, []string{"logs", "pass", "warn", "skip", "-", "skip", "50.0"}, records[2])
+	assert.Equal(t, []string{"broken", "error", "error", "error", "error", "error", ""}, records[3])
+	assert.Equal(t, []string{"score", "100.0", "50.0", "100.0", "0.0", "", "66.7"}, records[4])
+}
+
+func TestWriteMatrixHTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMatrix(&buf, sampleMatrix(), MatrixHTML))
+
+	out := buf.String()
+	assert.Contains(t, out, "<title>Compliance matrix: prod-alias</title>")
+	assert.Contains(t, out, "Generated 2024-03-31 12:00 UTC &middot; 3 buckets &middot; overall compliance 67%")
+	assert.Contains(t, out, `<td class="warn">warn</td>`)
+	assert.Contains(t, out, `<td class="bucket" title="failed to check bucket existence: access denied">broken</td>`)
+	assert.Contains(t, out, "<th>75%</th>")
+}
@@ -0,0 +1,388 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+// Events triggered by writing and then deleting a probe object version
+const (
+	probeCreatedEvent = "s3:ObjectCreated:Put"
+	probeRemovedEvent = "s3:ObjectRemoved:Delete"
+)
+
+// probeRetryAfter is how long the first round of probes is waited for before the probes
+// still missing events are written once more. The listener connects in the background
+// and can miss the first round.
+const probeRetryAfter = 2 * time.Second
+
+// ProbeTimeout is how long ProbeNotifications waits for probe events by default
+const ProbeTimeout = 10 * time.Second
+
+// notificationTarget is one Lambda, Topic or Queue configuration
+type notificationTarget struct {
+	Kind   string
+	ID     string
+	ARN    string
+	Events []string
+	Prefix string
+	Suffix string
+}
+
+// notificationTargets flattens a notification configuration into its targets
+func notificationTargets(config notification.Configuration) []notificationTarget {
+	var targets []notificationTarget
+	for _, lambda := range config.LambdaConfigs {
+		targets = append(targets, newNotificationTarget("Lambda", lambda.Lambda, lambda.Config))
+	}
+	for _, topic := range config.TopicConfigs {
+		targets = append(targets, newNotificationTarget("Topic", topic.Topic, topic.Config))
+	}
+	for _, queue := range config.QueueConfigs {
+		targets = append(targets, newNotificationTarget("Queue", queue.Queue, queue.Config))
+	}
+	return targets
+}
+
+func newNotificationTarget(kind, arn string, config notification.Config) notificationTarget {
+	target := notificationTarget{Kind: kind, ID: config.ID, ARN: arn}
+	if arn == "" && config.Arn.Resource != "" {
+		target.ARN = config.Arn.String()
+	}
+	for _, event := range config.Events {
+		target.Events = append(target.Events, string(event))
+	}
+	if config.Filter != nil {
+		for _, rule := range config.Filter.S3Key.FilterRules {
+			switch strings.ToLower(rule.Name) {
+			case "prefix":
+				target.Prefix = rule.Value
+			case "suffix":
+				target.Suffix = rule.Value
+			}
+		}
+	}
+	return target
+}
+
+// name identifies the target by kind and ARN
+func (t notificationTarget) name() string {
+	arn := t.ARN
+	if arn == "" {
+		arn = "<no ARN>"
+	}
+	return t.Kind + " " + arn
+}
+
+// describe formats the target's events and filters for evidence
+func (t notificationTarget) describe() string {
+	events := strings.Join(t.Events, ", ")
+	if events == "" {
+		events = "no events"
+	}
+	text := fmt.Sprintf("%s: %s", t.name(), events)
+	if t.Prefix != "" {
+		text += fmt.Sprintf(" prefix %q", t.Prefix)
+	}
+	if t.Suffix != "" {
+		text += fmt.Sprintf(" suffix %q", t.Suffix)
+	}
+	return text
+}
+
+// matches reports whether the target subscribes to an event
+func (t notificationTarget) matches(event string) bool {
+	for _, pattern := range t.Events {
+		if wildcardMatch(pattern, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventsOverlap reports whether two event lists share an event, e.g.
+// s3:ObjectCreated:* and s3:ObjectCreated:Put
+func eventsOverlap(a, b []string) bool {
+	for _, eventA := range a {
+		for _, eventB := range b {
+			if wildcardMatch(eventA, eventB) || wildcardMatch(eventB, eventA) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keyFiltersOverlap reports whether some object key can match both targets' filters
+func keyFiltersOverlap(a, b notificationTarget) bool {
+	prefixes := strings.HasPrefix(a.Prefix, b.Prefix) || strings.HasPrefix(b.Prefix, a.Prefix)
+	suffixes := strings.HasSuffix(a.Suffix, b.Suffix) || strings.HasSuffix(b.Suffix, a.Suffix)
+	return prefixes && suffixes
+}
+
+// evaluateNotificationTargets lists each configuration and checks that it has a valid
+// ARN and subscribes to events; it reports nothing without configurations
+func evaluateNotificationTargets(targets []notificationTarget) []CheckResult {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	details := CheckResult{
+		ID:       CheckNotificationTargets,
+		Category: CategoryNotification,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  fmt.Sprintf("%d targets with valid ARNs and events", len(targets)),
+	}
+	var invalid []string
+	for _, target := range targets {
+		details.Evidence = append(details.Evidence, target.describe())
+		if _, err := notification.NewArnFromString(target.ARN); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: invalid ARN", target.name()))
+		}
+		if len(target.Events) == 0 {
+			invalid = append(invalid, fmt.Sprintf("%s: no events", target.name()))
+		}
+	}
+	if len(invalid) > 0 {
+		details.Status = StatusFail
+		details.Severity = SeverityMedium
+		details.Message = fmt.Sprintf("%d problems with notification targets", len(invalid))
+		details.Remediation = "Re-add the target with a valid ARN and events: mc event add <alias>/<bucket> <arn> --event put,delete"
+		details.Evidence = append(invalid, details.Evidence...)
+	}
+
+	overlaps := CheckResult{
+		ID:       CheckNotificationOverlaps,
+		Category: CategoryNotification,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "No configurations deliver the same event to a target twice",
+	}
+	for i := range targets {
+		for j := i + 1; j < len(targets); j++ {
+			a, b := targets[i], targets[j]
+			if a.ARN == b.ARN && eventsOverlap(a.Events, b.Events) && keyFiltersOverlap(a, b) {
+				overlaps.Evidence = append(overlaps.Evidence, fmt.Sprintf("%s: configurations '%s' and '%s' overlap",
+					a.name(), ruleName(a.ID, i), ruleName(b.ID, j)))
+			}
+		}
+	}
+	if len(overlaps.Evidence) > 0 {
+		overlaps.Status = StatusWarn
+		overlaps.Severity = SeverityMedium
+		overlaps.Message = fmt.Sprintf("%d pairs of configurations overlap; matching objects are delivered twice", len(overlaps.Evidence))
+		overlaps.Remediation = "Merge the configurations or make their events or prefix/suffix filters disjoint"
+	}
+
+	return []CheckResult{details, overlaps}
+}
+
+// NotificationProbe is the outcome of writing and deleting a probe object matching one
+// notification configuration
+type NotificationProbe struct {
+	Target   string
+	Key      string
+	Expected []string // events the probe should trigger for this configuration
+	Received []string
+	Err      error
+}
+
+// probeKey returns an object key matching a target's prefix and suffix filters
+func probeKey(target notificationTarget, index int, now time.Time) string {
+	return fmt.Sprintf("%smc-tool-probe-%s-%d%s", target.Prefix, strconv.FormatInt(now.UnixNano(), 36), index, target.Suffix)
+}
+
+// ProbeNotifications writes and deletes a probe object per notification configuration
+// while listening to the bucket's events, and reports which events arrived in time.
+// Probes missing events after probeRetryAfter are retried once with a new key.
+func ProbeNotifications(ctx context.Context, client *minio.Client, bucketName string, config notification.Configuration, timeout time.Duration) ([]NotificationProbe, error) {
+	targets := notificationTargets(config)
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := client.ListenBucketNotification(ctx, bucketName, "", "", []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:*"})
+
+	now := time.Now()
+	probes := make([]NotificationProbe, len(targets))
+	for i, target := range targets {
+		probes[i] = writeProbe(ctx, client, bucketName, target, i, now)
+	}
+
+	received := make(map[string][]string)
+	if err := awaitProbes(events, probes, received, min(probeRetryAfter, timeout)); err != nil {
+		return nil, err
+	}
+	if probesComplete(probes, received) || timeout <= probeRetryAfter {
+		return collectProbes(probes, received), nil
+	}
+
+	now = time.Now()
+	for i, target := range targets {
+		if probes[i].Err == nil && len(missingEvents(probes[i].Expected, received[probes[i].Key])) > 0 {
+			probes[i] = writeProbe(ctx, client, bucketName, target, i, now)
+		}
+	}
+	if err := awaitProbes(events, probes, received, timeout); err != nil {
+		return nil, err
+	}
+	return collectProbes(probes, received), nil
+}
+
+// writeProbe writes and deletes a probe object matching a target's filters, unless the
+// target is not subscribed to the events that would trigger
+func writeProbe(ctx context.Context, client *minio.Client, bucketName string, target notificationTarget, index int, now time.Time) NotificationProbe {
+	probe := NotificationProbe{Target: target.name(), Key: probeKey(target, index, now)}
+	for _, event := range []string{probeCreatedEvent, probeRemovedEvent} {
+		if target.matches(event) {
+			probe.Expected = append(probe.Expected, event)
+		}
+	}
+	if len(probe.Expected) == 0 {
+		return probe
+	}
+
+	body := []byte("mc-tool notification probe")
+	info, err := client.PutObject(ctx, bucketName, probe.Key, bytes.NewReader(body), int64(len(body)), minio.PutObjectOptions{})
+	if err != nil {
+		probe.Err = fmt.Errorf("failed to write probe object: %w", err)
+	} else if err := client.RemoveObject(ctx, bucketName, probe.Key, minio.RemoveObjectOptions{VersionID: info.VersionID}); err != nil {
+		probe.Err = fmt.Errorf("failed to delete probe object: %w", err)
+	}
+	return probe
+}
+
+// awaitProbes records received events by key until every probe is complete or the
+// timeout passes
+func awaitProbes(events <-chan notification.Info, probes []NotificationProbe, received map[string][]string, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	for !probesComplete(probes, received) {
+		select {
+		case info, ok := <-events:
+			if !ok {
+				return fmt.Errorf("notification listener closed")
+			}
+			if info.Err != nil {
+				return fmt.Errorf("failed to listen for notifications: %w", info.Err)
+			}
+			for _, record := range info.Records {
+				key := record.S3.Object.Key
+				if unescaped, err := url.QueryUnescape(key); err == nil {
+					key = unescaped
+				}
+				received[key] = append(received[key], record.EventName)
+			}
+		case <-deadline:
+			return nil
+		}
+	}
+	return nil
+}
+
+// probesComplete reports whether every probe that was written received its expected events
+func probesComplete(probes []NotificationProbe, received map[string][]string) bool {
+	for _, probe := range probes {
+		if probe.Err == nil && len(missingEvents(probe.Expected, received[probe.Key])) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func collectProbes(probes []NotificationProbe, received map[string][]string) []NotificationProbe {
+	for i := range probes {
+		probes[i].Received = received[probes[i].Key]
+	}
+	return probes
+}
+
+func missingEvents(expected, received []string) []string {
+	var missing []string
+	for _, event := range expected {
+		found := false
+		for _, got := range received {
+			if got == event {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, event)
+		}
+	}
+	return missing
+}
+
+// probeSkipReason explains why probe objects must not be written to a bucket: default
+// retention would keep every probe version, and replication would copy the probes to
+// the destination. It is empty when probing is safe.
+func probeSkipReason(cfg *BucketConfig) string {
+	switch {
+	case cfg.ObjectLockErr != nil:
+		return "the object lock configuration could not be read"
+	case cfg.ObjectLock.Mode != "":
+		return fmt.Sprintf("%s default retention would keep the probe versions for %d days", cfg.ObjectLock.Mode, cfg.ObjectLock.Days)
+	case cfg.ReplicationErr != nil:
+		return "the replication configuration could not be read"
+	case cfg.Replication != nil:
+		return "replication would copy the probes to the destination"
+	}
+	return ""
+}
+
+// evaluateNotificationProbes reports configurations whose probe events did not arrive
+func evaluateNotificationProbes(probes []NotificationProbe, timeout time.Duration) CheckResult {
+	result := CheckResult{
+		ID:       CheckNotificationDelivery,
+		Category: CategoryNotification,
+		Status:   StatusSkip,
+		Severity: SeverityInfo,
+		Message:  "No notification configurations to test",
+	}
+	if len(probes) == 0 {
+		return result
+	}
+
+	var delivered, failed, untested int
+	for _, probe := range probes {
+		missing := missingEvents(probe.Expected, probe.Received)
+		switch {
+		case probe.Err != nil:
+			failed++
+			result.Evidence = append(result.Evidence, fmt.Sprintf("%s (%s): %v", probe.Target, probe.Key, probe.Err))
+		case len(probe.Expected) == 0:
+			untested++
+			result.Evidence = append(result.Evidence, fmt.Sprintf("%s: not subscribed to put or delete events, not tested", probe.Target))
+		case len(missing) > 0:
+			failed++
+			result.Evidence = append(result.Evidence, fmt.Sprintf("%s (%s): missing %s", probe.Target, probe.Key, strings.Join(missing, ", ")))
+		default:
+			delivered++
+			result.Evidence = append(result.Evidence, fmt.Sprintf("%s (%s): received %s", probe.Target, probe.Key, strings.Join(probe.Expected, ", ")))
+		}
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Probe events fired for %d configurations", delivered)
+	if untested > 0 {
+		result.Message += fmt.Sprintf(" (%d not tested)", untested)
+	}
+	if failed > 0 {
+		result.Status = StatusFail
+		result.Severity = SeverityHigh
+		result.Message = fmt.Sprintf("Probe events did not fire within %s for %d of %d configurations", timeout, failed, len(probes))
+		result.Remediation = "Check that the target is reachable and look for delivery errors in the server logs (mc admin logs <alias>)"
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/minio/minio-go/v7/pkg/replication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queueConfig(id, arn, prefix, suffix string, events ...notification.EventType) notification.QueueConfig {
+	config := notification.Config{ID: id, Events: events}
+	if prefix != "" {
+		config.AddFilterPrefix(prefix)
+	}
+	if suffix != "" {
+		config.AddFilterSuffix(suffix)
+	}
+	return notification.QueueConfig{Config: config, Queue: arn}
+}
+
+func TestNotificationTargets(t *testing.T) {
+	targets := notificationTargets(notification.Configuration{
+		QueueConfigs: []notification.QueueConfig{
+			queueConfig("csv", "arn:minio:sqs::1:webhook", "uploads/", ".csv", notification.ObjectCreatedAll),
+		},
+		LambdaConfigs: []notification.LambdaConfig{{
+			Config: notification.Config{Events: []notification.EventType{notification.ObjectRemovedAll}},
+			Lambda: "arn:minio:lambda::1:webhook",
+		}},
+	})
+	require.Len(t, targets, 2)
+
+	assert.Equal(t, "Lambda arn:minio:lambda::1:webhook: s3:ObjectRemoved:*", targets[0].describe())
+	assert.Equal(t, `Queue arn:minio:sqs::1:webhook: s3:ObjectCreated:* prefix "uploads/" suffix ".csv"`, targets[1].describe())
+	assert.True(t, targets[1].matches(probeCreatedEvent))
+	assert.False(t, targets[1].matches(probeRemovedEvent))
+
+	key := probeKey(targets[1], 3, time.Unix(0, 0))
+	assert.True(t, strings.HasPrefix(key, "uploads/mc-tool-probe-"))
+	assert.True(t, strings.HasSuffix(key, "-3.csv"))
+}
+
+func TestEvaluateNotificationTargets(t *testing.T) {
+	assert.Empty(t, evaluateNotificationTargets(nil))
+
+	results := evaluateNotificationTargets(notificationTargets(notification.Configuration{
+		QueueConfigs: []notification.QueueConfig{
+			queueConfig("all", "arn:minio:sqs::1:webhook", "", "", notification.ObjectCreatedAll),
+			queueConfig("images", "arn:minio:sqs::1:webhook", "images/", ".png", notification.ObjectCreatedPut),
+			queueConfig("deletes", "arn:minio:sqs::1:webhook", "images/", "", notification.ObjectRemovedAll),
+			queueConfig("other-target", "arn:minio:sqs::1:kafka", "", "", notification.ObjectCreatedAll),
+			queueConfig("csv", "arn:minio:sqs::1:kafka", "", ".csv", notification.ObjectCreatedPut),
+			queueConfig("json", "arn:minio:sqs::1:kafka", "", ".json", notification.ObjectCreatedPut),
+		},
+	}))
+	require.Len(t, results, 2)
+
+	targets := results[0]
+	assert.Equal(t, StatusPass, targets.Status)
+	assert.Len(t, targets.Evidence, 6)
+
+	overlaps := results[1]
+	assert.Equal(t, StatusWarn, overlaps.Status)
+	assert.Equal(t, []string{
+		"Queue arn:minio:sqs::1:webhook: configurations 'all' and 'images' overlap",
+		"Queue arn:minio:sqs::1:kafka: configurations 'other-target' and 'csv' overlap",
+		"Queue arn:minio:sqs::1:kafka: configurations 'other-target' and 'json' overlap",
+	}, overlaps.Evidence)
+}
+
+func TestEvaluateNotificationTargetsInvalid(t *testing.T) {
+	results := evaluateNotificationTargets(notificationTargets(notification.Configuration{
+		TopicConfigs: []notification.TopicConfig{{Topic: "webhook"}},
+	}))
+
+	assert.Equal(t, StatusFail, results[0].Status)
+	assert.Equal(t, []string{"Topic webhook: invalid ARN", "Topic webhook: no events", "Topic webhook: no events"}, results[0].Evidence)
+}
+
+func TestEvaluateNotificationProbes(t *testing.T) {
+	result := evaluateNotificationProbes(nil, ProbeTimeout)
+	assert.Equal(t, StatusSkip, result.Status)
+
+	delivered := NotificationProbe{
+		Target:   "Queue arn:minio:sqs::1:webhook",
+		Key:      "mc-tool-probe-1",
+		Expected: []string{probeCreatedEvent, probeRemovedEvent},
+		Received: []string{probeCreatedEvent, probeRemovedEvent},
+	}
+	untested := NotificationProbe{Target: "Queue arn:minio:sqs::1:kafka"}
+
+	result = evaluateNotificationProbes([]NotificationProbe{delivered, untested}, ProbeTimeout)
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "Probe events fired for 1 configurations (1 not tested)", result.Message)
+	assert.Equal(t, "Queue arn:minio:sqs::1:webhook (mc-tool-probe-1): received s3:ObjectCreated:Put, s3:ObjectRemoved:Delete", result.Evidence[0])
+
+	missing := delivered
+	missing.Received = []string{probeCreatedEvent}
+	failed := NotificationProbe{Target: "Lambda arn:minio:lambda::1:webhook", Key: "mc-tool-probe-2",
+		Expected: []string{probeCreatedEvent}, Err: errors.New("failed to write probe object: access denied")}
+
+	result = evaluateNotificationProbes([]NotificationProbe{missing, failed}, ProbeTimeout)
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, "Probe events did not fire within 10s for 2 of 2 configurations", result.Message)
+	assert.Equal(t, []string{
+		"Queue arn:minio:sqs::1:webhook (mc-tool-probe-1): missing s3:ObjectRemoved:Delete",
+		"Lambda arn:minio:lambda::1:webhook (mc-tool-probe-2): failed to write probe object: access denied",
+	}, result.Evidence)
+}
+
+func TestProbesComplete(t *testing.T) {
+	probes := []NotificationProbe{
+		{Key: "a", Expected: []string{probeCreatedEvent}},
+		{Key: "b", Expected: []string{probeCreatedEvent}, Err: errors.New("failed")},
+	}
+	received := map[string][]string{}
+	assert.False(t, probesComplete(probes, received))
+
+	received["a"] = []string{probeCreatedEvent}
+	assert.True(t, probesComplete(probes, received), "probes that failed to write are not waited for")
+}
+
+func TestAwaitProbes(t *testing.T) {
+	events := make(chan notification.Info, 1)
+	probes := []NotificationProbe{{Key: "logs/probe 1", Expected: []string{probeCreatedEvent}}}
+	received := map[string][]string{}
+
+	var info notification.Info
+	info.Records = make([]notification.Event, 1)
+	info.Records[0].EventName = probeCreatedEvent
+	info.Records[0].S3.Object.Key = "logs%2Fprobe+1"
+	events <- info
+
+	require.NoError(t, awaitProbes(events, probes, received, time.Second))
+	assert.Equal(t, []string{probeCreatedEvent}, received["logs/probe 1"])
+
+	// Missing events end the wait at the timeout without an error
+	probes = append(probes, NotificationProbe{Key: "other", Expected: []string{probeCreatedEvent}})
+	require.NoError(t, awaitProbes(events, probes, received, 10*time.Millisecond))
+	assert.False(t, probesComplete(probes, received))
+
+	close(events)
+	assert.Error(t, awaitProbes(events, probes, received, time.Second))
+}
+
+func TestProbeSkipReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      BucketConfig
+		expected string
+	}{
+		{"plain bucket", BucketConfig{}, ""},
+		{"lock without default retention", BucketConfig{ObjectLock: ObjectLockConfig{Enabled: true}}, ""},
+		{"default retention", BucketConfig{ObjectLock: ObjectLockConfig{Enabled: true, Mode: RetentionGovernance, Days: 30}},
+			"GOVERNANCE default retention would keep the probe versions for 30 days"},
+		{"replication", BucketConfig{Replication: &replication.Config{}}, "replication would copy the probes to the destination"},
+		{"unknown replication", BucketConfig{ReplicationErr: errors.New("denied")}, "the replication configuration could not be read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, probeSkipReason(&tt.cfg))
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Retention modes
+const (
+	RetentionGovernance = "GOVERNANCE"
+	RetentionCompliance = "COMPLIANCE"
+)
+
+// maxExamples limits the object keys listed as evidence by object scans
+const maxExamples = 10
+
+// ObjectLockConfig is the object lock configuration of a bucket
+type ObjectLockConfig struct {
+	Enabled bool
+	Mode    string // default retention mode; empty when there is no default retention
+	Days    int    // default retention period in days (years are counted as 365 days)
+}
+
+// fetchObjectLockConfig reads the object lock configuration; a bucket created without
+// object lock reports it as disabled rather than as an error
+func fetchObjectLockConfig(ctx context.Context, client *minio.Client, bucketName string) (ObjectLockConfig, error) {
+	enabled, mode, validity, unit, err := client.GetObjectLockConfig(ctx, bucketName)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "ObjectLockConfigurationNotFoundError" {
+			return ObjectLockConfig{}, nil
+		}
+		return ObjectLockConfig{}, err
+	}
+
+	config := ObjectLockConfig{Enabled: enabled == "Enabled"}
+	if mode != nil && validity != nil && unit != nil {
+		config.Mode = string(*mode)
+		config.Days = int(*validity)
+		if *unit == minio.Years {
+			config.Days *= 365
+		}
+	}
+	return config, nil
+}
+
+// modeSatisfies reports whether a retention mode meets a required mode. COMPLIANCE is
+// stricter than GOVERNANCE, so it satisfies both.
+func modeSatisfies(mode, required string) bool {
+	return mode == required || (mode == RetentionCompliance && required == RetentionGovernance)
+}
+
+// evaluateObjectLock checks object lock enablement and the default retention
+func evaluateObjectLock(bucketName string, config ObjectLockConfig) []CheckResult {
+	if !config.Enabled {
+		return []CheckResult{{
+			ID:       CheckObjectLockEnabled,
+			Category: CategoryObjectLock,
+			Status:   StatusSkip,
+			Severity: SeverityInfo,
+			Message:  "Not enabled",
+		}}
+	}
+
+	results := []CheckResult{{
+		ID:       CheckObjectLockEnabled,
+		Category: CategoryObjectLock,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "Enabled",
+	}}
+
+	if config.Mode == "" {
+		results = append(results, CheckResult{
+			ID:          CheckObjectLockDefaultRetention,
+			Category:    CategoryObjectLock,
+			Status:      StatusWarn,
+			Severity:    SeverityLow,
+			Message:     "No default retention; objects are only protected when written with explicit retention",
+			Remediation: fmt.Sprintf("Set a default retention: mc retention set --default GOVERNANCE 30d <alias>/%s", bucketName),
+		})
+	} else {
+		results = append(results, CheckResult{
+			ID:       CheckObjectLockDefaultRetention,
+			Category: CategoryObjectLock,
+			Status:   StatusPass,
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("Default retention %s for %d days", config.Mode, config.Days),
+		})
+	}
+
+	return results
+}
+
+// ObjectRetention is the retention state of one object version
+type ObjectRetention struct {
+	Key         string
+	VersionID   string
+	Mode        string
+	RetainUntil time.Time // zero when the object has no retention
+	LegalHold   bool
+}
+
+// ScanRetention reads the retention and legal hold of the current object versions,
+// stopping after limit objects when limit is positive
+func ScanRetention(ctx context.Context, client *minio.Client, bucketName string, limit int) ([]ObjectRetention, error) {
+	var retention []ObjectRetention
+	err := statObjects(ctx, client, bucketName, limit, func(key string, info minio.ObjectInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to stat object %s: %w", key, err)
+		}
+		retention = append(retention, objectRetention(info))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return retention, nil
+}
+
+// objectRetention extracts the object lock headers of a stat response
+func objectRetention(info minio.ObjectInfo) ObjectRetention {
+	entry := ObjectRetention{
+		Key:       info.Key,
+		VersionID: info.VersionID,
+		Mode:      info.Metadata.Get("X-Amz-Object-Lock-Mode"),
+		LegalHold: info.Metadata.Get("X-Amz-Object-Lock-Legal-Hold") == "ON",
+	}
+	if until := info.Metadata.Get("X-Amz-Object-Lock-Retain-Until-Date"); until != "" {
+		if parsed, err := time.Parse(time.RFC3339, until); err == nil {
+			entry.RetainUntil = parsed
+		}
+	}
+	return entry
+}
+
+// evaluateRetentionScan reports objects without effective retention, with less than
+// minRetention left (when positive), and under legal hold
+func evaluateRetentionScan(bucketName string, lock ObjectLockConfig, objects []ObjectRetention, now time.Time, minRetention time.Duration) []CheckResult {
+	if len(objects) == 0 {
+		return []CheckResult{{
+			ID:       CheckObjectLockObjectRetention,
+			Category: CategoryObjectLock,
+			Status:   StatusSkip,
+			Severity: SeverityInfo,
+			Message:  "No objects to scan",
+		}}
+	}
+
+	var unprotected, short, held []string
+	for _, object := range objects {
+		switch {
+		case object.RetainUntil.IsZero() || !object.RetainUntil.After(now):
+			unprotected = append(unprotected, object.Key+": no retention")
+		case minRetention > 0 && object.RetainUntil.Sub(now) < minRetention:
+			short = append(short, fmt.Sprintf("%s: %s until %s", object.Key, object.Mode, object.RetainUntil.Format("2006-01-02")))
+		}
+		if object.LegalHold {
+			held = append(held, object.Key)
+		}
+	}
+
+	retention := CheckResult{
+		ID:       CheckObjectLockObjectRetention,
+		Category: CategoryObjectLock,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  fmt.Sprintf("All %d scanned objects are under retention", len(objects)),
+	}
+	if minRetention > 0 {
+		retention.Message = fmt.Sprintf("All %d scanned objects are retained for at least %s", len(objects), formatDays(minRetention))
+	}
+
+	if len(unprotected) > 0 || len(short) > 0 {
+		var parts []string
+		if len(unprotected) > 0 {
+			parts = append(parts, fmt.Sprintf("%d without retention", len(unprotected)))
+		}
+		if len(short) > 0 {
+			parts = append(parts, fmt.Sprintf("%d retained for less than %s", len(short), formatDays(minRetention)))
+		}
+		retention.Message = fmt.Sprintf("%d of %d scanned objects: %s", len(unprotected)+len(short), len(objects), strings.Join(parts, ", "))
+		retention.Status = StatusWarn
+		retention.Severity = SeverityMedium
+		if minRetention > 0 {
+			retention.Status = StatusFail
+			retention.Severity = SeverityHigh
+		}
+		retention.Remediation = retentionRemediation(bucketName, lock, minRetention)
+		retention.Evidence = append(firstN(unprotected, maxExamples), firstN(short, maxExamples)...)
+	}
+
+	legalHolds := CheckResult{
+		ID:       CheckObjectLockLegalHolds,
+		Category: CategoryObjectLock,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "No scanned objects are under legal hold",
+	}
+	if len(held) > 0 {
+		legalHolds.Status = StatusWarn
+		legalHolds.Severity = SeverityLow
+		legalHolds.Message = fmt.Sprintf("%d scanned objects are under legal hold and cannot be deleted until it is released", len(held))
+		legalHolds.Evidence = firstN(held, maxExamples)
+	}
+
+	return []CheckResult{retention, legalHolds}
+}
+
+// retentionRemediation suggests retaining existing objects for the required minimum, or
+// for the bucket's default retention when no minimum is set, in the default mode
+func retentionRemediation(bucketName string, lock ObjectLockConfig, minRetention time.Duration) string {
+	mode := lock.Mode
+	if mode == "" {
+		mode = RetentionGovernance
+	}
+
+	period := "<days>d"
+	switch {
+	case minRetention > 0:
+		period = fmt.Sprintf("%dd", (minRetention+24*time.Hour-1)/(24*time.Hour))
+	case lock.Mode != "" && lock.Days > 0:
+		period = fmt.Sprintf("%dd", lock.Days)
+	}
+
+	return fmt.Sprintf("Set retention on existing objects: mc retention set --recursive %s %s <alias>/%s", mode, period, bucketName)
+}
+
+func firstN(values []string, n int) []string {
+	if len(values) > n {
+		return append(values[:n:n], fmt.Sprintf("... and %d more", len(values)-n))
+	}
+	return values
+}
+
+// formatDays formats a duration as whole days
+func formatDays(d time.Duration) string {
+	return fmt.Sprintf("%d days", int(d.Hours()/24))
+}
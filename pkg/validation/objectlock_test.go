@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateObjectLock(t *testing.T) {
+	results := evaluateObjectLock("data", ObjectLockConfig{})
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSkip, results[0].Status)
+
+	results = evaluateObjectLock("data", ObjectLockConfig{Enabled: true})
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusPass, results[0].Status)
+	assert.Equal(t, StatusWarn, results[1].Status)
+	assert.Contains(t, results[1].Remediation, "<alias>/data")
+
+	results = evaluateObjectLock("data", ObjectLockConfig{Enabled: true, Mode: RetentionCompliance, Days: 365})
+	assert.Equal(t, StatusPass, results[1].Status)
+	assert.Equal(t, "Default retention COMPLIANCE for 365 days", results[1].Message)
+}
+
+func TestObjectRetentionHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Amz-Object-Lock-Mode", "GOVERNANCE")
+	header.Set("X-Amz-Object-Lock-Retain-Until-Date", "2030-01-02T03:04:05Z")
+	header.Set("X-Amz-Object-Lock-Legal-Hold", "ON")
+
+	entry := objectRetention(minio.ObjectInfo{Key: "a", VersionID: "v1", Metadata: header})
+	assert.Equal(t, "GOVERNANCE", entry.Mode)
+	assert.Equal(t, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), entry.RetainUntil)
+	assert.True(t, entry.LegalHold)
+
+	entry = objectRetention(minio.ObjectInfo{Key: "b", Metadata: http.Header{}})
+	assert.True(t, entry.RetainUntil.IsZero())
+	assert.False(t, entry.LegalHold)
+}
+
+func TestEvaluateRetentionScan(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	objects := []ObjectRetention{
+		{Key: "locked", Mode: RetentionCompliance, RetainUntil: now.Add(400 * day)},
+		{Key: "short", Mode: RetentionGovernance, RetainUntil: now.Add(10 * day)},
+		{Key: "expired", Mode: RetentionGovernance, RetainUntil: now.Add(-day)},
+		{Key: "none", LegalHold: true},
+	}
+
+	results := evaluateRetentionScan("legal", ObjectLockConfig{}, objects, now, 0)
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusWarn, results[0].Status)
+	assert.Equal(t, "2 of 4 scanned objects: 2 without retention", results[0].Message)
+	assert.Contains(t, results[0].Remediation, "GOVERNANCE <days>d <alias>/legal")
+	assert.Equal(t, []string{"expired: no retention", "none: no retention"}, results[0].Evidence)
+	assert.Equal(t, StatusWarn, results[1].Status)
+	assert.Equal(t, []string{"none"}, results[1].Evidence)
+
+	results = evaluateRetentionScan("legal", ObjectLockConfig{Enabled: true, Mode: RetentionCompliance, Days: 30}, objects, now, 365*day)
+	assert.Equal(t, StatusFail, results[0].Status)
+	assert.Equal(t, SeverityHigh, results[0].Severity)
+	assert.Equal(t, "3 of 4 scanned objects: 2 without retention, 1 retained for less than 365 days", results[0].Message)
+	assert.Contains(t, results[0].Evidence, "short: GOVERNANCE until 2024-01-11")
+	assert.Equal(t, "Set retention on existing objects: mc retention set --recursive COMPLIANCE 365d <alias>/legal", results[0].Remediation)
+
+	results = evaluateRetentionScan("legal", ObjectLockConfig{}, objects[:1], now, 365*day)
+	assert.Equal(t, StatusPass, results[0].Status)
+	assert.Equal(t, StatusPass, results[1].Status)
+
+	results = evaluateRetentionScan("legal", ObjectLockConfig{}, nil, now, 0)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSkip, results[0].Status)
+}
+
+func TestEvaluateRetentionScanLimitsEvidence(t *testing.T) {
+	var objects []ObjectRetention
+	for i := 0; i < 15; i++ {
+		objects = append(objects, ObjectRetention{Key: fmt.Sprintf("key-%02d", i)})
+	}
+
+	results := evaluateRetentionScan("legal", ObjectLockConfig{}, objects, time.Now(), 0)
+	require.Len(t, results[0].Evidence, maxExamples+1)
+	assert.Equal(t, "... and 5 more", results[0].Evidence[maxExamples])
+}
@@ -0,0 +1,279 @@
+package validation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Report output formats
+const (
+	OutputText  = "text"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputJUnit = "junit"
+	OutputSARIF = "sarif"
+)
+
+// ParseOutputFormat validates a report output format
+func ParseOutputFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return OutputText, nil
+	case OutputText, OutputJSON, OutputYAML, OutputJUnit, OutputSARIF:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid output format: %s (expected text, json, yaml, junit or sarif)", format)
+	}
+}
+
+// WriteReport writes a report in the given output format
+func WriteReport(w io.Writer, report *Report, format string) error {
+	switch format {
+	case OutputText, "":
+		return writeText(w, report)
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case OutputYAML:
+		encoder := yaml.NewEncoder(w)
+		defer encoder.Close()
+		return encoder.Encode(report)
+	case OutputJUnit:
+		return writeJUnit(w, report)
+	case OutputSARIF:
+		return writeSARIF(w, report)
+	default:
+		return fmt.Errorf("invalid output format: %s", format)
+	}
+}
+
+// statusMarker returns the text marker of a status
+func statusMarker(status Status) string {
+	switch status {
+	case StatusPass:
+		return "✅"
+	case StatusWarn:
+		return "⚠️ "
+	case StatusFail:
+		return "❌"
+	default:
+		return "➖"
+	}
+}
+
+func writeText(w io.Writer, report *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Checking bucket: %s\n\n", report.Bucket)
+	for _, result := range report.Results {
+		fmt.Fprintf(&b, "%s %s: %s\n", statusMarker(result.Status), result.Category, result.Message)
+		for _, evidence := range result.Evidence {
+			fmt.Fprintf(&b, "   - %s\n", evidence)
+		}
+		if result.Remediation != "" && (result.Status == StatusWarn || result.Status == StatusFail) {
+			fmt.Fprintf(&b, "   💡 %s\n", result.Remediation)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nSummary: %d passed, %d warnings, %d failed, %d skipped\n",
+		report.Count(StatusPass), report.Count(StatusWarn), report.Count(StatusFail), report.Count(StatusSkip))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// resultDetails joins a result's remediation and evidence into a plain-text block
+func resultDetails(result CheckResult) string {
+	var lines []string
+	if result.Remediation != "" {
+		lines = append(lines, "Remediation: "+result.Remediation)
+	}
+	for _, evidence := range result.Evidence {
+		lines = append(lines, "Evidence: "+evidence)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeJUnit writes one test case per check. Failures are JUnit failures; warnings pass
+// with their details in system-out so --fail-on decides whether they gate a pipeline.
+func writeJUnit(w io.Writer, report *Report) error {
+	suite := junitTestSuite{
+		Name:     report.Bucket,
+		Tests:    len(report.Results),
+		Failures: report.Count(StatusFail),
+		Skipped:  report.Count(StatusSkip),
+	}
+
+	for _, result := range report.Results {
+		testCase := junitTestCase{
+			Name:      result.ID,
+			ClassName: result.Category,
+		}
+		switch result.Status {
+		case StatusFail:
+			testCase.Failure = &junitFailure{
+				Message: result.Message,
+				Type:    string(result.Severity),
+				Text:    resultDetails(result),
+			}
+		case StatusSkip:
+			testCase.Skipped = &junitSkipped{Message: result.Message}
+		case StatusWarn:
+			testCase.SystemOut = strings.TrimSpace("WARNING: " + result.Message + "\n" + resultDetails(result))
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	Help             *sarifMessage `json:"help,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// writeSARIF writes warnings and failures as SARIF 2.1.0 results
+func writeSARIF(w io.Writer, report *Report) error {
+	driver := sarifDriver{
+		Name:           "mc-tool",
+		InformationURI: "https://github.com/liamdn8/mc-tool",
+		Rules:          []sarifRule{},
+	}
+	results := []sarifResult{}
+	seenRules := make(map[string]bool)
+
+	for _, result := range report.Results {
+		if result.Status != StatusWarn && result.Status != StatusFail {
+			continue
+		}
+
+		if !seenRules[result.ID] {
+			seenRules[result.ID] = true
+			rule := sarifRule{ID: result.ID, ShortDescription: sarifMessage{Text: result.Category}}
+			if result.Remediation != "" {
+				rule.Help = &sarifMessage{Text: result.Remediation}
+			}
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		level := "warning"
+		if result.Status == StatusFail {
+			level = "error"
+		}
+
+		message := result.Message
+		if len(result.Evidence) > 0 {
+			message += ": " + strings.Join(result.Evidence, "; ")
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  result.ID,
+			Level:   level,
+			Message: sarifMessage{Text: message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: "s3://" + report.Bucket},
+				},
+			}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
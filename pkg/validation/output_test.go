@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func sampleReport() *Report {
+	return &Report{
+		Bucket: "data",
+		Results: []CheckResult{
+			{ID: CheckBucketExists, Category: CategoryBucket, Status: StatusPass, Severity: SeverityInfo, Message: "Exists"},
+			{ID: CheckVersioningEnabled, Category: CategoryVersioning, Status: StatusWarn, Severity: SeverityMedium,
+				Message: "Disabled", Remediation: "mc version enable <alias>/data"},
+			{ID: CheckEncryptionDefault, Category: CategoryEncryption, Status: StatusFail, Severity: SeverityHigh,
+				Message: "Not configured", Evidence: []string{"no SSE rules"}},
+			{ID: CheckPolicyConfigured, Category: CategoryPolicy, Status: StatusSkip, Severity: SeverityInfo, Message: "Not configured"},
+		},
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	format, err := ParseOutputFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, OutputText, format)
+
+	_, err = ParseOutputFormat("html")
+	assert.Error(t, err)
+}
+
+func TestWriteReportText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, sampleReport(), OutputText))
+
+	out := buf.String()
+	assert.Contains(t, out, "✅ Bucket: Exists")
+	assert.Contains(t, out, "⚠️  Versioning: Disabled\n   💡 mc version enable <alias>/data")
+	assert.Contains(t, out, "❌ Server-side Encryption: Not configured\n   - no SSE rules")
+	assert.Contains(t, out, "Summary: 1 passed, 1 warnings, 1 failed, 1 skipped")
+}
+
+func TestWriteReportJSONAndYAML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, sampleReport(), OutputJSON))
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, *sampleReport(), decoded)
+
+	buf.Reset()
+	require.NoError(t, WriteReport(&buf, sampleReport(), OutputYAML))
+
+	decoded = Report{}
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, *sampleReport(), decoded)
+}
+
+func TestWriteReportJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, sampleReport(), OutputJUnit))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	require.Len(t, suites.Suites, 1)
+
+	suite := suites.Suites[0]
+	assert.Equal(t, "data", suite.Name)
+	assert.Equal(t, 4, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Skipped)
+	require.NotNil(t, suite.Cases[2].Failure)
+	assert.Equal(t, "Not configured", suite.Cases[2].Failure.Message)
+	assert.Contains(t, suite.Cases[1].SystemOut, "WARNING: Disabled")
+}
+
+func TestWriteReportSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, sampleReport(), OutputSARIF))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 2, "only warnings and failures are reported")
+	assert.Equal(t, CheckVersioningEnabled, results[0].RuleID)
+	assert.Equal(t, "warning", results[0].Level)
+	assert.Equal(t, "error", results[1].Level)
+	assert.Equal(t, "Not configured: no SSE rules", results[1].Message.Text)
+	assert.Equal(t, "s3://data", results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, 2)
+}
@@ -0,0 +1,485 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// stringList decodes policy fields that may be a single string or an array of strings
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = stringList{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("expected a string or an array of strings: %w", err)
+	}
+	*l = multiple
+	return nil
+}
+
+// Principal is the principal of a policy statement. A bare "*" is stored as AWS ["*"].
+type Principal struct {
+	AWS stringList `json:"AWS,omitempty"`
+}
+
+func (p *Principal) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		p.AWS = stringList{single}
+		return nil
+	}
+
+	var object struct {
+		AWS stringList `json:"AWS"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("invalid principal: %w", err)
+	}
+	p.AWS = object.AWS
+	return nil
+}
+
+// Anonymous reports whether the principal matches everyone
+func (p Principal) Anonymous() bool {
+	for _, principal := range p.AWS {
+		if principal == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Statement is a single bucket policy statement
+type Statement struct {
+	Sid          string                                `json:"Sid,omitempty"`
+	Effect       string                                `json:"Effect"`
+	Principal    Principal                             `json:"Principal"`
+	NotPrincipal *Principal                            `json:"NotPrincipal,omitempty"`
+	Action       stringList                            `json:"Action"`
+	NotAction    stringList                            `json:"NotAction,omitempty"`
+	Resource     stringList                            `json:"Resource"`
+	NotResource  stringList                            `json:"NotResource,omitempty"`
+	Condition    map[string]map[string]json.RawMessage `json:"Condition,omitempty"`
+
+	// Index is the 1-based position of the statement in the policy
+	Index int `json:"-"`
+}
+
+// PolicyDocument is a parsed bucket policy
+type PolicyDocument struct {
+	Version   string      `json:"Version"`
+	Statement []Statement `json:"Statement"`
+}
+
+// ParsePolicy parses a bucket policy document
+func ParsePolicy(policy string) (*PolicyDocument, error) {
+	var document PolicyDocument
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return nil, fmt.Errorf("failed to parse bucket policy: %w", err)
+	}
+	for i := range document.Statement {
+		document.Statement[i].Index = i + 1
+	}
+	return &document, nil
+}
+
+// PublicStatements returns the statements that allow anonymous access
+func (d *PolicyDocument) PublicStatements() []Statement {
+	var public []Statement
+	for _, statement := range d.Statement {
+		if statement.Effect == "Allow" && statement.Anonymous() {
+			public = append(public, statement)
+		}
+	}
+	return public
+}
+
+// Anonymous reports whether the statement applies to unauthenticated requests.
+// NotPrincipal applies to everyone except the listed principals.
+func (s Statement) Anonymous() bool {
+	return s.Principal.Anonymous() || s.NotPrincipal != nil
+}
+
+// Actions returns the action patterns of the statement; NotAction counts as every action
+func (s Statement) Actions() []string {
+	if len(s.NotAction) > 0 {
+		return []string{"*"}
+	}
+	return s.Action
+}
+
+// Resources returns the resource patterns of the statement; NotResource counts as every resource
+func (s Statement) Resources() []string {
+	if len(s.NotResource) > 0 {
+		return []string{"*"}
+	}
+	return s.Resource
+}
+
+// ConditionKeys returns the condition keys of the statement as "Operator:key", sorted
+func (s Statement) ConditionKeys() []string {
+	var keys []string
+	for operator, conditions := range s.Condition {
+		for key := range conditions {
+			keys = append(keys, operator+":"+key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// name identifies the statement by Sid, or by position when it has none
+func (s Statement) name() string {
+	if s.Sid != "" {
+		return s.Sid
+	}
+	return fmt.Sprintf("#%d", s.Index)
+}
+
+// describe returns a short description of a statement for evidence
+func (s Statement) describe() string {
+	return fmt.Sprintf("Statement %s: %s %v on %v", s.name(), s.Effect, []string(s.Action), []string(s.Resource))
+}
+
+// Access is a class of S3 operations granted by a policy
+type Access string
+
+// Access classes, from most to least dangerous when granted anonymously
+const (
+	AccessManage Access = "manage"
+	AccessWrite  Access = "write"
+	AccessRead   Access = "read"
+	AccessList   Access = "list"
+)
+
+var accessOrder = []Access{AccessManage, AccessWrite, AccessRead, AccessList}
+
+// accessActions lists the actions that make up each access class
+var accessActions = map[Access][]string{
+	AccessManage: {
+		"s3:PutBucketPolicy", "s3:DeleteBucketPolicy", "s3:DeleteBucket", "s3:PutBucketVersioning",
+		"s3:PutLifecycleConfiguration", "s3:PutEncryptionConfiguration", "s3:PutBucketNotification",
+		"s3:PutReplicationConfiguration", "s3:PutBucketObjectLockConfiguration", "s3:PutBucketTagging",
+	},
+	AccessWrite: {
+		"s3:PutObject", "s3:DeleteObject", "s3:DeleteObjectVersion", "s3:AbortMultipartUpload",
+		"s3:PutObjectTagging", "s3:PutObjectRetention", "s3:PutObjectLegalHold",
+	},
+	AccessRead: {
+		"s3:GetObject", "s3:GetObjectVersion", "s3:GetObjectTagging",
+	},
+	AccessList: {
+		"s3:ListBucket", "s3:ListBucketVersions", "s3:ListBucketMultipartUploads", "s3:ListMultipartUploadParts",
+	},
+}
+
+// Access returns the access classes granted or denied by the statement's actions
+func (s Statement) Access() []Access {
+	var granted []Access
+	for _, access := range accessOrder {
+		if actionsCover(s.Actions(), accessActions[access]) {
+			granted = append(granted, access)
+		}
+	}
+	return granted
+}
+
+// actionsCover reports whether any action pattern matches any of the given actions
+func actionsCover(patterns, actions []string) bool {
+	for _, pattern := range patterns {
+		for _, action := range actions {
+			if wildcardMatch(strings.ToLower(pattern), strings.ToLower(action)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches IAM-style patterns where * and ? also match "/"
+func wildcardMatch(pattern, value string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(value); i >= 0; i-- {
+				if wildcardMatch(pattern[1:], value[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(value) == 0 {
+				return false
+			}
+		default:
+			if len(value) == 0 || pattern[0] != value[0] {
+				return false
+			}
+		}
+		pattern, value = pattern[1:], value[1:]
+	}
+	return len(value) == 0
+}
+
+// literalPrefix returns the part of a pattern before its first wildcard
+func literalPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, "*?"); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// patternsOverlap reports whether two wildcard patterns can match a common value
+func patternsOverlap(a, b string) bool {
+	prefixA, prefixB := literalPrefix(a), literalPrefix(b)
+	wildA, wildB := prefixA != a, prefixB != b
+	switch {
+	case !wildA && !wildB:
+		return a == b
+	case !wildA:
+		return wildcardMatch(b, a)
+	case !wildB:
+		return wildcardMatch(a, b)
+	default:
+		return strings.HasPrefix(prefixA, prefixB) || strings.HasPrefix(prefixB, prefixA)
+	}
+}
+
+// anyOverlap reports whether any pattern of one list overlaps any pattern of the other
+func anyOverlap(a, b []string, fold bool) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if fold {
+				x, y = strings.ToLower(x), strings.ToLower(y)
+			}
+			if patternsOverlap(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// principalsOverlap reports whether two statements can apply to the same caller
+func principalsOverlap(a, b Statement) bool {
+	if a.Anonymous() || b.Anonymous() {
+		return true
+	}
+	for _, x := range a.Principal.AWS {
+		for _, y := range b.Principal.AWS {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PolicyConflict is an Allow statement overlapped by a Deny statement
+type PolicyConflict struct {
+	Allow Statement
+	Deny  Statement
+}
+
+// Conflicts returns the Allow/Deny statement pairs that overlap in principal, action
+// and resource. Deny always wins, so the Allow grants less than it appears to.
+func (d *PolicyDocument) Conflicts() []PolicyConflict {
+	var conflicts []PolicyConflict
+	for _, allow := range d.Statement {
+		if allow.Effect != "Allow" {
+			continue
+		}
+		for _, deny := range d.Statement {
+			if deny.Effect != "Deny" {
+				continue
+			}
+			if principalsOverlap(allow, deny) &&
+				anyOverlap(allow.Actions(), deny.Actions(), true) &&
+				anyOverlap(allow.Resources(), deny.Resources(), false) {
+				conflicts = append(conflicts, PolicyConflict{Allow: allow, Deny: deny})
+			}
+		}
+	}
+	return conflicts
+}
+
+// PrefixAccess is the anonymous access effectively granted to a key prefix
+type PrefixAccess struct {
+	Prefix      string
+	Access      []Access
+	Conditional bool
+	Statements  []string
+}
+
+// resourceScope is what a resource pattern covers within one bucket
+type resourceScope struct {
+	prefix  string
+	bucket  bool
+	objects bool
+}
+
+// covers reports whether the scope applies to an access class. List and manage
+// actions act on the bucket resource; read and write act on object resources.
+func (r resourceScope) covers(access Access) bool {
+	if access == AccessList || access == AccessManage {
+		return r.bucket
+	}
+	return r.objects
+}
+
+// scopeOf returns the part of a bucket a resource pattern covers, if any
+func scopeOf(bucket, resource string) (resourceScope, bool) {
+	bucketARN := "arn:aws:s3:::" + bucket
+	scope := resourceScope{
+		bucket:  wildcardMatch(resource, bucketARN),
+		objects: wildcardMatch(resource, bucketARN+"/"),
+	}
+
+	if strings.HasPrefix(resource, bucketARN+"/") {
+		scope.objects = true
+		scope.prefix = literalPrefix(strings.TrimPrefix(resource, bucketARN+"/"))
+	}
+	return scope, scope.bucket || scope.objects
+}
+
+// coveredActions returns the given actions matched by any of the patterns
+func coveredActions(patterns, actions []string) []string {
+	var covered []string
+	for _, action := range actions {
+		if actionsCover(patterns, []string{action}) {
+			covered = append(covered, action)
+		}
+	}
+	return covered
+}
+
+// objectPattern rewrites a resource pattern matching every object of the bucket (such
+// as "*" or "arn:aws:s3:::*") as bucketARN/*; other patterns are returned unchanged
+func objectPattern(bucketARN, resource string) string {
+	if !strings.HasPrefix(resource, bucketARN+"/") && strings.HasSuffix(resource, "*") && wildcardMatch(resource, bucketARN+"/") {
+		return bucketARN + "/*"
+	}
+	return resource
+}
+
+// patternCovers reports whether a Deny pattern matches every value an Allow pattern
+// matches. Only literal patterns and patterns ending in a single trailing * count;
+// a wildcard anywhere else (e.g. data/*.secret) may leave most values uncovered.
+func patternCovers(deny, allow string) bool {
+	prefix := literalPrefix(deny)
+	switch deny {
+	case prefix:
+		return deny == allow
+	case prefix + "*":
+		return strings.HasPrefix(literalPrefix(allow), prefix)
+	}
+	return false
+}
+
+// deniedAnonymously reports whether unconditional anonymous Deny statements cover
+// every action of an access class that an Allow statement grants on a resource
+func (d *PolicyDocument) deniedAnonymously(bucket, allowResource string, access Access, allowed []string) bool {
+	bucketARN := "arn:aws:s3:::" + bucket
+	remaining := coveredActions(allowed, accessActions[access])
+	for _, deny := range d.Statement {
+		if deny.Effect != "Deny" || !deny.Anonymous() || len(deny.Condition) > 0 {
+			continue
+		}
+		for _, resource := range deny.Resources() {
+			scope, ok := scopeOf(bucket, resource)
+			if !ok || !scope.covers(access) {
+				continue
+			}
+			// The bucket resource is a single ARN, so matching it covers it; object
+			// access is only covered when the Deny pattern spans the Allow pattern
+			objectAccess := access == AccessRead || access == AccessWrite
+			if objectAccess && !patternCovers(objectPattern(bucketARN, resource), objectPattern(bucketARN, allowResource)) {
+				continue
+			}
+			var stillAllowed []string
+			for _, action := range remaining {
+				if !actionsCover(deny.Actions(), []string{action}) {
+					stillAllowed = append(stillAllowed, action)
+				}
+			}
+			remaining = stillAllowed
+		}
+	}
+	return len(remaining) == 0
+}
+
+// PublicAccess returns the anonymous access effectively granted per key prefix, after
+// unconditional anonymous Deny statements are applied, sorted by prefix
+func (d *PolicyDocument) PublicAccess(bucket string) []PrefixAccess {
+	byPrefix := make(map[string]*PrefixAccess)
+	granted := make(map[string]map[Access]bool)
+
+	for _, statement := range d.PublicStatements() {
+		for _, resource := range statement.Resources() {
+			scope, ok := scopeOf(bucket, resource)
+			if !ok {
+				continue
+			}
+			prefix := scope.prefix
+			for _, access := range statement.Access() {
+				if !scope.covers(access) || d.deniedAnonymously(bucket, resource, access, statement.Actions()) {
+					continue
+				}
+				entry, exists := byPrefix[prefix]
+				if !exists {
+					entry = &PrefixAccess{Prefix: prefix, Conditional: true}
+					byPrefix[prefix] = entry
+					granted[prefix] = make(map[Access]bool)
+				}
+				if !granted[prefix][access] {
+					granted[prefix][access] = true
+					entry.Access = append(entry.Access, access)
+				}
+				if len(statement.Condition) == 0 {
+					entry.Conditional = false
+				}
+				if len(entry.Statements) == 0 || entry.Statements[len(entry.Statements)-1] != statement.name() {
+					entry.Statements = append(entry.Statements, statement.name())
+				}
+			}
+		}
+	}
+
+	var result []PrefixAccess
+	for _, entry := range byPrefix {
+		sort.Slice(entry.Access, func(i, j int) bool {
+			return accessRank(entry.Access[i]) < accessRank(entry.Access[j])
+		})
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Prefix < result[j].Prefix
+	})
+	return result
+}
+
+func accessRank(access Access) int {
+	for i, a := range accessOrder {
+		if a == access {
+			return i
+		}
+	}
+	return len(accessOrder)
+}
+
+// joinAccess formats access classes as "read, list"
+func joinAccess(access []Access) string {
+	names := make([]string, len(access))
+	for i, a := range access {
+		names[i] = string(a)
+	}
+	return strings.Join(names, ", ")
+}
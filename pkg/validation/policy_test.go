@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	document, err := ParsePolicy(`{
+		"Version": "2012-10-17",
+		"Statement": [
+			{"Sid": "Anon", "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"},
+			{"Sid": "AnonAWS", "Effect": "Allow", "Principal": {"AWS": ["*"]}, "Action": ["s3:ListBucket"], "Resource": ["arn:aws:s3:::b"]},
+			{"Sid": "User", "Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::1:user/app"}, "Action": "s3:*", "Resource": "*"},
+			{"Sid": "DenyAnon", "Effect": "Deny", "Principal": "*", "Action": "s3:DeleteObject", "Resource": "arn:aws:s3:::b/*"}
+		]
+	}`)
+	require.NoError(t, err)
+	require.Len(t, document.Statement, 4)
+
+	assert.Equal(t, []string{"s3:GetObject"}, []string(document.Statement[0].Action))
+	assert.True(t, document.Statement[0].Principal.Anonymous())
+	assert.False(t, document.Statement[2].Principal.Anonymous())
+
+	public := document.PublicStatements()
+	require.Len(t, public, 2)
+	assert.Equal(t, "Anon", public[0].Sid)
+	assert.Equal(t, "AnonAWS", public[1].Sid)
+}
+
+func TestParsePolicyInvalid(t *testing.T) {
+	_, err := ParsePolicy(`{"Statement": [{"Action": 42}]}`)
+	assert.Error(t, err)
+
+	_, err = ParsePolicy("not json")
+	assert.Error(t, err)
+}
+
+func TestStatementAccess(t *testing.T) {
+	tests := []struct {
+		actions []string
+		want    []Access
+	}{
+		{[]string{"s3:GetObject"}, []Access{AccessRead}},
+		{[]string{"s3:Get*", "s3:List*"}, []Access{AccessRead, AccessList}},
+		{[]string{"s3:PutObject", "s3:DeleteObject"}, []Access{AccessWrite}},
+		{[]string{"s3:*"}, []Access{AccessManage, AccessWrite, AccessRead, AccessList}},
+		{[]string{"S3:GETOBJECT"}, []Access{AccessRead}},
+		{[]string{"s3:GetBucketLocation"}, nil},
+	}
+
+	for _, tt := range tests {
+		statement := Statement{Effect: "Allow", Action: tt.actions}
+		assert.Equal(t, tt.want, statement.Access(), tt.actions)
+	}
+
+	notAction := Statement{Effect: "Allow", NotAction: stringList{"s3:DeleteObject"}}
+	assert.Len(t, notAction.Access(), 4)
+}
+
+func TestWildcardMatch(t *testing.T) {
+	assert.True(t, wildcardMatch("arn:aws:s3:::data/*", "arn:aws:s3:::data/a/b/c"))
+	assert.True(t, wildcardMatch("arn:aws:s3:::da?a", "arn:aws:s3:::data"))
+	assert.False(t, wildcardMatch("arn:aws:s3:::data/*", "arn:aws:s3:::data"))
+	assert.True(t, wildcardMatch("*", ""))
+
+	assert.True(t, patternsOverlap("arn:aws:s3:::data/*", "arn:aws:s3:::data/logs/*"))
+	assert.True(t, patternsOverlap("arn:aws:s3:::data/logs/a", "arn:aws:s3:::data/*"))
+	assert.False(t, patternsOverlap("arn:aws:s3:::data/logs/*", "arn:aws:s3:::data/public/*"))
+}
+
+func TestPublicAccess(t *testing.T) {
+	document, err := ParsePolicy(`{"Statement": [
+		{"Sid": "All", "Effect": "Allow", "Principal": "*", "Action": "s3:*", "Resource": ["arn:aws:s3:::b", "arn:aws:s3:::b/*"]},
+		{"Sid": "NoWrite", "Effect": "Deny", "Principal": "*", "Action": ["s3:PutObject", "s3:DeleteObject"], "Resource": "arn:aws:s3:::b/*"},
+		{"Sid": "Other", "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::other/*"}
+	]}`)
+	require.NoError(t, err)
+
+	access := document.PublicAccess("b")
+	require.Len(t, access, 1)
+	assert.Equal(t, "", access[0].Prefix)
+	assert.Equal(t, []Access{AccessManage, AccessWrite, AccessRead, AccessList}, access[0].Access,
+		"write is still granted through actions the deny does not name")
+	assert.Equal(t, []string{"All"}, access[0].Statements)
+	assert.False(t, access[0].Conditional)
+
+	conflicts := document.Conflicts()
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "NoWrite", conflicts[0].Deny.Sid)
+}
+
+func TestPatternCovers(t *testing.T) {
+	assert.True(t, patternCovers("arn:aws:s3:::data/*", "arn:aws:s3:::data/public/*"))
+	assert.True(t, patternCovers("arn:aws:s3:::data/public/a.txt", "arn:aws:s3:::data/public/a.txt"))
+	assert.False(t, patternCovers("arn:aws:s3:::data/public/a.txt", "arn:aws:s3:::data/public/*"))
+	assert.False(t, patternCovers("arn:aws:s3:::data/private/*", "arn:aws:s3:::data/*"))
+	assert.False(t, patternCovers("arn:aws:s3:::data/*.secret", "arn:aws:s3:::data/*"))
+	assert.False(t, patternCovers("arn:aws:s3:::data/*/*", "arn:aws:s3:::data/*"))
+}
+
+func TestPublicAccessPartialDeny(t *testing.T) {
+	policy := `{"Statement": [
+		{"Sid": "Public", "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"},
+		{"Sid": "Secrets", "Effect": "Deny", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*.secret"}
+	]}`
+	document, err := ParsePolicy(policy)
+	require.NoError(t, err)
+
+	// The Deny only matches keys ending in .secret, so everything else stays readable
+	access := document.PublicAccess("data")
+	require.Len(t, access, 1)
+	assert.Equal(t, []Access{AccessRead}, access[0].Access)
+
+	byID := resultsByID(evaluatePolicy("data", policy))
+	assert.Equal(t, StatusWarn, byID[CheckPolicyAnonymousAccess].Status)
+	assert.Equal(t, StatusWarn, byID[CheckPolicyPublicPrefixes].Status)
+
+	// A blanket Deny written as "*" still overrides the Allow
+	document, err = ParsePolicy(`{"Statement": [
+		{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/public/*"},
+		{"Effect": "Deny", "Principal": "*", "Action": "s3:GetObject", "Resource": "*"}
+	]}`)
+	require.NoError(t, err)
+	assert.Empty(t, document.PublicAccess("data"))
+}
@@ -0,0 +1,345 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/replication"
+
+	"github.com/liamdn8/mc-tool/pkg/client"
+)
+
+// Replication statuses reported per object version
+const (
+	ReplicationCompleted = "COMPLETED"
+	ReplicationPending   = "PENDING"
+	ReplicationFailed    = "FAILED"
+	ReplicationReplica   = "REPLICA"
+)
+
+// fetchReplicationConfig reads the replication configuration; nil means none is configured
+func fetchReplicationConfig(ctx context.Context, client *minio.Client, bucketName string) (*replication.Config, error) {
+	config, err := client.GetBucketReplication(ctx, bucketName)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "ReplicationConfigurationNotFoundError" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if config.Empty() {
+		return nil, nil
+	}
+	return &config, nil
+}
+
+// ruleName identifies a rule by ID, or by position when it has none
+func ruleName(id string, index int) string {
+	if id != "" {
+		return id
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+// ruleScope is the set of objects a rule's prefix and tag filter select
+type ruleScope struct {
+	Prefix string
+	Tags   map[string]string
+}
+
+// overlaps reports whether two scopes can select the same object: their prefixes nest
+// and their tag filters don't require different values for the same key
+func (s ruleScope) overlaps(other ruleScope) bool {
+	if !strings.HasPrefix(s.Prefix, other.Prefix) && !strings.HasPrefix(other.Prefix, s.Prefix) {
+		return false
+	}
+	for key, value := range s.Tags {
+		if otherValue, ok := other.Tags[key]; ok && otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// replicationScope returns the objects a replication rule applies to
+func replicationScope(rule replication.Rule) ruleScope {
+	scope := ruleScope{Prefix: rule.Filter.Prefix, Tags: make(map[string]string)}
+	if scope.Prefix == "" {
+		scope.Prefix = rule.Filter.And.Prefix
+	}
+	if rule.Filter.Tag.Key != "" {
+		scope.Tags[rule.Filter.Tag.Key] = rule.Filter.Tag.Value
+	}
+	for _, tag := range rule.Filter.And.Tags {
+		scope.Tags[tag.Key] = tag.Value
+	}
+	return scope
+}
+
+// filtersOverlap reports whether two replication rules can apply to the same object
+func filtersOverlap(a, b replication.Rule) bool {
+	return replicationScope(a).overlaps(replicationScope(b))
+}
+
+// RemoteTargetsFunc lists the replication targets registered for a bucket
+type RemoteTargetsFunc func(ctx context.Context, bucket string) ([]client.RemoteTarget, error)
+
+// evaluateReplication checks a replication configuration; nil means none is configured.
+// Rule destinations are checked against the bucket's remote targets unless targets is nil.
+func evaluateReplication(bucketName string, config *replication.Config, versioning string, targets []client.RemoteTarget) []CheckResult {
+	if config == nil {
+		return []CheckResult{{
+			ID:       CheckReplicationConfigured,
+			Category: CategoryReplication,
+			Status:   StatusSkip,
+			Severity: SeverityInfo,
+			Message:  "Not configured",
+		}}
+	}
+
+	arns := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		arns[target.Arn] = true
+	}
+
+	var enabled []replication.Rule
+	var disabled, destinations, noDestination, unknownTarget []string
+	var noDeleteMarkers, noDeletes, noExisting []string
+	for i, rule := range config.Rules {
+		name := ruleName(rule.ID, i)
+		if rule.Status != replication.Enabled {
+			disabled = append(disabled, fmt.Sprintf("Rule '%s' is disabled", name))
+			continue
+		}
+		enabled = append(enabled, rule)
+
+		prefix := replicationScope(rule).Prefix
+		switch {
+		case rule.Destination.Bucket == "":
+			noDestination = append(noDestination, fmt.Sprintf("Rule '%s' has no destination bucket", name))
+		case targets != nil && !arns[rule.Destination.Bucket]:
+			unknownTarget = append(unknownTarget, fmt.Sprintf("Rule '%s' -> %s matches no remote target", name, rule.Destination.Bucket))
+		default:
+			destinations = append(destinations, fmt.Sprintf("Rule '%s' (prefix %q, priority %d) -> %s",
+				name, prefix, rule.Priority, rule.Destination.Bucket))
+		}
+		if rule.DeleteMarkerReplication.Status != replication.Enabled {
+			noDeleteMarkers = append(noDeleteMarkers, fmt.Sprintf("Rule '%s'", name))
+		}
+		if rule.DeleteReplication.Status != replication.Enabled {
+			noDeletes = append(noDeletes, fmt.Sprintf("Rule '%s'", name))
+		}
+		if rule.ExistingObjectReplication.Status != replication.Enabled {
+			noExisting = append(noExisting, fmt.Sprintf("Rule '%s'", name))
+		}
+	}
+
+	configured := CheckResult{
+		ID:       CheckReplicationConfigured,
+		Category: CategoryReplication,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  fmt.Sprintf("%d rules configured, %d enabled", len(config.Rules), len(enabled)),
+		Evidence: disabled,
+	}
+	if len(enabled) == 0 {
+		configured.Status = StatusWarn
+		configured.Severity = SeverityMedium
+		configured.Message = fmt.Sprintf("%d rules configured, none enabled", len(config.Rules))
+	}
+	results := []CheckResult{configured}
+
+	versioningResult := CheckResult{
+		ID:       CheckReplicationVersioning,
+		Category: CategoryReplication,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "Versioning is enabled as replication requires",
+	}
+	if versioning != "Enabled" {
+		versioningResult.Status = StatusFail
+		versioningResult.Severity = SeverityHigh
+		versioningResult.Message = "Replication requires versioning, which is not enabled"
+		versioningResult.Remediation = fmt.Sprintf("mc version enable <alias>/%s", bucketName)
+	}
+	results = append(results, versioningResult)
+
+	destination := CheckResult{
+		ID:       CheckReplicationDestination,
+		Category: CategoryReplication,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "Every enabled rule has a destination bucket",
+		Evidence: destinations,
+	}
+	if targets != nil {
+		destination.Message = "Every enabled rule points at a registered remote target"
+	}
+	if len(noDestination) > 0 || len(unknownTarget) > 0 {
+		var parts []string
+		if len(noDestination) > 0 {
+			parts = append(parts, fmt.Sprintf("%d have no destination bucket", len(noDestination)))
+		}
+		if len(unknownTarget) > 0 {
+			parts = append(parts, fmt.Sprintf("%d point at ARNs with no remote target", len(unknownTarget)))
+		}
+		destination.Status = StatusFail
+		destination.Severity = SeverityHigh
+		destination.Message = fmt.Sprintf("Enabled rules without a usable destination: %s", strings.Join(parts, ", "))
+		destination.Remediation = fmt.Sprintf("Point each rule at a target listed by mc admin bucket remote ls <alias>/%s: mc replicate update --remote-bucket <target> <alias>/%s", bucketName, bucketName)
+		destination.Evidence = append(append(noDestination, unknownTarget...), destinations...)
+	}
+	results = append(results, destination)
+
+	results = append(results,
+		ruleFlagResult(CheckReplicationDeleteMarkers, noDeleteMarkers, SeverityMedium,
+			"Delete markers are replicated",
+			"rules do not replicate delete markers; objects deleted at the source stay visible at the destination",
+			fmt.Sprintf("mc replicate update --replicate \"delete-marker,delete,existing-objects\" --id <rule> <alias>/%s", bucketName)),
+		ruleFlagResult(CheckReplicationDeletes, noDeletes, SeverityLow,
+			"Versioned deletes are replicated",
+			"rules do not replicate versioned deletes; removed versions remain at the destination",
+			fmt.Sprintf("mc replicate update --replicate \"delete-marker,delete\" --id <rule> <alias>/%s", bucketName)),
+		ruleFlagResult(CheckReplicationExistingObjects, noExisting, SeverityMedium,
+			"Existing objects are replicated",
+			"rules do not replicate existing objects; objects written before the rule are missing at the destination",
+			fmt.Sprintf("mc replicate update --replicate \"existing-objects\" --id <rule> <alias>/%s, then mc replicate resync start", bucketName)),
+	)
+
+	results = append(results, evaluateReplicationPriorities(config.Rules))
+	return results
+}
+
+// ruleFlagResult reports the enabled rules missing a replication flag
+func ruleFlagResult(id string, missing []string, severity Severity, passMessage, failMessage, remediation string) CheckResult {
+	result := CheckResult{
+		ID:       id,
+		Category: CategoryReplication,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  passMessage,
+	}
+	if len(missing) > 0 {
+		result.Status = StatusWarn
+		result.Severity = severity
+		result.Message = fmt.Sprintf("%d %s", len(missing), failMessage)
+		result.Remediation = remediation
+		result.Evidence = missing
+	}
+	return result
+}
+
+// evaluateReplicationPriorities finds enabled rules that apply to the same objects with
+// the same priority, where it is ambiguous which destination wins
+func evaluateReplicationPriorities(rules []replication.Rule) CheckResult {
+	result := CheckResult{
+		ID:       CheckReplicationPriorities,
+		Category: CategoryReplication,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "No overlapping rules share a priority",
+	}
+
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			a, b := rules[i], rules[j]
+			if a.Status != replication.Enabled || b.Status != replication.Enabled {
+				continue
+			}
+			if a.Priority == b.Priority && filtersOverlap(a, b) {
+				result.Evidence = append(result.Evidence, fmt.Sprintf("Rules '%s' and '%s' overlap with priority %d",
+					ruleName(a.ID, i), ruleName(b.ID, j), a.Priority))
+			}
+		}
+	}
+
+	if len(result.Evidence) > 0 {
+		result.Status = StatusFail
+		result.Severity = SeverityMedium
+		result.Message = fmt.Sprintf("%d pairs of overlapping rules share a priority", len(result.Evidence))
+		result.Remediation = "Give overlapping rules distinct priorities (mc replicate update --priority)"
+	}
+	return result
+}
+
+// ObjectReplication is the replication status of one object version
+type ObjectReplication struct {
+	Key       string
+	VersionID string
+	Status    string
+}
+
+// SampleReplicationStatus reads the replication status of up to limit current objects
+// (all objects when limit is not positive)
+func SampleReplicationStatus(ctx context.Context, client *minio.Client, bucketName string, limit int) ([]ObjectReplication, error) {
+	var sample []ObjectReplication
+	err := statObjects(ctx, client, bucketName, limit, func(key string, info minio.ObjectInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to stat object %s: %w", key, err)
+		}
+		sample = append(sample, ObjectReplication{Key: info.Key, VersionID: info.VersionID, Status: info.ReplicationStatus})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sample, nil
+}
+
+// evaluateReplicationSample counts sampled objects per replication status. Failed
+// replicas fail the check; pending ones only warn since they may still complete.
+func evaluateReplicationSample(sample []ObjectReplication) CheckResult {
+	result := CheckResult{
+		ID:       CheckReplicationStatus,
+		Category: CategoryReplication,
+		Status:   StatusSkip,
+		Severity: SeverityInfo,
+		Message:  "No objects to sample",
+	}
+	if len(sample) == 0 {
+		return result
+	}
+
+	counts := make(map[string]int)
+	var failed, pending []string
+	for _, object := range sample {
+		status := object.Status
+		if status == "" {
+			status = "NONE"
+		}
+		counts[status]++
+		switch status {
+		case ReplicationFailed:
+			failed = append(failed, object.Key+": FAILED")
+		case ReplicationPending:
+			pending = append(pending, object.Key+": PENDING")
+		}
+	}
+
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = fmt.Sprintf("%d %s", counts[status], status)
+	}
+
+	result.Message = fmt.Sprintf("Sampled %d objects: %s", len(sample), strings.Join(parts, ", "))
+	result.Status = StatusPass
+	switch {
+	case len(failed) > 0:
+		result.Status = StatusFail
+		result.Severity = SeverityHigh
+		result.Remediation = "Check the replication target and errors (mc replicate status), then retry with mc replicate resync start"
+	case len(pending) > 0:
+		result.Status = StatusWarn
+		result.Severity = SeverityLow
+		result.Remediation = "Pending replicas may still complete; re-run later or watch mc replicate status"
+	}
+	result.Evidence = append(firstN(failed, maxExamples), firstN(pending, maxExamples)...)
+	return result
+}
@@ -0,0 +1,150 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/replication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liamdn8/mc-tool/pkg/client"
+)
+
+func replicationRule(id, prefix string, priority int, flags ...string) replication.Rule {
+	rule := replication.Rule{
+		ID:          id,
+		Status:      replication.Enabled,
+		Priority:    priority,
+		Filter:      replication.Filter{Prefix: prefix},
+		Destination: replication.Destination{Bucket: "arn:minio:replication::target:" + id},
+	}
+	for _, flag := range flags {
+		switch flag {
+		case "delete-marker":
+			rule.DeleteMarkerReplication.Status = replication.Enabled
+		case "delete":
+			rule.DeleteReplication.Status = replication.Enabled
+		case "existing-objects":
+			rule.ExistingObjectReplication.Status = replication.Enabled
+		}
+	}
+	return rule
+}
+
+func TestEvaluateReplicationNotConfigured(t *testing.T) {
+	results := evaluateReplication("data", nil, "Enabled", nil)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSkip, results[0].Status)
+}
+
+func TestEvaluateReplicationHealthy(t *testing.T) {
+	config := &replication.Config{Rules: []replication.Rule{
+		replicationRule("all", "", 1, "delete-marker", "delete", "existing-objects"),
+		replicationRule("logs", "logs/", 2, "delete-marker", "delete", "existing-objects"),
+	}}
+
+	results := evaluateReplication("data", config, "Enabled", nil)
+	require.Len(t, results, 7)
+	for _, result := range results {
+		assert.Equal(t, StatusPass, result.Status, result.ID)
+	}
+	assert.Equal(t, "2 rules configured, 2 enabled", results[0].Message)
+	assert.Equal(t, `Rule 'logs' (prefix "logs/", priority 2) -> arn:minio:replication::target:logs`,
+		resultsByID(results)[CheckReplicationDestination].Evidence[1])
+}
+
+func TestEvaluateReplicationRemoteTargets(t *testing.T) {
+	config := &replication.Config{Rules: []replication.Rule{
+		replicationRule("all", "", 1, "delete-marker", "delete", "existing-objects"),
+		replicationRule("logs", "logs/", 2, "delete-marker", "delete", "existing-objects"),
+	}}
+	targets := []client.RemoteTarget{{Arn: "arn:minio:replication::target:all", TargetBucket: "data-dr"}}
+
+	destination := resultsByID(evaluateReplication("data", config, "Enabled", targets))[CheckReplicationDestination]
+	assert.Equal(t, StatusFail, destination.Status)
+	assert.Equal(t, "Enabled rules without a usable destination: 1 point at ARNs with no remote target", destination.Message)
+	assert.Equal(t, "Rule 'logs' -> arn:minio:replication::target:logs matches no remote target", destination.Evidence[0])
+
+	targets = append(targets, client.RemoteTarget{Arn: "arn:minio:replication::target:logs"})
+	destination = resultsByID(evaluateReplication("data", config, "Enabled", targets))[CheckReplicationDestination]
+	assert.Equal(t, StatusPass, destination.Status)
+	assert.Equal(t, "Every enabled rule points at a registered remote target", destination.Message)
+
+	// A bucket without registered targets fails every rule
+	destination = resultsByID(evaluateReplication("data", config, "Enabled", []client.RemoteTarget{}))[CheckReplicationDestination]
+	assert.Len(t, destination.Evidence, 2)
+	assert.Equal(t, StatusFail, destination.Status)
+}
+
+func TestEvaluateReplicationProblems(t *testing.T) {
+	disabled := replicationRule("old", "", 5)
+	disabled.Status = replication.Disabled
+	noDestination := replicationRule("broken", "tmp/", 3, "delete-marker")
+	noDestination.Destination.Bucket = ""
+
+	config := &replication.Config{Rules: []replication.Rule{
+		replicationRule("all", "", 1, "delete-marker"),
+		replicationRule("logs", "logs/", 1),
+		noDestination,
+		disabled,
+	}}
+
+	byID := resultsByID(evaluateReplication("data", config, "Suspended", nil))
+
+	assert.Equal(t, []string{"Rule 'old' is disabled"}, byID[CheckReplicationConfigured].Evidence)
+	assert.Equal(t, StatusFail, byID[CheckReplicationVersioning].Status)
+	assert.Equal(t, StatusFail, byID[CheckReplicationDestination].Status)
+	assert.Equal(t, "Rule 'broken' has no destination bucket", byID[CheckReplicationDestination].Evidence[0])
+
+	assert.Equal(t, StatusWarn, byID[CheckReplicationDeleteMarkers].Status)
+	assert.Equal(t, []string{"Rule 'logs'"}, byID[CheckReplicationDeleteMarkers].Evidence)
+	assert.Equal(t, []string{"Rule 'all'", "Rule 'logs'", "Rule 'broken'"}, byID[CheckReplicationExistingObjects].Evidence)
+
+	priorities := byID[CheckReplicationPriorities]
+	assert.Equal(t, StatusFail, priorities.Status)
+	assert.Equal(t, []string{"Rules 'all' and 'logs' overlap with priority 1"}, priorities.Evidence)
+}
+
+func TestFiltersOverlap(t *testing.T) {
+	tagged := func(prefix, key, value string) replication.Rule {
+		return replication.Rule{Filter: replication.Filter{And: replication.And{
+			Prefix: prefix, Tags: []replication.Tag{{Key: key, Value: value}},
+		}}}
+	}
+
+	assert.True(t, filtersOverlap(replicationRule("a", "", 1), replicationRule("b", "logs/", 1)))
+	assert.False(t, filtersOverlap(replicationRule("a", "logs/", 1), replicationRule("b", "data/", 1)))
+	assert.True(t, filtersOverlap(tagged("logs/", "team", "a"), tagged("logs/2024/", "env", "prod")))
+	assert.False(t, filtersOverlap(tagged("", "team", "a"), tagged("", "team", "b")))
+}
+
+func TestEvaluateReplicationSample(t *testing.T) {
+	result := evaluateReplicationSample(nil)
+	assert.Equal(t, StatusSkip, result.Status)
+
+	sample := []ObjectReplication{
+		{Key: "a", Status: ReplicationCompleted},
+		{Key: "b", Status: ReplicationCompleted},
+		{Key: "c", Status: ReplicationPending},
+		{Key: "d", Status: ""},
+	}
+	result = evaluateReplicationSample(sample)
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, "Sampled 4 objects: 2 COMPLETED, 1 NONE, 1 PENDING", result.Message)
+	assert.Equal(t, []string{"c: PENDING"}, result.Evidence)
+
+	sample = append(sample, ObjectReplication{Key: "e", Status: ReplicationFailed})
+	result = evaluateReplicationSample(sample)
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, SeverityHigh, result.Severity)
+	assert.Equal(t, []string{"e: FAILED", "c: PENDING"}, result.Evidence)
+
+	var completed []ObjectReplication
+	for i := 0; i < 3; i++ {
+		completed = append(completed, ObjectReplication{Key: fmt.Sprintf("k%d", i), Status: ReplicationCompleted})
+	}
+	result = evaluateReplicationSample(completed)
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Empty(t, result.Evidence)
+}
@@ -0,0 +1,162 @@
+package validation
+
+import "fmt"
+
+// Status is the outcome of a check
+type Status string
+
+// Check statuses
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Severity ranks how much a non-passing check matters
+type Severity string
+
+// Check severities
+const (
+	SeverityInfo   Severity = "info"
+	SeverityLow    Severity = "low"
+	SeverityMedium Severity = "medium"
+	SeverityHigh   Severity = "high"
+)
+
+// Check categories
+const (
+	CategoryBucket       = "Bucket"
+	CategoryVersioning   = "Versioning"
+	CategoryNotification = "Event Notifications"
+	CategoryLifecycle    = "Object Lifecycle"
+	CategoryEncryption   = "Server-side Encryption"
+	CategoryPolicy       = "Bucket Policy"
+	CategoryObjectLock   = "Object Lock"
+	CategoryReplication  = "Replication"
+	CategoryBaseline     = "Baseline"
+)
+
+// Check IDs
+const (
+	CheckBucketExists            = "bucket.exists"
+	CheckVersioningEnabled       = "versioning.enabled"
+	CheckNotificationConfigured  = "notification.configured"
+	CheckLifecycleConfigured     = "lifecycle.configured"
+	CheckLifecycleAbortUploads   = "lifecycle.abort-incomplete-uploads"
+	CheckEncryptionDefault       = "encryption.default"
+	CheckPolicyConfigured        = "policy.configured"
+	CheckPolicyWildcardActions   = "policy.wildcard-actions"
+	CheckPolicyWildcardResources = "policy.wildcard-resources"
+	CheckPolicyAnonymousAccess   = "policy.anonymous-access"
+	CheckPolicyPublicPrefixes    = "policy.public-prefixes"
+	CheckPolicyConflicts         = "policy.allow-deny-conflicts"
+	CheckPolicyConditions        = "policy.conditions"
+
+	CheckEncryptionKMSKey  = "encryption.kms-key"
+	CheckEncryptionObjects = "encryption.objects"
+
+	CheckNotificationTargets  = "notification.targets"
+	CheckNotificationOverlaps = "notification.overlaps"
+	CheckNotificationDelivery = "notification.delivery"
+
+	CheckLifecycleDisabledRules        = "lifecycle.disabled-rules"
+	CheckLifecycleConflicts            = "lifecycle.conflicts"
+	CheckLifecycleNoncurrentExpiration = "lifecycle.noncurrent-expiration"
+	CheckLifecycleDeleteMarkers        = "lifecycle.expired-delete-markers"
+	CheckLifecycleTransitionTiers      = "lifecycle.transition-tiers"
+	CheckLifecycleRetention            = "lifecycle.object-lock-retention"
+
+	CheckObjectLockEnabled          = "objectlock.enabled"
+	CheckObjectLockDefaultRetention = "objectlock.default-retention"
+	CheckObjectLockObjectRetention  = "objectlock.object-retention"
+	CheckObjectLockLegalHolds       = "objectlock.legal-holds"
+
+	CheckReplicationConfigured      = "replication.configured"
+	CheckReplicationVersioning      = "replication.versioning"
+	CheckReplicationDestination     = "replication.destination"
+	CheckReplicationDeleteMarkers   = "replication.delete-markers"
+	CheckReplicationDeletes         = "replication.deletes"
+	CheckReplicationExistingObjects = "replication.existing-objects"
+	CheckReplicationPriorities      = "replication.priorities"
+	CheckReplicationStatus          = "replication.status"
+
+	CheckBaselineMatched              = "baseline.matched"
+	CheckBaselineVersioning           = "baseline.versioning"
+	CheckBaselineEncryption           = "baseline.encryption"
+	CheckBaselineKMSKey               = "baseline.kms-key"
+	CheckBaselineNoncurrentExpiration = "baseline.noncurrent-expiration"
+	CheckBaselineAbortUploads         = "baseline.abort-incomplete-uploads"
+	CheckBaselinePublicPolicy         = "baseline.public-policy"
+	CheckBaselineObjectLock           = "baseline.object-lock"
+	CheckBaselineRetentionMode        = "baseline.retention-mode"
+	CheckBaselineRetentionPeriod      = "baseline.retention-period"
+)
+
+// CheckResult is the outcome of a single bucket check
+type CheckResult struct {
+	ID          string   `json:"id" yaml:"id"`
+	Category    string   `json:"category" yaml:"category"`
+	Status      Status   `json:"status" yaml:"status"`
+	Severity    Severity `json:"severity" yaml:"severity"`
+	Message     string   `json:"message" yaml:"message"`
+	Remediation string   `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	Evidence    []string `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+}
+
+// Report collects the check results of a bucket
+type Report struct {
+	Bucket  string        `json:"bucket" yaml:"bucket"`
+	Results []CheckResult `json:"results" yaml:"results"`
+}
+
+// Add appends results to the report
+func (r *Report) Add(results ...CheckResult) {
+	r.Results = append(r.Results, results...)
+}
+
+// Count returns the number of results with the given status
+func (r *Report) Count(status Status) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Result returns the result of a check, if it ran
+func (r *Report) Result(id string) (CheckResult, bool) {
+	for _, result := range r.Results {
+		if result.ID == id {
+			return result, true
+		}
+	}
+	return CheckResult{}, false
+}
+
+// ParseFailOn validates a --fail-on policy: "", "warn" or "fail"
+func ParseFailOn(failOn string) (Status, error) {
+	switch Status(failOn) {
+	case "":
+		return "", nil
+	case StatusWarn, StatusFail:
+		return Status(failOn), nil
+	default:
+		return "", fmt.Errorf("invalid fail-on policy: %s (expected warn or fail)", failOn)
+	}
+}
+
+// Failed reports whether the report violates a fail-on policy. With StatusWarn both
+// warnings and failures count; with StatusFail only failures; an empty policy never fails.
+func (r *Report) Failed(failOn Status) bool {
+	switch failOn {
+	case StatusWarn:
+		return r.Count(StatusWarn) > 0 || r.Count(StatusFail) > 0
+	case StatusFail:
+		return r.Count(StatusFail) > 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportCountAndResult(t *testing.T) {
+	report := &Report{Bucket: "data"}
+	report.Add(
+		CheckResult{ID: CheckBucketExists, Status: StatusPass},
+		CheckResult{ID: CheckVersioningEnabled, Status: StatusWarn},
+		CheckResult{ID: CheckPolicyConfigured, Status: StatusSkip},
+	)
+
+	assert.Equal(t, 1, report.Count(StatusPass))
+	assert.Equal(t, 1, report.Count(StatusWarn))
+	assert.Equal(t, 0, report.Count(StatusFail))
+
+	result, ok := report.Result(CheckVersioningEnabled)
+	require.True(t, ok)
+	assert.Equal(t, StatusWarn, result.Status)
+
+	_, ok = report.Result(CheckEncryptionDefault)
+	assert.False(t, ok)
+}
+
+func TestReportFailed(t *testing.T) {
+	warning := &Report{Results: []CheckResult{{Status: StatusPass}, {Status: StatusWarn}}}
+	failure := &Report{Results: []CheckResult{{Status: StatusFail}}}
+	clean := &Report{Results: []CheckResult{{Status: StatusPass}, {Status: StatusSkip}}}
+
+	assert.False(t, warning.Failed(""))
+	assert.True(t, warning.Failed(StatusWarn))
+	assert.False(t, warning.Failed(StatusFail))
+	assert.True(t, failure.Failed(StatusWarn))
+	assert.True(t, failure.Failed(StatusFail))
+	assert.False(t, clean.Failed(StatusWarn))
+}
+
+func TestParseFailOn(t *testing.T) {
+	failOn, err := ParseFailOn("warn")
+	require.NoError(t, err)
+	assert.Equal(t, StatusWarn, failOn)
+
+	failOn, err = ParseFailOn("")
+	require.NoError(t, err)
+	assert.Equal(t, Status(""), failOn)
+
+	_, err = ParseFailOn("pass")
+	assert.Error(t, err)
+}
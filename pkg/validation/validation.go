@@ -4,132 +4,602 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/minio/minio-go/v7/pkg/replication"
+	"github.com/minio/minio-go/v7/pkg/sse"
+
+	"github.com/liamdn8/mc-tool/pkg/client"
 )
 
+// BucketConfig is the configuration of a bucket as read from the server
+type BucketConfig struct {
+	Bucket          string
+	Versioning      minio.BucketVersioningConfiguration
+	VersioningErr   error
+	Notification    notification.Configuration
+	NotificationErr error
+	Lifecycle       *lifecycle.Configuration
+	Encryption      *sse.Configuration
+	Policy          string
+	ObjectLock      ObjectLockConfig
+	ObjectLockErr   error
+	Replication     *replication.Config
+	ReplicationErr  error
+	// Tiers names the server's remote tiers; nil when they could not be listed
+	Tiers []string
+	// RemoteTargets are the bucket's registered replication targets; nil when they could
+	// not be listed
+	RemoteTargets []client.RemoteTarget
+}
+
+// CheckOptions selects the optional parts of a bucket check
+type CheckOptions struct {
+	// Baseline adds the requirements of matching baseline rules when set
+	Baseline *Baseline
+	// ScanRetention samples objects for retention and legal holds
+	ScanRetention bool
+	// MinRetention is the retention every scanned object must have left; when zero the
+	// strictest min_retention_days of the matching baseline rules applies
+	MinRetention time.Duration
+	// KMSKeyStatus verifies the default encryption KMS key when set
+	KMSKeyStatus KMSKeyStatusFunc
+	// SampleEncryption samples how objects are encrypted
+	SampleEncryption bool
+	// TestEvents writes and deletes probe objects to confirm notification events fire
+	TestEvents bool
+	// SampleReplication samples the replication status of objects
+	SampleReplication bool
+	// Tiers names the server's remote tiers that lifecycle transitions are checked
+	// against; nil skips that check
+	Tiers []string
+	// RemoteTargets lists the replication targets that rule destinations are checked
+	// against when set
+	RemoteTargets RemoteTargetsFunc
+	// SampleSize limits how many objects object scans inspect; zero scans every object
+	SampleSize int
+}
+
+// FetchBucketConfig reads the configuration checked by the checklist. Missing lifecycle,
+// encryption and policy configurations are left empty rather than reported as errors.
+func FetchBucketConfig(ctx context.Context, client *minio.Client, bucketName string) *BucketConfig {
+	cfg := &BucketConfig{Bucket: bucketName}
+
+	cfg.Versioning, cfg.VersioningErr = client.GetBucketVersioning(ctx, bucketName)
+	cfg.Notification, cfg.NotificationErr = client.GetBucketNotification(ctx, bucketName)
+
+	if lifecycleConfig, err := client.GetBucketLifecycle(ctx, bucketName); err == nil {
+		cfg.Lifecycle = lifecycleConfig
+	}
+	if encryption, err := client.GetBucketEncryption(ctx, bucketName); err == nil {
+		cfg.Encryption = encryption
+	}
+	if policy, err := client.GetBucketPolicy(ctx, bucketName); err == nil {
+		cfg.Policy = policy
+	}
+	cfg.ObjectLock, cfg.ObjectLockErr = fetchObjectLockConfig(ctx, client, bucketName)
+	cfg.Replication, cfg.ReplicationErr = fetchReplicationConfig(ctx, client, bucketName)
+
+	return cfg
+}
+
 // CheckBucketConfiguration performs comprehensive bucket configuration validation
-func CheckBucketConfiguration(ctx context.Context, client *minio.Client, bucketName string) error {
-	fmt.Printf("Checking bucket: %s\n\n", bucketName)
+func CheckBucketConfiguration(ctx context.Context, client *minio.Client, bucketName string, opts CheckOptions) (*Report, error) {
+	report := &Report{Bucket: bucketName}
 
 	// Check if bucket exists
 	exists, err := client.BucketExists(ctx, bucketName)
 	if err != nil {
-		return fmt.Errorf("failed to check bucket existence: %w", err)
+		return nil, fmt.Errorf("failed to check bucket existence: %w", err)
 	}
 	if !exists {
-		return fmt.Errorf("bucket %s does not exist", bucketName)
+		report.Add(CheckResult{
+			ID:       CheckBucketExists,
+			Category: CategoryBucket,
+			Status:   StatusFail,
+			Severity: SeverityHigh,
+			Message:  fmt.Sprintf("Bucket %s does not exist", bucketName),
+		})
+		return report, nil
+	}
+
+	report.Add(CheckResult{
+		ID:       CheckBucketExists,
+		Category: CategoryBucket,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "Exists",
+	})
+
+	cfg := FetchBucketConfig(ctx, client, bucketName)
+	cfg.Tiers = opts.Tiers
+	if cfg.Replication != nil && opts.RemoteTargets != nil {
+		if targets, err := opts.RemoteTargets(ctx, bucketName); err == nil {
+			cfg.RemoteTargets = targets
+		}
+	}
+	report.Add(EvaluateBucketConfig(cfg)...)
+	if keyID, ok := defaultKMSKey(cfg.Encryption); ok && opts.KMSKeyStatus != nil {
+		status, err := opts.KMSKeyStatus(ctx, keyID)
+		report.Add(evaluateKMSKey(keyID, status, err))
+	}
+	if opts.Baseline != nil {
+		report.Add(opts.Baseline.Evaluate(cfg)...)
+	}
+
+	if opts.TestEvents && cfg.NotificationErr == nil {
+		if reason := probeSkipReason(cfg); reason != "" {
+			report.Add(CheckResult{
+				ID:       CheckNotificationDelivery,
+				Category: CategoryNotification,
+				Status:   StatusSkip,
+				Severity: SeverityInfo,
+				Message:  fmt.Sprintf("Probe objects not written: %s", reason),
+			})
+		} else {
+			probes, err := ProbeNotifications(ctx, client, bucketName, cfg.Notification, ProbeTimeout)
+			if err != nil {
+				report.Add(retrievalFailure(CheckNotificationDelivery, CategoryNotification, err))
+			} else {
+				report.Add(evaluateNotificationProbes(probes, ProbeTimeout))
+			}
+		}
+	}
+
+	if opts.ScanRetention {
+		minRetention := opts.MinRetention
+		if minRetention == 0 && opts.Baseline != nil {
+			minRetention = opts.Baseline.MinRetention(bucketName)
+		}
+		retention, err := ScanRetention(ctx, client, bucketName, opts.SampleSize)
+		if err != nil {
+			report.Add(retrievalFailure(CheckObjectLockObjectRetention, CategoryObjectLock, err))
+		} else {
+			report.Add(evaluateRetentionScan(bucketName, cfg.ObjectLock, retention, time.Now(), minRetention)...)
+		}
+	}
+
+	if opts.SampleEncryption {
+		objects, err := ScanEncryption(ctx, client, bucketName, opts.SampleSize)
+		if err != nil {
+			report.Add(retrievalFailure(CheckEncryptionObjects, CategoryEncryption, err))
+		} else {
+			report.Add(evaluateEncryptionSample(objects, cfg.Encryption))
+		}
 	}
 
-	fmt.Println("✅ Bucket exists")
+	if opts.SampleReplication {
+		sample, err := SampleReplicationStatus(ctx, client, bucketName, opts.SampleSize)
+		if err != nil {
+			report.Add(retrievalFailure(CheckReplicationStatus, CategoryReplication, err))
+		} else {
+			report.Add(evaluateReplicationSample(sample))
+		}
+	}
+
+	return report, nil
+}
+
+// EvaluateBucketConfig runs the built-in checks against a bucket configuration
+func EvaluateBucketConfig(cfg *BucketConfig) []CheckResult {
+	var results []CheckResult
 
 	// Check versioning configuration
-	checkVersioningConfig(ctx, client, bucketName)
+	if cfg.VersioningErr != nil {
+		results = append(results, retrievalFailure(CheckVersioningEnabled, CategoryVersioning, cfg.VersioningErr))
+	} else {
+		results = append(results, evaluateVersioning(cfg.Bucket, cfg.Versioning))
+	}
 
 	// Check notification configuration
-	checkNotificationConfig(ctx, client, bucketName)
+	if cfg.NotificationErr != nil {
+		results = append(results, retrievalFailure(CheckNotificationConfigured, CategoryNotification, cfg.NotificationErr))
+	} else {
+		results = append(results, evaluateNotification(cfg.Notification))
+		results = append(results, evaluateNotificationTargets(notificationTargets(cfg.Notification))...)
+	}
 
 	// Check lifecycle configuration
-	checkLifecycleConfig(ctx, client, bucketName)
+	results = append(results, evaluateLifecycle(cfg.Lifecycle)...)
+	results = append(results, evaluateLifecycleRules(cfg)...)
 
 	// Check encryption configuration
-	checkEncryptionConfig(ctx, client, bucketName)
+	results = append(results, evaluateEncryption(cfg.Bucket, cfg.Encryption))
 
 	// Check bucket policy
-	checkBucketPolicyConfig(ctx, client, bucketName)
+	results = append(results, evaluatePolicy(cfg.Bucket, cfg.Policy)...)
+
+	// Check object lock configuration
+	if cfg.ObjectLockErr != nil {
+		results = append(results, retrievalFailure(CheckObjectLockEnabled, CategoryObjectLock, cfg.ObjectLockErr))
+	} else {
+		results = append(results, evaluateObjectLock(cfg.Bucket, cfg.ObjectLock)...)
+	}
+
+	// Check replication configuration
+	if cfg.ReplicationErr != nil {
+		results = append(results, retrievalFailure(CheckReplicationConfigured, CategoryReplication, cfg.ReplicationErr))
+	} else {
+		results = append(results, evaluateReplication(cfg.Bucket, cfg.Replication, cfg.Versioning.Status, cfg.RemoteTargets)...)
+	}
+
+	return results
+}
+
+// statObjects stats up to limit current objects (all objects when limit is not positive),
+// passing each stat result to visit; an error from visit stops the scan
+func statObjects(ctx context.Context, client *minio.Client, bucketName string, limit int, visit func(key string, info minio.ObjectInfo, err error) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	count := 0
+	for object := range client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list objects: %w", object.Err)
+		}
+		if limit > 0 && count >= limit {
+			break
+		}
+		count++
+
+		info, err := client.StatObject(ctx, bucketName, object.Key, minio.StatObjectOptions{})
+		if err := visit(object.Key, info, err); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func checkVersioningConfig(ctx context.Context, client *minio.Client, bucketName string) {
-	versioningConfig, err := client.GetBucketVersioning(ctx, bucketName)
-	if err != nil {
-		fmt.Printf("❌ Versioning: Failed to retrieve configuration - %v\n", err)
-		return
+// retrievalFailure reports a configuration that could not be read
+func retrievalFailure(id, category string, err error) CheckResult {
+	return CheckResult{
+		ID:       id,
+		Category: category,
+		Status:   StatusFail,
+		Severity: SeverityMedium,
+		Message:  fmt.Sprintf("Failed to retrieve configuration - %v", err),
+	}
+}
+
+func evaluateVersioning(bucketName string, versioningConfig minio.BucketVersioningConfiguration) CheckResult {
+	result := CheckResult{
+		ID:       CheckVersioningEnabled,
+		Category: CategoryVersioning,
 	}
 
 	if versioningConfig.Status == "Enabled" {
-		fmt.Println("✅ Versioning: Enabled")
+		result.Status = StatusPass
+		result.Severity = SeverityInfo
+		result.Message = "Enabled"
 	} else {
-		fmt.Println("⚠️  Versioning: Disabled - Consider enabling for data protection")
+		result.Status = StatusWarn
+		result.Severity = SeverityMedium
+		result.Message = "Disabled"
+		result.Remediation = fmt.Sprintf("Enable versioning for data protection: mc version enable <alias>/%s", bucketName)
 	}
+
+	return result
 }
 
-func checkNotificationConfig(ctx context.Context, client *minio.Client, bucketName string) {
-	notification, err := client.GetBucketNotification(ctx, bucketName)
-	if err != nil {
-		fmt.Printf("❌ Event Notifications: Failed to retrieve configuration - %v\n", err)
-		return
+func evaluateNotification(notificationConfig notification.Configuration) CheckResult {
+	result := CheckResult{
+		ID:       CheckNotificationConfigured,
+		Category: CategoryNotification,
+		Severity: SeverityInfo,
 	}
 
-	totalConfigs := len(notification.LambdaConfigs) + len(notification.TopicConfigs) + len(notification.QueueConfigs)
+	totalConfigs := len(notificationConfig.LambdaConfigs) + len(notificationConfig.TopicConfigs) + len(notificationConfig.QueueConfigs)
 
 	if totalConfigs == 0 {
-		fmt.Println("➖ Event Notifications: Not configured")
-	} else {
-		fmt.Printf("✅ Event Notifications: %d configurations found\n", totalConfigs)
-		if len(notification.LambdaConfigs) > 0 {
-			fmt.Printf("   - Lambda configurations: %d\n", len(notification.LambdaConfigs))
-		}
-		if len(notification.TopicConfigs) > 0 {
-			fmt.Printf("   - Topic configurations: %d\n", len(notification.TopicConfigs))
-		}
-		if len(notification.QueueConfigs) > 0 {
-			fmt.Printf("   - Queue configurations: %d\n", len(notification.QueueConfigs))
-		}
+		result.Status = StatusSkip
+		result.Message = "Not configured"
+		return result
 	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%d configurations found", totalConfigs)
+	if len(notificationConfig.LambdaConfigs) > 0 {
+		result.Evidence = append(result.Evidence, fmt.Sprintf("Lambda configurations: %d", len(notificationConfig.LambdaConfigs)))
+	}
+	if len(notificationConfig.TopicConfigs) > 0 {
+		result.Evidence = append(result.Evidence, fmt.Sprintf("Topic configurations: %d", len(notificationConfig.TopicConfigs)))
+	}
+	if len(notificationConfig.QueueConfigs) > 0 {
+		result.Evidence = append(result.Evidence, fmt.Sprintf("Queue configurations: %d", len(notificationConfig.QueueConfigs)))
+	}
+
+	return result
 }
 
-func checkLifecycleConfig(ctx context.Context, client *minio.Client, bucketName string) {
-	lifecycle, err := client.GetBucketLifecycle(ctx, bucketName)
-	if err != nil {
-		fmt.Println("➖ Object Lifecycle: Not configured")
-		return
+// evaluateLifecycle checks a lifecycle configuration; nil means none is configured
+func evaluateLifecycle(lifecycleConfig *lifecycle.Configuration) []CheckResult {
+	configured := CheckResult{
+		ID:       CheckLifecycleConfigured,
+		Category: CategoryLifecycle,
+		Severity: SeverityInfo,
+	}
+	abortUploads := CheckResult{
+		ID:       CheckLifecycleAbortUploads,
+		Category: CategoryLifecycle,
+		Status:   StatusWarn,
+		Severity: SeverityLow,
+		Message:  "No rule aborts incomplete multipart uploads",
+		Remediation: "Consider adding a rule with AbortIncompleteMultipartUpload " +
+			"(e.g. DaysAfterInitiation 7) to reclaim space from stale uploads",
 	}
 
-	fmt.Printf("✅ Object Lifecycle: %d rules configured\n", len(lifecycle.Rules))
+	if lifecycleConfig == nil || len(lifecycleConfig.Rules) == 0 {
+		configured.Status = StatusSkip
+		configured.Message = "Not configured"
+		return []CheckResult{configured, abortUploads}
+	}
 
-	hasIncompleteUploadRule := false
+	configured.Status = StatusPass
+	configured.Message = fmt.Sprintf("%d rules configured", len(lifecycleConfig.Rules))
 
-	for _, rule := range lifecycle.Rules {
-		fmt.Printf("   - Rule '%s': %s\n", rule.ID, rule.Status)
+	for _, rule := range lifecycleConfig.Rules {
+		configured.Evidence = append(configured.Evidence, fmt.Sprintf("Rule '%s': %s", rule.ID, rule.Status))
 		if rule.AbortIncompleteMultipartUpload.DaysAfterInitiation > 0 {
-			hasIncompleteUploadRule = true
+			abortUploads.Status = StatusPass
+			abortUploads.Severity = SeverityInfo
+			abortUploads.Message = fmt.Sprintf("Rule '%s' aborts incomplete uploads after %d days",
+				rule.ID, rule.AbortIncompleteMultipartUpload.DaysAfterInitiation)
+			abortUploads.Remediation = ""
 		}
 	}
 
-	if !hasIncompleteUploadRule {
-		fmt.Println("   💡 Consider adding rules to abort incomplete multipart uploads")
+	return []CheckResult{configured, abortUploads}
+}
+
+// evaluateEncryption checks default encryption; nil means none is configured
+func evaluateEncryption(bucketName string, encryption *sse.Configuration) CheckResult {
+	result := CheckResult{
+		ID:       CheckEncryptionDefault,
+		Category: CategoryEncryption,
+	}
+
+	if encryption == nil || len(encryption.Rules) == 0 {
+		result.Status = StatusWarn
+		result.Severity = SeverityHigh
+		result.Message = "Not configured"
+		result.Remediation = fmt.Sprintf("Enable default encryption for data security: mc encrypt set sse-s3 <alias>/%s", bucketName)
+		return result
 	}
+
+	rule := encryption.Rules[0]
+	result.Status = StatusPass
+	result.Severity = SeverityInfo
+	result.Message = fmt.Sprintf("%s configured", rule.Apply.SSEAlgorithm)
+	if rule.Apply.KmsMasterKeyID != "" {
+		result.Message = fmt.Sprintf("%s configured with KMS key %s", rule.Apply.SSEAlgorithm, rule.Apply.KmsMasterKeyID)
+	}
+	return result
 }
 
-func checkEncryptionConfig(ctx context.Context, client *minio.Client, bucketName string) {
-	encryption, err := client.GetBucketEncryption(ctx, bucketName)
+// evaluatePolicy checks a bucket policy document; an empty policy means none is configured
+func evaluatePolicy(bucketName, policy string) []CheckResult {
+	if policy == "" {
+		return []CheckResult{{
+			ID:       CheckPolicyConfigured,
+			Category: CategoryPolicy,
+			Status:   StatusSkip,
+			Severity: SeverityInfo,
+			Message:  "Not configured",
+		}}
+	}
+
+	document, err := ParsePolicy(policy)
 	if err != nil {
-		fmt.Println("⚠️  Server-side Encryption: Not configured - Consider enabling for data security")
-		return
+		return []CheckResult{{
+			ID:          CheckPolicyConfigured,
+			Category:    CategoryPolicy,
+			Status:      StatusFail,
+			Severity:    SeverityMedium,
+			Message:     fmt.Sprintf("Policy could not be analyzed - %v", err),
+			Remediation: fmt.Sprintf("Inspect the policy with: mc anonymous get-json <alias>/%s", bucketName),
+		}}
 	}
 
-	if len(encryption.Rules) > 0 {
-		rule := encryption.Rules[0]
-		fmt.Printf("✅ Server-side Encryption: %s configured\n", rule.Apply.SSEAlgorithm)
+	results := []CheckResult{{
+		ID:       CheckPolicyConfigured,
+		Category: CategoryPolicy,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  fmt.Sprintf("Configured (%d statements)", len(document.Statement)),
+	}}
+
+	results = append(results, evaluateAnonymousAccess(bucketName, document)...)
+	results = append(results, evaluateWildcards(document)...)
+
+	if conflicts := document.Conflicts(); len(conflicts) > 0 {
+		result := CheckResult{
+			ID:          CheckPolicyConflicts,
+			Category:    CategoryPolicy,
+			Status:      StatusWarn,
+			Severity:    SeverityLow,
+			Message:     fmt.Sprintf("%d Allow statements are overridden by Deny statements", len(conflicts)),
+			Remediation: "Deny always wins; narrow the Allow statements so the policy reads as it behaves",
+		}
+		for _, conflict := range conflicts {
+			result.Evidence = append(result.Evidence, fmt.Sprintf("Statement %s (Allow) overlaps statement %s (Deny)",
+				conflict.Allow.name(), conflict.Deny.name()))
+		}
+		results = append(results, result)
+	}
+
+	var conditions []string
+	for _, statement := range document.Statement {
+		if keys := statement.ConditionKeys(); len(keys) > 0 {
+			conditions = append(conditions, fmt.Sprintf("Statement %s (%s): %s",
+				statement.name(), statement.Effect, strings.Join(keys, ", ")))
+		}
+	}
+	if len(conditions) > 0 {
+		results = append(results, CheckResult{
+			ID:       CheckPolicyConditions,
+			Category: CategoryPolicy,
+			Status:   StatusPass,
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("%d statements are restricted by conditions", len(conditions)),
+			Evidence: conditions,
+		})
 	}
+
+	return results
 }
 
-func checkBucketPolicyConfig(ctx context.Context, client *minio.Client, bucketName string) {
-	policy, err := client.GetBucketPolicy(ctx, bucketName)
-	if err != nil {
-		fmt.Println("➖ Bucket Policy: Not configured")
-		return
+// evaluateAnonymousAccess reports the statements granting anonymous access and the
+// access that remains effective per prefix once anonymous Deny statements apply. The
+// status follows the effective access; statements fully overridden by Deny statements
+// are reported for information only.
+func evaluateAnonymousAccess(bucketName string, document *PolicyDocument) []CheckResult {
+	anonymous := CheckResult{
+		ID:       CheckPolicyAnonymousAccess,
+		Category: CategoryPolicy,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "No statement grants anonymous access",
 	}
 
-	fmt.Println("✅ Bucket Policy: Configured")
+	public := document.PublicStatements()
+	if len(public) == 0 {
+		return []CheckResult{anonymous}
+	}
 
-	// Basic policy analysis
-	if strings.Contains(policy, `"s3:*"`) {
-		fmt.Println("   ⚠️  Warning: Policy contains wildcard actions - review for security")
+	prefixes := document.PublicAccess(bucketName)
+	granted := make(map[Access]bool)
+	effective := make(map[string]bool)
+	conditional := true
+	for _, prefix := range prefixes {
+		for _, a := range prefix.Access {
+			granted[a] = true
+		}
+		for _, name := range prefix.Statements {
+			effective[name] = true
+		}
+		if !prefix.Conditional {
+			conditional = false
+		}
 	}
-	if strings.Contains(policy, `"Resource": "*"`) {
-		fmt.Println("   ⚠️  Warning: Policy contains wildcard resources - review for security")
+
+	for _, statement := range public {
+		evidence := fmt.Sprintf("%s grants %s", statement.describe(), joinAccess(statement.Access()))
+		if keys := statement.ConditionKeys(); len(keys) > 0 {
+			evidence += fmt.Sprintf(" when %s", strings.Join(keys, ", "))
+		}
+		if !effective[statement.name()] {
+			evidence += " (overridden by Deny)"
+		}
+		anonymous.Evidence = append(anonymous.Evidence, evidence)
+	}
+
+	var accessGranted []Access
+	for _, access := range accessOrder {
+		if granted[access] {
+			accessGranted = append(accessGranted, access)
+		}
+	}
+
+	if len(effective) == 0 {
+		anonymous.Message = fmt.Sprintf("Anonymous grants of %d statements are fully overridden by Deny statements", len(public))
+	} else {
+		anonymous.Status = StatusWarn
+		anonymous.Severity = SeverityHigh
+		anonymous.Message = fmt.Sprintf("Anonymous %s access granted by %d statements", joinAccess(accessGranted), len(effective))
+		anonymous.Remediation = fmt.Sprintf("Remove anonymous statements unless the data is meant to be public: mc anonymous set none <alias>/%s", bucketName)
+		if conditional {
+			anonymous.Severity = SeverityMedium
+		}
+		if granted[AccessWrite] || granted[AccessManage] {
+			anonymous.Status = StatusFail
+			anonymous.Severity = SeverityHigh
+		}
+	}
+
+	exposure := CheckResult{
+		ID:       CheckPolicyPublicPrefixes,
+		Category: CategoryPolicy,
+		Status:   StatusPass,
+		Severity: SeverityInfo,
+		Message:  "Anonymous grants are fully overridden by Deny statements",
+	}
+
+	unconditional := false
+	for _, prefix := range prefixes {
+		name := prefix.Prefix
+		if name == "" {
+			name = "(entire bucket)"
+		}
+		line := fmt.Sprintf("%s: %s via %s", name, joinAccess(prefix.Access), strings.Join(prefix.Statements, ", "))
+		if prefix.Conditional {
+			line += " (conditional)"
+		} else {
+			unconditional = true
+		}
+		exposure.Evidence = append(exposure.Evidence, line)
 	}
-}
\ No newline at end of file
+
+	if len(prefixes) > 0 {
+		exposure.Status = StatusWarn
+		exposure.Severity = SeverityMedium
+		exposure.Message = fmt.Sprintf("%d prefixes are effectively public", len(prefixes))
+		if unconditional {
+			exposure.Severity = SeverityHigh
+		}
+	}
+
+	return []CheckResult{anonymous, exposure}
+}
+
+// evaluateWildcards reports Allow statements granting every action or every resource
+func evaluateWildcards(document *PolicyDocument) []CheckResult {
+	var results []CheckResult
+	var actions, resources []string
+
+	for _, statement := range document.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		for _, action := range statement.Actions() {
+			if action == "*" || strings.EqualFold(action, "s3:*") {
+				actions = append(actions, statement.describe())
+				break
+			}
+		}
+		for _, resource := range statement.Resources() {
+			if resource == "*" || resource == "arn:aws:s3:::*" {
+				resources = append(resources, statement.describe())
+				break
+			}
+		}
+	}
+
+	if len(actions) > 0 {
+		results = append(results, CheckResult{
+			ID:          CheckPolicyWildcardActions,
+			Category:    CategoryPolicy,
+			Status:      StatusWarn,
+			Severity:    SeverityHigh,
+			Message:     "Policy contains wildcard actions",
+			Remediation: "Review the policy and grant only the actions clients need",
+			Evidence:    actions,
+		})
+	}
+	if len(resources) > 0 {
+		results = append(results, CheckResult{
+			ID:          CheckPolicyWildcardResources,
+			Category:    CategoryPolicy,
+			Status:      StatusWarn,
+			Severity:    SeverityHigh,
+			Message:     "Policy contains wildcard resources",
+			Remediation: "Review the policy and scope resources to specific prefixes",
+			Evidence:    resources,
+		})
+	}
+
+	return results
+}
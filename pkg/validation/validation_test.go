@@ -4,7 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/minio/minio-go/v7/pkg/sse"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock MinIO client for testing validation functions
@@ -73,4 +78,145 @@ func TestValidationPackageStructure(t *testing.T) {
 // - TestCheckNotificationConfig  
 // - TestCheckLifecycleConfig
 // - TestCheckEncryptionConfig
-// - TestCheckBucketPolicyConfig
\ No newline at end of file
+// - TestCheckBucketPolicyConfig
+
+func TestEvaluateVersioning(t *testing.T) {
+	result := evaluateVersioning("data", minio.BucketVersioningConfiguration{Status: "Enabled"})
+	assert.Equal(t, StatusPass, result.Status)
+
+	result = evaluateVersioning("data", minio.BucketVersioningConfiguration{Status: "Suspended"})
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, SeverityMedium, result.Severity)
+	assert.Contains(t, result.Remediation, "mc version enable <alias>/data")
+}
+
+func TestEvaluateNotification(t *testing.T) {
+	result := evaluateNotification(notification.Configuration{})
+	assert.Equal(t, StatusSkip, result.Status)
+
+	config := notification.Configuration{
+		QueueConfigs: []notification.QueueConfig{{}, {}},
+	}
+	result = evaluateNotification(config)
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "2 configurations found", result.Message)
+	assert.Equal(t, []string{"Queue configurations: 2"}, result.Evidence)
+}
+
+func TestEvaluateLifecycle(t *testing.T) {
+	results := evaluateLifecycle(nil)
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusSkip, results[0].Status)
+	assert.Equal(t, CheckLifecycleAbortUploads, results[1].ID)
+	assert.Equal(t, StatusWarn, results[1].Status)
+
+	config := &lifecycle.Configuration{Rules: []lifecycle.Rule{
+		{ID: "expire", Status: "Enabled"},
+		{ID: "abort", Status: "Enabled", AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{DaysAfterInitiation: 7}},
+	}}
+	results = evaluateLifecycle(config)
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusPass, results[0].Status)
+	assert.Equal(t, []string{"Rule 'expire': Enabled", "Rule 'abort': Enabled"}, results[0].Evidence)
+	assert.Equal(t, StatusPass, results[1].Status)
+	assert.Empty(t, results[1].Remediation)
+}
+
+func TestEvaluateEncryption(t *testing.T) {
+	result := evaluateEncryption("data", nil)
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, SeverityHigh, result.Severity)
+
+	result = evaluateEncryption("data", sse.NewConfigurationSSES3())
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "AES256 configured", result.Message)
+
+	result = evaluateEncryption("data", sse.NewConfigurationSSEKMS("minio-key"))
+	assert.Equal(t, "aws:kms configured with KMS key minio-key", result.Message)
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	results := evaluatePolicy("data", "")
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSkip, results[0].Status)
+
+	results = evaluatePolicy("data", "{not json")
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusFail, results[0].Status)
+
+	// Formatting variants that substring matching used to miss
+	results = evaluatePolicy("data", `{"Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::1:user/app"]},"Action":"S3:*","Resource":["arn:aws:s3:::*"]}]}`)
+	byID := resultsByID(results)
+	assert.Equal(t, StatusPass, byID[CheckPolicyAnonymousAccess].Status)
+	assert.Equal(t, StatusWarn, byID[CheckPolicyWildcardActions].Status)
+	assert.Equal(t, StatusWarn, byID[CheckPolicyWildcardResources].Status)
+	assert.NotContains(t, byID, CheckPolicyPublicPrefixes)
+}
+
+func TestEvaluatePolicyAnonymousAccess(t *testing.T) {
+	policy := `{"Statement": [
+		{"Sid": "PublicRead", "Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/public/*"},
+		{"Sid": "PublicList", "Effect": "Allow", "Principal": "*", "Action": "s3:ListBucket", "Resource": "arn:aws:s3:::data",
+			"Condition": {"StringLike": {"s3:prefix": "public/*"}}},
+		{"Sid": "DenyPrivate", "Effect": "Deny", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/public/private/*"}
+	]}`
+
+	byID := resultsByID(evaluatePolicy("data", policy))
+
+	anonymous := byID[CheckPolicyAnonymousAccess]
+	assert.Equal(t, StatusWarn, anonymous.Status)
+	assert.Equal(t, SeverityHigh, anonymous.Severity)
+	assert.Equal(t, "Anonymous read, list access granted by 2 statements", anonymous.Message)
+	assert.Equal(t, []string{
+		"Statement PublicRead: Allow [s3:GetObject] on [arn:aws:s3:::data/public/*] grants read",
+		"Statement PublicList: Allow [s3:ListBucket] on [arn:aws:s3:::data] grants list when StringLike:s3:prefix",
+	}, anonymous.Evidence)
+
+	prefixes := byID[CheckPolicyPublicPrefixes]
+	assert.Equal(t, StatusWarn, prefixes.Status)
+	assert.Equal(t, []string{
+		"(entire bucket): list via PublicList (conditional)",
+		"public/: read via PublicRead",
+	}, prefixes.Evidence)
+
+	conflicts := byID[CheckPolicyConflicts]
+	assert.Equal(t, StatusWarn, conflicts.Status)
+	assert.Equal(t, []string{"Statement PublicRead (Allow) overlaps statement DenyPrivate (Deny)"}, conflicts.Evidence)
+
+	assert.Equal(t, []string{"Statement PublicList (Allow): StringLike:s3:prefix"}, byID[CheckPolicyConditions].Evidence)
+}
+
+func TestEvaluatePolicyAnonymousWrite(t *testing.T) {
+	policy := `{"Statement": [
+		{"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": ["s3:PutObject"], "Resource": ["arn:aws:s3:::data/uploads/*"]},
+		{"Effect": "Deny", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::data/*"}
+	]}`
+
+	byID := resultsByID(evaluatePolicy("data", policy))
+
+	// The grant is overridden, so it is reported for information only
+	anonymous := byID[CheckPolicyAnonymousAccess]
+	assert.Equal(t, StatusPass, anonymous.Status)
+	assert.Equal(t, SeverityInfo, anonymous.Severity)
+	assert.Equal(t, "Anonymous grants of 1 statements are fully overridden by Deny statements", anonymous.Message)
+	assert.Equal(t, []string{
+		"Statement #1: Allow [s3:PutObject] on [arn:aws:s3:::data/uploads/*] grants write (overridden by Deny)",
+	}, anonymous.Evidence)
+
+	// The blanket Deny removes the effective exposure
+	assert.Equal(t, StatusPass, byID[CheckPolicyPublicPrefixes].Status)
+	assert.Empty(t, byID[CheckPolicyPublicPrefixes].Evidence)
+}
+
+func TestEvaluatePolicyAnonymousWritePartlyDenied(t *testing.T) {
+	policy := `{"Statement": [
+		{"Sid": "Public", "Effect": "Allow", "Principal": "*", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": "arn:aws:s3:::data/*"},
+		{"Sid": "NoWrites", "Effect": "Deny", "Principal": "*", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::data/*"}
+	]}`
+
+	// Only the effective read access counts toward the status
+	anonymous := resultsByID(evaluatePolicy("data", policy))[CheckPolicyAnonymousAccess]
+	assert.Equal(t, StatusWarn, anonymous.Status)
+	assert.Equal(t, SeverityHigh, anonymous.Severity)
+	assert.Equal(t, "Anonymous read access granted by 1 statements", anonymous.Message)
+}